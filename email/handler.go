@@ -2,9 +2,11 @@ package email
 
 import (
 	"context"
+	"errors"
 
 	"github.com/titpetric/platform"
 
+	"github.com/titpetric/platform-app/email/model"
 	"github.com/titpetric/platform-app/email/schema"
 	"github.com/titpetric/platform-app/email/storage"
 )
@@ -52,6 +54,21 @@ func (h *Handler) Stop(ctx context.Context) error {
 	return nil
 }
 
+// AddEmail queues an email with the email service. Other modules can
+// use the handler to send email once it is started.
+func (h *Handler) AddEmail(ctx context.Context, email *model.Email) error {
+	if h.service == nil {
+		return errors.New("email service not started")
+	}
+	return h.service.AddEmail(ctx, email)
+}
+
+// Send queues a plain email to recipient. It satisfies the EmailSender
+// contract of the user module.
+func (h *Handler) Send(ctx context.Context, recipient, subject, body string) error {
+	return h.AddEmail(ctx, model.NewEmail(recipient, subject, body))
+}
+
 // Name returns the name of the containing package.
 func (h *Handler) Name() string {
 	return "email"
@@ -13,9 +13,11 @@ import (
 	"time"
 )
 
-// TokenData holds authentication token data with expiration info.
+// TokenData holds authentication token data with expiration info. A
+// device key has a Hostname, it is bound to that host and doesn't expire.
 type TokenData struct {
 	Token     string    `json:"token"`
+	Hostname  string    `json:"hostname,omitempty"`
 	ExpiresAt time.Time `json:"expires_at"`
 	SavedAt   time.Time `json:"saved_at"`
 }
@@ -72,6 +74,24 @@ func (c *Client) LoadToken() error {
 
 // SaveToken persists the authentication token to disk.
 func (c *Client) SaveToken(token string, expiresAt time.Time) error {
+	return c.saveTokenData(TokenData{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		SavedAt:   time.Now(),
+	})
+}
+
+// SaveDeviceKey persists a device key bound to hostname to disk, in
+// place of a user token.
+func (c *Client) SaveDeviceKey(key, hostname string) error {
+	return c.saveTokenData(TokenData{
+		Token:    key,
+		Hostname: hostname,
+		SavedAt:  time.Now(),
+	})
+}
+
+func (c *Client) saveTokenData(data TokenData) error {
 	path, err := configPath()
 	if err != nil {
 		return err
@@ -81,12 +101,6 @@ func (c *Client) SaveToken(token string, expiresAt time.Time) error {
 		return fmt.Errorf("create config dir: %w", err)
 	}
 
-	data := TokenData{
-		Token:     token,
-		ExpiresAt: expiresAt,
-		SavedAt:   time.Now(),
-	}
-
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal token: %w", err)
@@ -100,11 +114,15 @@ func (c *Client) SaveToken(token string, expiresAt time.Time) error {
 	return nil
 }
 
-// ShouldRefresh reports whether the token needs refreshing.
+// ShouldRefresh reports whether the token needs refreshing. Device keys
+// are never refreshed.
 func (c *Client) ShouldRefresh() bool {
 	if c.token == nil {
 		return true
 	}
+	if c.IsDeviceKey() {
+		return false
+	}
 	if time.Now().After(c.token.ExpiresAt) {
 		return true
 	}
@@ -162,23 +180,78 @@ func (c *Client) EnsureToken() error {
 	return nil
 }
 
-// SendPulse submits a keystroke count to the server.
-func (c *Client) SendPulse(count int64, hostname string) error {
-	if c.token == nil {
-		return errors.New("not authenticated")
+// CreateDeviceKey creates a device key bound to hostname, authenticated
+// with a user token, and returns the key.
+func (c *Client) CreateDeviceKey(userToken, hostname string) (string, error) {
+	payload := struct {
+		Hostname string `json:"hostname"`
+	}{Hostname: hostname}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.ServerURL+"/api/pulse/keys", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+userToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("create device key request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("create device key failed (status %d): %s", resp.StatusCode, string(respBody))
 	}
 
+	var result struct {
+		Key string `json:"key"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	return result.Key, nil
+}
+
+// SendPulse submits a keystroke count to the server.
+func (c *Client) SendPulse(count int64, hostname string) error {
 	payload := struct {
 		Count    int64  `json:"count"`
 		Hostname string `json:"hostname"`
 	}{Count: count, Hostname: hostname}
 
+	return c.post("/api/pulse/ingest", payload)
+}
+
+// SendBatch submits timestamped keystroke counts to the server.
+// The server applies the whole batch or none of it, and applies a
+// batch ID at most once, so a failed send can be retried safely.
+func (c *Client) SendBatch(id string, buckets []Bucket) error {
+	payload := struct {
+		ID      string   `json:"id"`
+		Entries []Bucket `json:"entries"`
+	}{ID: id, Entries: buckets}
+
+	return c.post("/api/pulse/ingest/batch", payload)
+}
+
+func (c *Client) post(path string, payload any) error {
+	if c.token == nil {
+		return errors.New("not authenticated")
+	}
+
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.ServerURL+"/api/pulse/ingest", bytes.NewReader(body))
+	req, err := http.NewRequest("POST", c.ServerURL+path, bytes.NewReader(body))
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
@@ -193,12 +266,39 @@ func (c *Client) SendPulse(count int64, hostname string) error {
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("send failed (status %d): %s", resp.StatusCode, string(respBody))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	return nil
 }
 
+// StatusError is returned for a request the server responded to with
+// an error status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error returns the status code and the response body.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("send failed (status %d): %s", e.StatusCode, e.Body)
+}
+
+// Rejected reports whether err is a request the server rejected for
+// good, so sending it again fails the same way. Client errors are
+// rejected, except an expired login, a timeout and rate limiting.
+func Rejected(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	switch statusErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return statusErr.StatusCode >= 400 && statusErr.StatusCode < 500
+}
+
 // Token returns the current authentication token string.
 func (c *Client) Token() string {
 	if c.token == nil {
@@ -206,3 +306,17 @@ func (c *Client) Token() string {
 	}
 	return c.token.Token
 }
+
+// IsDeviceKey reports whether the current token is a device key.
+func (c *Client) IsDeviceKey() bool {
+	return c.token != nil && c.token.Hostname != ""
+}
+
+// Hostname returns the host a device key is bound to, or an empty string
+// for user tokens.
+func (c *Client) Hostname() string {
+	if c.token == nil {
+		return ""
+	}
+	return c.token.Hostname
+}
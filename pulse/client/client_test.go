@@ -75,6 +75,15 @@ func TestShouldRefresh(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "device key",
+			token: &TokenData{
+				Token:    "pulse_dk_key",
+				Hostname: "laptop",
+				SavedAt:  time.Now().Add(-30 * 24 * time.Hour),
+			},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -85,3 +94,20 @@ func TestShouldRefresh(t *testing.T) {
 		})
 	}
 }
+
+func TestSaveDeviceKey(t *testing.T) {
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	t.Cleanup(func() { os.Setenv("HOME", origHome) })
+	os.Setenv("HOME", tmpDir)
+
+	c := New("http://localhost:8080")
+	require.NoError(t, c.SaveDeviceKey("pulse_dk_key", "laptop"))
+
+	c2 := New("http://localhost:8080")
+	require.NoError(t, c2.LoadToken())
+	assert.True(t, c2.IsDeviceKey())
+	assert.Equal(t, "laptop", c2.Hostname())
+	assert.Equal(t, "pulse_dk_key", c2.Token())
+	assert.False(t, c2.ShouldRefresh())
+}
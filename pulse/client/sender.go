@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// MaxBatchSize is the number of buckets sent in a single request.
+const MaxBatchSize = 500
+
+// Sender drains a spool to the server in the background, backing off
+// exponentially while the server is unreachable.
+type Sender struct {
+	client *Client
+	spool  *Spool
+	notify chan struct{}
+
+	// Interval is how often the spool is checked without a notification.
+	Interval time.Duration
+	// MinBackoff is the delay after the first failed send.
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between failed sends.
+	MaxBackoff time.Duration
+}
+
+// NewSender creates a sender for the spool using client c.
+func NewSender(c *Client, spool *Spool) *Sender {
+	return &Sender{
+		client:     c,
+		spool:      spool,
+		notify:     make(chan struct{}, 1),
+		Interval:   time.Minute,
+		MinBackoff: 5 * time.Second,
+		MaxBackoff: 10 * time.Minute,
+	}
+}
+
+// Notify wakes the sender to drain the spool. It never blocks.
+func (s *Sender) Notify() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run drains the spool until the context is cancelled.
+func (s *Sender) Run(ctx context.Context) {
+	var backoff time.Duration
+
+	for {
+		wait := s.Interval
+		if err := s.Drain(); err != nil {
+			if backoff == 0 {
+				backoff = s.MinBackoff
+			} else {
+				backoff = min(backoff*2, s.MaxBackoff)
+			}
+			wait = backoff
+			log.Printf("send failed, %d bucket(s) spooled, retrying in %s: %v", s.spool.Len(), wait, err)
+		} else {
+			backoff = 0
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.notify:
+			timer.Stop()
+			if backoff > 0 {
+				// Keep backing off; new data is already safely spooled.
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(backoff):
+				}
+			}
+		case <-timer.C:
+		}
+	}
+}
+
+// Drain sends all spooled batches and acknowledges them in the spool.
+// Batches the server rejects for good, see Rejected, are dropped and
+// logged, so they don't hold up the batches after them. Other errors
+// leave the batch spooled, to be sent again.
+func (s *Sender) Drain() error {
+	if s.spool.Len() == 0 {
+		return nil
+	}
+
+	if s.client.ShouldRefresh() {
+		if err := s.client.RefreshToken(); err != nil {
+			log.Printf("token refresh failed: %v", err)
+		}
+	}
+
+	for {
+		batch, err := s.spool.Next(MaxBatchSize)
+		if err != nil || batch == nil {
+			return err
+		}
+
+		sendErr := s.client.SendBatch(batch.ID, batch.Buckets)
+		if sendErr != nil && !Rejected(sendErr) {
+			return sendErr
+		}
+		if err := s.spool.Ack(batch.ID); err != nil {
+			return err
+		}
+
+		var count int64
+		for _, b := range batch.Buckets {
+			count += b.Count
+		}
+		if sendErr != nil {
+			log.Printf("dropped %d keystroke(s) in %d bucket(s), rejected by the server: %v", count, len(batch.Buckets), sendErr)
+			continue
+		}
+		log.Printf("sent %d keystroke(s) in %d bucket(s)", count, len(batch.Buckets))
+	}
+}
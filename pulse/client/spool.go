@@ -0,0 +1,176 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/titpetric/platform/pkg/ulid"
+)
+
+// Bucket holds a keystroke count recorded at a point in time. Categories
+// breaks the count down by key category.
+type Bucket struct {
+	Hostname   string           `json:"hostname"`
+	Stamp      time.Time        `json:"stamp"`
+	Count      int64            `json:"count"`
+	Categories map[string]int64 `json:"categories,omitempty"`
+}
+
+// Batch is a set of buckets sealed under an ID for sending. The ID stays
+// the same across retries, so the server can drop replays.
+type Batch struct {
+	ID      string   `json:"id"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+// Spool is a durable on-disk queue of buckets waiting to be sent.
+// Pending buckets with the same hostname and stamp are merged.
+type Spool struct {
+	mu      sync.Mutex
+	path    string
+	buckets []Bucket
+	batch   *Batch
+}
+
+// spoolFile is the on-disk representation of the spool.
+type spoolFile struct {
+	Buckets []Bucket `json:"buckets"`
+	Batch   *Batch   `json:"batch,omitempty"`
+}
+
+// SpoolPath returns the default spool location, next to the token file.
+func SpoolPath() (string, error) {
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "spool.json"), nil
+}
+
+// OpenSpool loads the spool from path. A missing file yields an empty spool.
+func OpenSpool(path string) (*Spool, error) {
+	s := &Spool{
+		path: path,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("read spool file: %w", err)
+	}
+
+	var file spoolFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse spool file: %w", err)
+	}
+	s.buckets, s.batch = file.Buckets, file.Batch
+	return s, nil
+}
+
+// Add stores a bucket in the spool and persists it to disk.
+func (s *Spool) Add(b Bucket) error {
+	if b.Count <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.buckets {
+		if existing.Hostname == b.Hostname && existing.Stamp.Equal(b.Stamp) {
+			s.buckets[i].Count += b.Count
+			for category, n := range b.Categories {
+				if s.buckets[i].Categories == nil {
+					s.buckets[i].Categories = make(map[string]int64)
+				}
+				s.buckets[i].Categories[category] += n
+			}
+			return s.save()
+		}
+	}
+
+	s.buckets = append(s.buckets, b)
+	return s.save()
+}
+
+// Next returns the batch to send. A batch that wasn't acknowledged is
+// returned again, otherwise up to max pending buckets are sealed into a
+// new batch. It returns nil when the spool is empty.
+func (s *Spool) Next(max int) (*Batch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.batch != nil {
+		return s.batch, nil
+	}
+	if len(s.buckets) == 0 {
+		return nil, nil
+	}
+
+	n := min(len(s.buckets), max)
+	s.batch = &Batch{
+		ID:      ulid.String(),
+		Buckets: append([]Bucket(nil), s.buckets[:n]...),
+	}
+	s.buckets = append([]Bucket(nil), s.buckets[n:]...)
+
+	if err := s.save(); err != nil {
+		return nil, err
+	}
+	return s.batch, nil
+}
+
+// Ack removes the sent batch from the spool.
+func (s *Spool) Ack(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.batch == nil || s.batch.ID != id {
+		return nil
+	}
+	s.batch = nil
+	return s.save()
+}
+
+// Len returns the number of buckets in the spool.
+func (s *Spool) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.buckets)
+	if s.batch != nil {
+		n += len(s.batch.Buckets)
+	}
+	return n
+}
+
+// save writes the spool to a temporary file and renames it into place,
+// so a crash mid-write never leaves a truncated spool behind.
+func (s *Spool) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("create spool dir: %w", err)
+	}
+
+	jsonData, err := json.Marshal(spoolFile{
+		Buckets: s.buckets,
+		Batch:   s.batch,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal spool: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, jsonData, 0o600); err != nil {
+		return fmt.Errorf("write spool file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("rename spool file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpool(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool.json")
+	stamp := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	s, err := OpenSpool(path)
+	require.NoError(t, err)
+	assert.Equal(t, 0, s.Len())
+
+	require.NoError(t, s.Add(Bucket{Hostname: "laptop", Stamp: stamp, Count: 10, Categories: map[string]int64{"alphanumeric": 8, "delete": 2}}))
+	require.NoError(t, s.Add(Bucket{Hostname: "laptop", Stamp: stamp, Count: 5, Categories: map[string]int64{"alphanumeric": 5}}))
+	require.NoError(t, s.Add(Bucket{Hostname: "desktop", Stamp: stamp, Count: 3}))
+	require.NoError(t, s.Add(Bucket{Hostname: "desktop", Stamp: stamp, Count: 0}))
+	assert.Equal(t, 2, s.Len())
+
+	// Reopening the spool restores pending buckets.
+	s2, err := OpenSpool(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, s2.Len())
+
+	batch, err := s2.Next(1)
+	require.NoError(t, err)
+	require.NotNil(t, batch)
+	require.Len(t, batch.Buckets, 1)
+	assert.Equal(t, int64(15), batch.Buckets[0].Count)
+	assert.Equal(t, map[string]int64{"alphanumeric": 13, "delete": 2}, batch.Buckets[0].Categories)
+
+	// An unacknowledged batch is returned again with the same ID,
+	// also after a restart.
+	require.NoError(t, s2.Add(Bucket{Hostname: "laptop", Stamp: stamp, Count: 2}))
+
+	s3, err := OpenSpool(path)
+	require.NoError(t, err)
+	retry, err := s3.Next(1)
+	require.NoError(t, err)
+	assert.Equal(t, batch.ID, retry.ID)
+	assert.Equal(t, int64(15), retry.Buckets[0].Count)
+
+	require.NoError(t, s3.Ack(retry.ID))
+
+	next, err := s3.Next(10)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.NotEqual(t, batch.ID, next.ID)
+	assert.Len(t, next.Buckets, 2)
+
+	require.NoError(t, s3.Ack(next.ID))
+	assert.Equal(t, 0, s3.Len())
+
+	empty, err := s3.Next(10)
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+}
+
+func TestSenderDrain(t *testing.T) {
+	fail := true
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		received = append(received, body.ID)
+
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	c.token = &TokenData{Token: "test", ExpiresAt: time.Now().Add(time.Hour), SavedAt: time.Now()}
+
+	s, err := OpenSpool(filepath.Join(t.TempDir(), "spool.json"))
+	require.NoError(t, err)
+	require.NoError(t, s.Add(Bucket{Hostname: "laptop", Stamp: time.Now(), Count: 10}))
+
+	sender := NewSender(c, s)
+
+	assert.Error(t, sender.Drain())
+	assert.Equal(t, 1, s.Len())
+
+	fail = false
+	assert.NoError(t, sender.Drain())
+	assert.Equal(t, 0, s.Len())
+	// The retry carries the same batch ID.
+	require.Len(t, received, 2)
+	assert.Equal(t, received[0], received[1])
+}
+
+func TestSenderDrainRejected(t *testing.T) {
+	status := http.StatusBadRequest
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		received = append(received, body.ID)
+
+		w.WriteHeader(status)
+		status = http.StatusNoContent
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	c.token = &TokenData{Token: "test", ExpiresAt: time.Now().Add(time.Hour), SavedAt: time.Now()}
+
+	s, err := OpenSpool(filepath.Join(t.TempDir(), "spool.json"))
+	require.NoError(t, err)
+	require.NoError(t, s.Add(Bucket{Hostname: "laptop", Stamp: time.Now(), Count: 10}))
+
+	sender := NewSender(c, s)
+
+	// The rejected batch is dropped instead of being sent forever.
+	require.NoError(t, sender.Drain())
+	assert.Equal(t, 0, s.Len())
+	require.Len(t, received, 1)
+
+	// Later batches are sent.
+	require.NoError(t, s.Add(Bucket{Hostname: "laptop", Stamp: time.Now(), Count: 5}))
+	require.NoError(t, sender.Drain())
+	assert.Equal(t, 0, s.Len())
+	require.Len(t, received, 2)
+	assert.NotEqual(t, received[0], received[1])
+}
+
+func TestRejected(t *testing.T) {
+	assert.True(t, Rejected(&StatusError{StatusCode: http.StatusBadRequest}))
+	assert.True(t, Rejected(&StatusError{StatusCode: http.StatusForbidden}))
+	assert.False(t, Rejected(&StatusError{StatusCode: http.StatusUnauthorized}))
+	assert.False(t, Rejected(&StatusError{StatusCode: http.StatusTooManyRequests}))
+	assert.False(t, Rejected(&StatusError{StatusCode: http.StatusServiceUnavailable}))
+	assert.False(t, Rejected(errors.New("connection refused")))
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// HostStats holds keystroke totals of a host.
+type HostStats struct {
+	Hostname string `json:"hostname"`
+	Today    int64  `json:"today"`
+	Week     int64  `json:"week"`
+	Total    int64  `json:"total"`
+	// Daily lists the keystrokes per day, oldest first.
+	Daily []int64 `json:"daily"`
+}
+
+// Stats holds keystroke totals for today, this week and the last 30 days,
+// overall and per host.
+type Stats struct {
+	From     string      `json:"from"`
+	Timezone string      `json:"timezone"`
+	Today    int64       `json:"today"`
+	Week     int64       `json:"week"`
+	Total    int64       `json:"total"`
+	Hosts    []HostStats `json:"hosts"`
+}
+
+// ExportOptions selects the pulse history to export. Empty fields use
+// the server defaults: daily rows as JSON for the last 30 days.
+type ExportOptions struct {
+	From       string
+	To         string
+	Format     string
+	Resolution string
+	Hostname   string
+}
+
+// Stats fetches the keystroke totals of the logged in user.
+func (c *Client) Stats() (*Stats, error) {
+	resp, err := c.get("/api/pulse/stats", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var stats Stats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return &stats, nil
+}
+
+// Export downloads the pulse history of the logged in user to w.
+func (c *Client) Export(w io.Writer, opts ExportOptions) error {
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"from":       opts.From,
+		"to":         opts.To,
+		"format":     opts.Format,
+		"resolution": opts.Resolution,
+		"host":       opts.Hostname,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	resp, err := c.get("/api/pulse/export", query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	return nil
+}
+
+// get sends an authenticated GET request. Responses other than 200 OK are
+// returned as errors.
+func (c *Client) get(path string, query url.Values) (*http.Response, error) {
+	if c.token == nil {
+		return nil, errors.New("not authenticated")
+	}
+	if c.IsDeviceKey() {
+		return nil, errors.New("device keys can only record, run 'pulse login --device-key=false' to read your data")
+	}
+
+	target := c.ServerURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token.Token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return resp, nil
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsAndExport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer user-token" {
+			http.Error(w, "login required", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/pulse/stats":
+			w.Write([]byte(`{"from":"2026-02-11","today":10,"week":30,"total":630,"hosts":[{"hostname":"laptop","today":10,"week":30,"total":630,"daily":[100,0,10]}]}`))
+		case "/api/pulse/export":
+			w.Write([]byte(r.URL.RawQuery))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+	c.token = &TokenData{Token: "user-token"}
+
+	stats, err := c.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, int64(630), stats.Total)
+	require.Len(t, stats.Hosts, 1)
+	assert.Equal(t, []int64{100, 0, 10}, stats.Hosts[0].Daily)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.Export(&buf, ExportOptions{From: "2026-01-01", Format: "csv"}))
+	assert.Equal(t, "format=csv&from=2026-01-01", buf.String())
+
+	c.token = &TokenData{Token: "expired"}
+	_, err = c.Stats()
+	assert.ErrorContains(t, err, "status 401")
+
+	c.token = &TokenData{Token: "pulse_dk_key", Hostname: "laptop"}
+	_, err = c.Stats()
+	assert.ErrorContains(t, err, "device keys can only record")
+}
@@ -0,0 +1,255 @@
+// Package admin implements the pulse data maintenance commands.
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/titpetric/cli"
+
+	"github.com/titpetric/platform-app/pulse/schema"
+	"github.com/titpetric/platform-app/pulse/storage"
+	userstorage "github.com/titpetric/platform-app/user/storage"
+)
+
+// Name is the command title.
+const Name = "Maintain pulse data (rebuild-daily, orphans, delete-user, export, import)"
+
+// usage lists the admin commands.
+const usage = `usage: pulse admin <command>
+
+commands:
+  rebuild-daily [--user ID]   recompute daily rows from hourly rows
+  orphans [--delete --yes]    list (or delete) data of users that no longer exist
+  delete-user --yes USER      delete all data of a user, by username or ID
+  export [--file FILE]        write all data as NDJSON (default stdout)
+  import [--file FILE]        read data written by export (default stdin)`
+
+// Options holds admin command configuration.
+type Options struct {
+	User   string
+	Delete bool
+	Yes    bool
+	File   string
+}
+
+// Bind registers admin flags with the flag set.
+func (o *Options) Bind(flag *cli.FlagSet) {
+	flag.StringVar(&o.User, "user", "", "Only rebuild the daily rows of this user ID")
+	flag.BoolVar(&o.Delete, "delete", false, "Delete the data of orphaned users")
+	flag.BoolVar(&o.Yes, "yes", false, "Confirm deleting data")
+	flag.StringVar(&o.File, "file", "", "File to export to or import from")
+}
+
+// NewCommand creates a new admin command.
+func NewCommand() *cli.Command {
+	var opts Options
+
+	return &cli.Command{
+		Name:  "admin",
+		Title: Name,
+		Bind:  opts.Bind,
+		Run: func(ctx context.Context, args []string) error {
+			return Run(ctx, opts, args)
+		},
+	}
+}
+
+// Run runs the admin command given in args against the pulse database,
+// PLATFORM_DB_PULSE. Commands that look up users also use the user
+// database, PLATFORM_DB_USER.
+func Run(ctx context.Context, opts Options, args []string) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	db, err := storage.DB(ctx)
+	if err != nil {
+		return err
+	}
+	if err := storage.Migrate(ctx, db, schema.Migrations()); err != nil {
+		return err
+	}
+	s := storage.NewStorage(db)
+
+	switch args[0] {
+	case "rebuild-daily":
+		return rebuildDaily(ctx, s, opts)
+	case "orphans":
+		return orphans(ctx, s, opts)
+	case "delete-user":
+		if len(args) != 2 {
+			return errors.New("usage: pulse admin delete-user --yes USER")
+		}
+		return deleteUser(ctx, s, opts, args[1])
+	case "export":
+		return exportData(ctx, s, opts)
+	case "import":
+		return importData(ctx, s, opts)
+	}
+	return fmt.Errorf("unknown command: %s\n\n%s", args[0], usage)
+}
+
+// openUserStorage connects to the user database.
+func openUserStorage(ctx context.Context) (*userstorage.UserStorage, error) {
+	db, err := userstorage.DB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return userstorage.NewUserStorage(db), nil
+}
+
+func rebuildDaily(ctx context.Context, s *storage.Storage, opts Options) error {
+	userStorage, err := openUserStorage(ctx)
+	if err != nil {
+		return err
+	}
+
+	userIDs := []string{opts.User}
+	if opts.User == "" {
+		userIDs, err = s.ListDataUsers(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	var total int64
+	for _, userID := range userIDs {
+		// Days are taken in the user timezone, as on ingest.
+		u, err := userStorage.Get(ctx, userID)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			fmt.Printf("%s: user not found, skipped\n", userID)
+			continue
+		}
+
+		changed, err := s.RebuildDaily(ctx, userID, u.Location())
+		if err != nil {
+			return err
+		}
+		if changed > 0 {
+			fmt.Printf("%s: %d daily rows changed\n", userID, changed)
+		}
+		total += changed
+	}
+
+	fmt.Printf("rebuilt daily rows for %d users, %d rows changed\n", len(userIDs), total)
+	return nil
+}
+
+func orphans(ctx context.Context, s *storage.Storage, opts Options) error {
+	if opts.Delete && !opts.Yes {
+		return errors.New("pass --yes to delete the data of orphaned users")
+	}
+
+	userStorage, err := openUserStorage(ctx)
+	if err != nil {
+		return err
+	}
+
+	userIDs, err := s.ListDataUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var found int
+	for _, userID := range userIDs {
+		_, err := userStorage.Get(ctx, userID)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		found++
+
+		if !opts.Delete {
+			fmt.Println(userID)
+			continue
+		}
+		deleted, err := s.DeleteUserData(ctx, userID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: deleted %d rows\n", userID, deleted)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d orphaned users\n", found)
+	return nil
+}
+
+func deleteUser(ctx context.Context, s *storage.Storage, opts Options, name string) error {
+	if !opts.Yes {
+		return fmt.Errorf("pass --yes to delete all data of %s", name)
+	}
+
+	// Users are looked up by username first. Data of a user that is
+	// already gone can be deleted by ID.
+	userID := name
+	userStorage, err := openUserStorage(ctx)
+	if err != nil {
+		return err
+	}
+	u, err := userStorage.GetByUsername(ctx, name)
+	switch {
+	case err == nil:
+		userID = u.ID
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+
+	deleted, err := s.DeleteUserData(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: deleted %d rows\n", userID, deleted)
+	return nil
+}
+
+func exportData(ctx context.Context, s *storage.Storage, opts Options) error {
+	var out io.Writer = os.Stdout
+	if opts.File != "" {
+		// Exports hold device key hashes, keep them private.
+		f, err := os.OpenFile(opts.File, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("create export file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	written, err := s.ExportData(ctx, out)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d rows\n", written)
+	return nil
+}
+
+func importData(ctx context.Context, s *storage.Storage, opts Options) error {
+	var in io.Reader = os.Stdin
+	if opts.File != "" && opts.File != "-" {
+		f, err := os.Open(opts.File)
+		if err != nil {
+			return fmt.Errorf("open import file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	imported, err := s.ImportData(ctx, in)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "imported %d rows\n", imported)
+	return nil
+}
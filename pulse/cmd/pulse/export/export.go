@@ -0,0 +1,91 @@
+// Package export implements the pulse history export command.
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/titpetric/cli"
+
+	"github.com/titpetric/platform-app/pulse/client"
+)
+
+// Name is the command title.
+const Name = "Export keystroke history"
+
+// Options holds export command configuration.
+type Options struct {
+	Server     string
+	From       string
+	To         string
+	Format     string
+	Resolution string
+	Host       string
+	Output     string
+}
+
+// Bind registers export flags with the flag set.
+func (o *Options) Bind(flag *cli.FlagSet) {
+	server := "http://localhost:8080"
+	if e := os.Getenv("PULSE_SERVER"); e != "" {
+		server = e
+	}
+
+	flag.StringVar(&o.Server, "server", server, "Pulse server URL")
+	flag.StringVar(&o.From, "from", "", "First day to export, YYYY-MM-DD (default 30 days ago)")
+	flag.StringVar(&o.To, "to", "", "Last day to export, YYYY-MM-DD (default today)")
+	flag.StringVar(&o.Format, "format", "csv", "Output format (csv, json)")
+	flag.StringVar(&o.Resolution, "resolution", "daily", "Row resolution (daily, hourly)")
+	flag.StringVar(&o.Host, "host", "", "Only export this host")
+	flag.StringVar(&o.Output, "output", "", "Output file (default stdout)")
+}
+
+// NewCommand creates a new export command.
+func NewCommand() *cli.Command {
+	var opts Options
+
+	return &cli.Command{
+		Name:  "export",
+		Title: Name,
+		Bind:  opts.Bind,
+		Run: func(ctx context.Context, args []string) error {
+			return Run(ctx, opts)
+		},
+	}
+}
+
+// Run downloads the keystroke history of the logged in user.
+func Run(_ context.Context, opts Options) error {
+	if opts.Format != "csv" && opts.Format != "json" {
+		return fmt.Errorf("invalid format: %q", opts.Format)
+	}
+
+	c := client.New(opts.Server)
+	if err := c.EnsureToken(); err != nil {
+		return fmt.Errorf("authentication required: %w (run 'pulse login' first)", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if opts.Output != "" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			return fmt.Errorf("create output: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	err := c.Export(out, client.ExportOptions{
+		From:       opts.From,
+		To:         opts.To,
+		Format:     opts.Format,
+		Resolution: opts.Resolution,
+		Hostname:   opts.Host,
+	})
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	return nil
+}
@@ -24,9 +24,12 @@ const Name = "Authenticate with pulse server"
 
 // Options holds login command configuration.
 type Options struct {
-	Server   string
-	Email    string
-	Password string
+	Server    string
+	Email     string
+	Password  string
+	Code      string
+	Name      string
+	DeviceKey bool
 }
 
 // Bind registers login flags with the flag set.
@@ -38,6 +41,16 @@ func (o *Options) Bind(flag *cli.FlagSet) {
 	flag.StringVar(&o.Server, "server", defaultServer, "Pulse server URL")
 	flag.StringVar(&o.Email, "email", "", "Email address (optional, will prompt if not provided)")
 	flag.StringVar(&o.Password, "password", "", "Password (optional, will prompt if not provided)")
+	flag.StringVar(&o.Code, "code", "", "Authenticator app or recovery code, if two-factor authentication is enabled (optional, will prompt if needed)")
+	BindDevice(flag, &o.Name, &o.DeviceKey)
+}
+
+// BindDevice registers the device key flags with the flag set.
+func BindDevice(flag *cli.FlagSet, name *string, deviceKey *bool) {
+	hostname, _ := os.Hostname()
+
+	flag.StringVar(name, "name", hostname, "Device name the key is bound to (hostname default)")
+	flag.BoolVar(deviceKey, "device-key", true, "Save a device key that can only record for this device, instead of a user token")
 }
 
 // NewCommand creates a new login command.
@@ -90,43 +103,102 @@ func Run(opts Options) error {
 		Password: password,
 	}
 
+	result, err := post(opts.Server+"/api/user/token/create", payload)
+	if err != nil {
+		return fmt.Errorf("login: %w", err)
+	}
+
+	if result.MFARequired {
+		code := opts.Code
+		if code == "" {
+			fmt.Print("Authentication code: ")
+			code, err = reader.ReadString('\n')
+			if err != nil {
+				return fmt.Errorf("read code: %w", err)
+			}
+			code = strings.TrimSpace(code)
+		}
+
+		result, err = post(opts.Server+"/api/user/mfa/verify", struct {
+			MFAToken string `json:"mfa_token"`
+			Code     string `json:"code"`
+		}{
+			MFAToken: result.MFAToken,
+			Code:     code,
+		})
+		if err != nil {
+			return fmt.Errorf("verify code: %w", err)
+		}
+	}
+
+	if err := SaveCredentials(opts.Server, opts.Name, opts.DeviceKey, result.Token, time.Unix(result.ExpiresAt, 0)); err != nil {
+		return err
+	}
+
+	fmt.Println("Login successful!")
+	return nil
+}
+
+// tokenResponse is the response of the token endpoints. With MFA enabled,
+// creating a token returns an MFA token to verify with a code instead.
+type tokenResponse struct {
+	Token       string `json:"token"`
+	ExpiresAt   int64  `json:"expires_at"`
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+}
+
+// post sends a JSON request to a token endpoint and decodes the response.
+func post(url string, payload any) (*tokenResponse, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("marshal request: %w", err)
+		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", opts.Server+"/api/user/token/create", bytes.NewReader(body))
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	httpClient := &http.Client{Timeout: 30 * time.Second}
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("login request: %w", err)
+		return nil, fmt.Errorf("request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("login failed (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("failed (status %d): %s", resp.StatusCode, string(respBody))
 	}
 
-	var result struct {
-		Token     string `json:"token"`
-		ExpiresAt int64  `json:"expires_at"`
-	}
+	var result tokenResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return fmt.Errorf("decode response: %w", err)
+		return nil, fmt.Errorf("decode response: %w", err)
 	}
+	return &result, nil
+}
 
-	expiresAt := time.Unix(result.ExpiresAt, 0)
-	c := client.New(opts.Server)
-	if err := c.SaveToken(result.Token, expiresAt); err != nil {
-		return fmt.Errorf("save token: %w", err)
+// SaveCredentials saves the user token, or with deviceKey set, uses it
+// to create a device key for name and saves that instead.
+func SaveCredentials(server, name string, deviceKey bool, token string, expiresAt time.Time) error {
+	c := client.New(server)
+	if !deviceKey {
+		if err := c.SaveToken(token, expiresAt); err != nil {
+			return fmt.Errorf("save token: %w", err)
+		}
+		fmt.Println("Token saved.")
+		return nil
 	}
 
-	fmt.Println("Login successful! Token saved.")
+	key, err := c.CreateDeviceKey(token, name)
+	if err != nil {
+		return err
+	}
+	if err := c.SaveDeviceKey(key, name); err != nil {
+		return fmt.Errorf("save device key: %w", err)
+	}
+	fmt.Printf("Device key for %s saved.\n", name)
 	return nil
 }
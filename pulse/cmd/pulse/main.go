@@ -4,17 +4,22 @@ import (
 	"fmt"
 	"os"
 	"slices"
+	_ "time/tzdata"
 
 	_ "github.com/titpetric/platform/pkg/drivers"
 
 	"github.com/titpetric/cli"
 	"github.com/titpetric/platform"
 
+	"github.com/titpetric/platform-app/pulse/cmd/pulse/admin"
+	"github.com/titpetric/platform-app/pulse/cmd/pulse/export"
 	"github.com/titpetric/platform-app/pulse/cmd/pulse/login"
 	"github.com/titpetric/platform-app/pulse/cmd/pulse/record"
 	"github.com/titpetric/platform-app/pulse/cmd/pulse/register"
 	"github.com/titpetric/platform-app/pulse/cmd/pulse/server"
+	"github.com/titpetric/platform-app/pulse/cmd/pulse/stats"
 	"github.com/titpetric/platform-app/pulse/cmd/pulse/version"
+	useradmin "github.com/titpetric/platform-app/user/cmd/admin"
 )
 
 func main() {
@@ -47,6 +52,10 @@ func run() error {
 	app.AddCommand("record", record.Name, record.NewCommand)
 	app.AddCommand("login", login.Name, login.NewCommand)
 	app.AddCommand("register", register.Name, register.NewCommand)
+	app.AddCommand("stats", stats.Name, stats.NewCommand)
+	app.AddCommand("export", export.Name, export.NewCommand)
+	app.AddCommand("admin", admin.Name, admin.NewCommand)
+	app.AddCommand("user", useradmin.Name, useradmin.NewCommand)
 	app.AddCommand("version", version.Name, func() *cli.Command {
 		return version.NewCommand(version.Info{
 			Version:    Version,
@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"sync/atomic"
+	"strings"
 	"time"
 
 	"github.com/titpetric/cli"
@@ -20,9 +20,17 @@ const Name = "Record keystroke activity"
 
 // Options holds record command configuration.
 type Options struct {
-	Name     string
-	Server   string
-	Duration string
+	Name       string
+	Server     string
+	Duration   string
+	Include    string
+	Exclude    string
+	AllDevices bool
+
+	Source   string
+	File     string
+	Realtime bool
+	Rate     int
 }
 
 // Bind registers record flags with the flag set.
@@ -36,7 +44,14 @@ func (o *Options) Bind(flag *cli.FlagSet) {
 
 	flag.StringVar(&o.Name, "name", name, "Client name (hostname default)")
 	flag.StringVar(&o.Server, "server", server, "Pulse server URL")
-	flag.StringVar(&o.Duration, "duration", "5m", "Duration between pulse sends")
+	flag.StringVar(&o.Duration, "duration", "1m", "Duration between pulse sends (at most 1m for accurate sessions)")
+	flag.StringVar(&o.Include, "include", "", "Also read devices with names containing any of these (comma separated)")
+	flag.StringVar(&o.Exclude, "exclude", "", "Skip devices with names containing any of these (comma separated)")
+	flag.BoolVar(&o.AllDevices, "all-devices", false, "Read all input devices, not only keyboards")
+	flag.StringVar(&o.Source, "source", "evdev", "Keystroke source (evdev, stdin, lines, replay, synthetic)")
+	flag.StringVar(&o.File, "file", "-", "Event device dump to replay (- for stdin)")
+	flag.BoolVar(&o.Realtime, "realtime", false, "Replay events with their recorded delays")
+	flag.IntVar(&o.Rate, "rate", 300, "Synthetic key presses per minute")
 }
 
 // NewCommand creates a new record command.
@@ -61,66 +76,144 @@ func Run(ctx context.Context, opts Options) error {
 		return fmt.Errorf("invalid duration: %w", err)
 	}
 
+	source, closeSource, err := newSource(opts)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+
 	// Initialize client and load token
 	c := client.New(opts.Server)
 	if err := c.EnsureToken(); err != nil {
 		return fmt.Errorf("authentication required: %w (run 'pulse login' first)", err)
 	}
 
-	log.Printf("Recording keypresses, sending every %s to %s", opts.Duration, opts.Server)
+	// A device key records for the host it is bound to. Buckets leave
+	// the hostname empty, so the server fills in the bound host, even
+	// after the host is renamed in the web UI.
+	hostname := opts.Name
+	if c.IsDeviceKey() {
+		if opts.Name != c.Hostname() {
+			log.Printf("device key is bound to %s, ignoring name %s", c.Hostname(), opts.Name)
+		}
+		hostname = ""
+	}
 
-	lastRefresh := time.Now()
+	spoolPath, err := client.SpoolPath()
+	if err != nil {
+		return err
+	}
+	spool, err := client.OpenSpool(spoolPath)
+	if err != nil {
+		return err
+	}
+	if n := spool.Len(); n > 0 {
+		log.Printf("resuming with %d spooled bucket(s) from %s", n, spoolPath)
+	}
 
-	var queued int64
+	log.Printf("Recording keypresses, sending every %s to %s", opts.Duration, opts.Server)
 
-	// Setup keyboard counter with flush function
+	// Drain the spool in the background so the counter never blocks
+	sender := client.NewSender(c, spool)
+	senderCtx, stopSender := context.WithCancel(ctx)
+	defer stopSender()
+	senderDone := make(chan struct{})
+	go func() {
+		defer close(senderDone)
+		sender.Run(senderCtx)
+	}()
+
+	// Setup keystroke counter with flush function
 	keyCounterOpts := &keycounter.Options{
 		FlushInterval: duration,
-		FlushFn: func(val int32) {
-			if val <= 0 {
+		FlushFn: func(counts keycounter.Counts) {
+			total := counts.Total()
+			if total <= 0 {
 				return
 			}
 
-			// Refresh token if needed
-			if time.Since(lastRefresh) > 24*time.Hour {
-				if err := c.RefreshToken(); err != nil {
-					log.Printf("token refresh failed: %v", err)
-				} else {
-					lastRefresh = time.Now()
-					log.Println("token refreshed")
-				}
+			categories := make(map[string]int64, len(counts))
+			for category, n := range counts {
+				categories[string(category)] = n
 			}
 
-			// get any queued keystrokes
-			qVal := atomic.SwapInt64(&queued, 0) + int64(val)
-
-			tries := 3
-			for {
-				if tries == 0 {
-					break
-				}
-				tries--
-
-				if err := c.SendPulse(qVal, opts.Name); err != nil {
-					log.Printf("send failed: %v", err)
-				} else {
-					log.Printf("sent %d keystroke(s)", val)
-					return
-				}
-
-				time.Sleep(time.Minute)
+			bucket := client.Bucket{
+				Hostname:   hostname,
+				Stamp:      time.Now().UTC().Truncate(time.Minute),
+				Count:      total,
+				Categories: categories,
 			}
-
-			atomic.AddInt64(&queued, qVal)
+			if err := spool.Add(bucket); err != nil {
+				log.Printf("spool failed: %v", err)
+				return
+			}
+			sender.Notify()
 		},
 	}
 
-	// Run keyboard counter
-	log.Println("starting keystroke counter")
-	if err := keycounter.KeyboardCounter(ctx, keyCounterOpts); err != nil {
+	// Run keystroke counter
+	log.Printf("starting keystroke counter (%s)", opts.Source)
+	if err := keycounter.Count(ctx, source, keyCounterOpts); err != nil {
 		return fmt.Errorf("keystroke counter: %w", err)
 	}
 	log.Println("keystroke counter exited")
 
+	// Sources other than evdev can run out of input. The last counts are
+	// sent before exiting, what fails to send stays spooled.
+	stopSender()
+	<-senderDone
+	if err := sender.Drain(); err != nil {
+		return fmt.Errorf("send failed, %d bucket(s) spooled: %w", spool.Len(), err)
+	}
+
 	return nil
 }
+
+// newSource creates the keystroke source selected by opts. The returned
+// function releases the source.
+func newSource(opts Options) (keycounter.Source, func(), error) {
+	keep := func() {}
+
+	switch opts.Source {
+	case "", "evdev":
+		return keycounter.NewEvdevSource(&keycounter.Options{
+			Include:    splitList(opts.Include),
+			Exclude:    splitList(opts.Exclude),
+			AllDevices: opts.AllDevices,
+			DeviceFn: func(d keycounter.Device, added bool) {
+				if added {
+					log.Printf("reading %s (%s)", d.Path, d.Name)
+					return
+				}
+				log.Printf("removed %s (%s)", d.Path, d.Name)
+			},
+		}), keep, nil
+	case "stdin":
+		return keycounter.NewReaderSource(os.Stdin, false), keep, nil
+	case "lines":
+		return keycounter.NewReaderSource(os.Stdin, true), keep, nil
+	case "replay":
+		if opts.File == "" || opts.File == "-" {
+			return keycounter.NewReplaySource(os.Stdin, opts.Realtime), keep, nil
+		}
+		f, err := os.Open(opts.File)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open replay file: %w", err)
+		}
+		return keycounter.NewReplaySource(f, opts.Realtime), func() { f.Close() }, nil
+	case "synthetic":
+		return keycounter.NewSyntheticSource(opts.Rate, 0), keep, nil
+	}
+	return nil, nil, fmt.Errorf("unknown source: %s (evdev, stdin, lines, replay, synthetic)", opts.Source)
+}
+
+// splitList splits a comma separated flag value.
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
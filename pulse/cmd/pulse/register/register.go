@@ -16,7 +16,6 @@ import (
 	"github.com/titpetric/cli"
 	"golang.org/x/term"
 
-	"github.com/titpetric/platform-app/pulse/client"
 	"github.com/titpetric/platform-app/pulse/cmd/pulse/login"
 )
 
@@ -25,10 +24,12 @@ const Name = "Register a new account"
 
 // Options holds register command configuration.
 type Options struct {
-	Server   string
-	Email    string
-	Password string
-	Username string
+	Server    string
+	Email     string
+	Password  string
+	Username  string
+	Name      string
+	DeviceKey bool
 }
 
 // Bind registers registration flags with the flag set.
@@ -41,6 +42,7 @@ func (o *Options) Bind(flag *cli.FlagSet) {
 	flag.StringVar(&o.Email, "email", "", "Email address (optional, will prompt if not provided)")
 	flag.StringVar(&o.Password, "password", "", "Password (optional, will prompt if not provided)")
 	flag.StringVar(&o.Username, "username", "", "Username (optional, will prompt if not provided)")
+	login.BindDevice(flag, &o.Name, &o.DeviceKey)
 }
 
 // NewCommand creates a new register command.
@@ -135,9 +137,11 @@ func Run(opts Options) error {
 		// Username already taken, try to login instead
 		fmt.Println("User already exists, attempting login...")
 		return login.Run(login.Options{
-			Server:   opts.Server,
-			Email:    email,
-			Password: password,
+			Server:    opts.Server,
+			Email:     email,
+			Password:  password,
+			Name:      opts.Name,
+			DeviceKey: opts.DeviceKey,
 		})
 	}
 
@@ -155,12 +159,7 @@ func Run(opts Options) error {
 		return fmt.Errorf("decode response: %w", err)
 	}
 
-	expiresAt := time.Unix(result.ExpiresAt, 0)
-	c := client.New(opts.Server)
-	if err := c.SaveToken(result.Token, expiresAt); err != nil {
-		return fmt.Errorf("save token: %w", err)
-	}
-
 	fmt.Printf("Registration successful! User ID: %s\n", result.UserID)
-	return nil
+
+	return login.SaveCredentials(opts.Server, opts.Name, opts.DeviceKey, result.Token, time.Unix(result.ExpiresAt, 0))
 }
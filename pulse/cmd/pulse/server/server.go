@@ -9,6 +9,7 @@ import (
 	"github.com/titpetric/cli"
 	"github.com/titpetric/platform"
 
+	"github.com/titpetric/platform-app/email"
 	"github.com/titpetric/platform-app/pulse"
 	"github.com/titpetric/platform-app/pulse/config"
 	"github.com/titpetric/platform-app/user"
@@ -36,8 +37,11 @@ func Run(ctx context.Context) error {
 	svc := platform.New(platformOpts)
 
 	svc.Use(middleware.Logger)
-	svc.Register(user.NewModule())
-	svc.Register(pulse.NewModule())
+	mail := email.NewModule()
+
+	svc.Register(mail)
+	svc.Register(user.NewModule(user.WithEmailSender(mail)))
+	svc.Register(pulse.NewModule(mail))
 
 	if err := svc.Start(ctx); err != nil {
 		return fmt.Errorf("exit error: %w", err)
@@ -0,0 +1,116 @@
+// Package stats implements the pulse stats command.
+package stats
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/titpetric/cli"
+
+	"github.com/titpetric/platform-app/pulse/client"
+)
+
+// Name is the command title.
+const Name = "Show keystroke totals"
+
+// Options holds stats command configuration.
+type Options struct {
+	Server string
+	Format string
+}
+
+// Bind registers stats flags with the flag set.
+func (o *Options) Bind(flag *cli.FlagSet) {
+	server := "http://localhost:8080"
+	if e := os.Getenv("PULSE_SERVER"); e != "" {
+		server = e
+	}
+
+	flag.StringVar(&o.Server, "server", server, "Pulse server URL")
+	flag.StringVar(&o.Format, "format", "table", "Output format (table, sparkline)")
+}
+
+// NewCommand creates a new stats command.
+func NewCommand() *cli.Command {
+	var opts Options
+
+	return &cli.Command{
+		Name:  "stats",
+		Title: Name,
+		Bind:  opts.Bind,
+		Run: func(ctx context.Context, args []string) error {
+			return Run(ctx, opts)
+		},
+	}
+}
+
+// Run fetches and prints keystroke totals for today, this week and the
+// last 30 days, per host.
+func Run(_ context.Context, opts Options) error {
+	if opts.Format != "table" && opts.Format != "sparkline" {
+		return fmt.Errorf("invalid format: %q", opts.Format)
+	}
+
+	c := client.New(opts.Server)
+	if err := c.EnsureToken(); err != nil {
+		return fmt.Errorf("authentication required: %w (run 'pulse login' first)", err)
+	}
+
+	stats, err := c.Stats()
+	if err != nil {
+		return fmt.Errorf("get stats: %w", err)
+	}
+
+	if opts.Format == "sparkline" {
+		return writeSparklines(os.Stdout, stats)
+	}
+	return writeTable(os.Stdout, stats)
+}
+
+// writeTable prints the totals per host as a table.
+func writeTable(w io.Writer, stats *client.Stats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "HOST\tTODAY\tTHIS WEEK\t30 DAYS\t")
+	for _, host := range stats.Hosts {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t\n", host.Hostname, host.Today, host.Week, host.Total)
+	}
+	fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t\n", "total", stats.Today, stats.Week, stats.Total)
+	return tw.Flush()
+}
+
+// writeSparklines prints the daily keystrokes of each host as a sparkline.
+func writeSparklines(w io.Writer, stats *client.Stats) error {
+	fmt.Fprintf(w, "Today %d, this week %d keystrokes (%s)\n", stats.Today, stats.Week, stats.Timezone)
+	fmt.Fprintf(w, "Last 30 days from %s:\n", stats.From)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, host := range stats.Hosts {
+		fmt.Fprintf(tw, "%s\t%s\t%d\n", host.Hostname, sparkline(host.Daily), host.Total)
+	}
+	return tw.Flush()
+}
+
+// sparkline draws counts with block characters, days without keystrokes
+// are left blank.
+func sparkline(counts []int64) string {
+	ticks := []rune("▁▂▃▄▅▆▇█")
+
+	var maxCount int64
+	for _, c := range counts {
+		maxCount = max(maxCount, c)
+	}
+
+	var b strings.Builder
+	for _, c := range counts {
+		if c <= 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(ticks[(c*int64(len(ticks))-1)/maxCount])
+	}
+	return b.String()
+}
@@ -0,0 +1,43 @@
+package model
+
+// Category is a class of keys counted by the recorder. Only the number
+// of presses per category is recorded, never which keys were pressed.
+type Category string
+
+// Key categories.
+const (
+	// CategoryAlphanumeric counts letters and digits.
+	CategoryAlphanumeric Category = "alphanumeric"
+	// CategoryModifier counts shift, control, alt, meta and lock keys.
+	CategoryModifier Category = "modifier"
+	// CategoryNavigation counts arrows, home, end, page up/down and insert.
+	CategoryNavigation Category = "navigation"
+	// CategoryFunction counts escape and the function keys.
+	CategoryFunction Category = "function"
+	// CategoryDelete counts backspace and delete.
+	CategoryDelete Category = "delete"
+	// CategoryOther counts whitespace, punctuation and any other key.
+	CategoryOther Category = "other"
+)
+
+// Categories returns all key categories in display order.
+func Categories() []Category {
+	return []Category{
+		CategoryAlphanumeric,
+		CategoryModifier,
+		CategoryNavigation,
+		CategoryFunction,
+		CategoryDelete,
+		CategoryOther,
+	}
+}
+
+// Valid reports whether c is a known category.
+func (c Category) Valid() bool {
+	for _, category := range Categories() {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
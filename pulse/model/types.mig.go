@@ -187,6 +187,9 @@ type PulseDaily struct {
 
 	// Count
 	Count int64 `db:"count" json:"count"`
+
+	// Rolled Up
+	RolledUp int64 `db:"rolled_up" json:"rolled_up"`
 }
 
 // GetUserID will return the value of UserID.
@@ -213,15 +216,261 @@ func (p *PulseDaily) GetCount() int64 { return p.Count }
 // SetCount sets Count to the provided value.
 func (p *PulseDaily) SetCount(val int64) { p.Count = val }
 
+// GetRolledUp will return the value of RolledUp.
+func (p *PulseDaily) GetRolledUp() int64 { return p.RolledUp }
+
+// SetRolledUp sets RolledUp to the provided value.
+func (p *PulseDaily) SetRolledUp(val int64) { p.RolledUp = val }
+
 // PulseDailyTable is the name of the table in the DB.
 const PulseDailyTable = "`pulse_daily`"
 
 // PulseDailyFields is a list of all columns in the DB table.
-var PulseDailyFields = []string{"user_id", "hostname", "stamp", "count"}
+var PulseDailyFields = []string{"user_id", "hostname", "stamp", "count", "rolled_up"}
 
 // PulseDailyPrimaryFields are the primary key fields in the DB table.
 var PulseDailyPrimaryFields = []string{"user_id", "hostname", "stamp"}
 
+// PulseDailyCategory generated for db table `pulse_daily_category`.
+//
+// Pulse Daily Category.
+type PulseDailyCategory struct {
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Hostname
+	Hostname string `db:"hostname" json:"hostname"`
+
+	// Stamp
+	Stamp *time.Time `db:"stamp" json:"stamp"`
+
+	// Category
+	Category string `db:"category" json:"category"`
+
+	// Count
+	Count int64 `db:"count" json:"count"`
+}
+
+// GetUserID will return the value of UserID.
+func (p *PulseDailyCategory) GetUserID() string { return p.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (p *PulseDailyCategory) SetUserID(val string) { p.UserID = val }
+
+// GetHostname will return the value of Hostname.
+func (p *PulseDailyCategory) GetHostname() string { return p.Hostname }
+
+// SetHostname sets Hostname to the provided value.
+func (p *PulseDailyCategory) SetHostname(val string) { p.Hostname = val }
+
+// GetStamp will return the value of Stamp.
+func (p *PulseDailyCategory) GetStamp() *time.Time { return p.Stamp }
+
+// SetStamp sets Stamp to the provided value.
+func (p *PulseDailyCategory) SetStamp(stamp time.Time) { p.Stamp = &stamp }
+
+// GetCategory will return the value of Category.
+func (p *PulseDailyCategory) GetCategory() string { return p.Category }
+
+// SetCategory sets Category to the provided value.
+func (p *PulseDailyCategory) SetCategory(val string) { p.Category = val }
+
+// GetCount will return the value of Count.
+func (p *PulseDailyCategory) GetCount() int64 { return p.Count }
+
+// SetCount sets Count to the provided value.
+func (p *PulseDailyCategory) SetCount(val int64) { p.Count = val }
+
+// PulseDailyCategoryTable is the name of the table in the DB.
+const PulseDailyCategoryTable = "`pulse_daily_category`"
+
+// PulseDailyCategoryFields is a list of all columns in the DB table.
+var PulseDailyCategoryFields = []string{"user_id", "hostname", "stamp", "category", "count"}
+
+// PulseDailyCategoryPrimaryFields are the primary key fields in the DB table.
+var PulseDailyCategoryPrimaryFields = []string{"user_id", "hostname", "stamp", "category"}
+
+// PulseDeviceKey generated for db table `pulse_device_key`.
+//
+// Pulse Device Key.
+type PulseDeviceKey struct {
+	// ID
+	ID string `db:"id" json:"id"`
+
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Hostname
+	Hostname string `db:"hostname" json:"hostname"`
+
+	// Key Hash
+	KeyHash string `db:"key_hash" json:"key_hash"`
+
+	// Created At
+	CreatedAt *time.Time `db:"created_at" json:"created_at"`
+
+	// Last Used At
+	LastUsedAt *time.Time `db:"last_used_at" json:"last_used_at"`
+
+	// Revoked At
+	RevokedAt *time.Time `db:"revoked_at" json:"revoked_at"`
+}
+
+// GetID will return the value of ID.
+func (p *PulseDeviceKey) GetID() string { return p.ID }
+
+// SetID sets ID to the provided value.
+func (p *PulseDeviceKey) SetID(val string) { p.ID = val }
+
+// GetUserID will return the value of UserID.
+func (p *PulseDeviceKey) GetUserID() string { return p.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (p *PulseDeviceKey) SetUserID(val string) { p.UserID = val }
+
+// GetHostname will return the value of Hostname.
+func (p *PulseDeviceKey) GetHostname() string { return p.Hostname }
+
+// SetHostname sets Hostname to the provided value.
+func (p *PulseDeviceKey) SetHostname(val string) { p.Hostname = val }
+
+// GetKeyHash will return the value of KeyHash.
+func (p *PulseDeviceKey) GetKeyHash() string { return p.KeyHash }
+
+// SetKeyHash sets KeyHash to the provided value.
+func (p *PulseDeviceKey) SetKeyHash(val string) { p.KeyHash = val }
+
+// GetCreatedAt will return the value of CreatedAt.
+func (p *PulseDeviceKey) GetCreatedAt() *time.Time { return p.CreatedAt }
+
+// SetCreatedAt sets CreatedAt to the provided value.
+func (p *PulseDeviceKey) SetCreatedAt(stamp time.Time) { p.CreatedAt = &stamp }
+
+// GetLastUsedAt will return the value of LastUsedAt.
+func (p *PulseDeviceKey) GetLastUsedAt() *time.Time { return p.LastUsedAt }
+
+// SetLastUsedAt sets LastUsedAt to the provided value.
+func (p *PulseDeviceKey) SetLastUsedAt(stamp time.Time) { p.LastUsedAt = &stamp }
+
+// GetRevokedAt will return the value of RevokedAt.
+func (p *PulseDeviceKey) GetRevokedAt() *time.Time { return p.RevokedAt }
+
+// SetRevokedAt sets RevokedAt to the provided value.
+func (p *PulseDeviceKey) SetRevokedAt(stamp time.Time) { p.RevokedAt = &stamp }
+
+// PulseDeviceKeyTable is the name of the table in the DB.
+const PulseDeviceKeyTable = "`pulse_device_key`"
+
+// PulseDeviceKeyFields is a list of all columns in the DB table.
+var PulseDeviceKeyFields = []string{"id", "user_id", "hostname", "key_hash", "created_at", "last_used_at", "revoked_at"}
+
+// PulseDeviceKeyPrimaryFields are the primary key fields in the DB table.
+var PulseDeviceKeyPrimaryFields = []string{"id"}
+
+// PulseGoal generated for db table `pulse_goal`.
+//
+// Pulse Goal.
+type PulseGoal struct {
+	// ID
+	ID string `db:"id" json:"id"`
+
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Metric
+	Metric string `db:"metric" json:"metric"`
+
+	// Comparison
+	Comparison string `db:"comparison" json:"comparison"`
+
+	// Threshold
+	Threshold int64 `db:"threshold" json:"threshold"`
+
+	// Days
+	Days string `db:"days" json:"days"`
+
+	// From Hour
+	FromHour int64 `db:"from_hour" json:"from_hour"`
+
+	// To Hour
+	ToHour int64 `db:"to_hour" json:"to_hour"`
+
+	// Created At
+	CreatedAt *time.Time `db:"created_at" json:"created_at"`
+
+	// Settled On
+	SettledOn *time.Time `db:"settled_on" json:"settled_on"`
+}
+
+// GetID will return the value of ID.
+func (p *PulseGoal) GetID() string { return p.ID }
+
+// SetID sets ID to the provided value.
+func (p *PulseGoal) SetID(val string) { p.ID = val }
+
+// GetUserID will return the value of UserID.
+func (p *PulseGoal) GetUserID() string { return p.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (p *PulseGoal) SetUserID(val string) { p.UserID = val }
+
+// GetMetric will return the value of Metric.
+func (p *PulseGoal) GetMetric() string { return p.Metric }
+
+// SetMetric sets Metric to the provided value.
+func (p *PulseGoal) SetMetric(val string) { p.Metric = val }
+
+// GetComparison will return the value of Comparison.
+func (p *PulseGoal) GetComparison() string { return p.Comparison }
+
+// SetComparison sets Comparison to the provided value.
+func (p *PulseGoal) SetComparison(val string) { p.Comparison = val }
+
+// GetThreshold will return the value of Threshold.
+func (p *PulseGoal) GetThreshold() int64 { return p.Threshold }
+
+// SetThreshold sets Threshold to the provided value.
+func (p *PulseGoal) SetThreshold(val int64) { p.Threshold = val }
+
+// GetDays will return the value of Days.
+func (p *PulseGoal) GetDays() string { return p.Days }
+
+// SetDays sets Days to the provided value.
+func (p *PulseGoal) SetDays(val string) { p.Days = val }
+
+// GetFromHour will return the value of FromHour.
+func (p *PulseGoal) GetFromHour() int64 { return p.FromHour }
+
+// SetFromHour sets FromHour to the provided value.
+func (p *PulseGoal) SetFromHour(val int64) { p.FromHour = val }
+
+// GetToHour will return the value of ToHour.
+func (p *PulseGoal) GetToHour() int64 { return p.ToHour }
+
+// SetToHour sets ToHour to the provided value.
+func (p *PulseGoal) SetToHour(val int64) { p.ToHour = val }
+
+// GetCreatedAt will return the value of CreatedAt.
+func (p *PulseGoal) GetCreatedAt() *time.Time { return p.CreatedAt }
+
+// SetCreatedAt sets CreatedAt to the provided value.
+func (p *PulseGoal) SetCreatedAt(stamp time.Time) { p.CreatedAt = &stamp }
+
+// GetSettledOn will return the value of SettledOn.
+func (p *PulseGoal) GetSettledOn() *time.Time { return p.SettledOn }
+
+// SetSettledOn sets SettledOn to the provided value.
+func (p *PulseGoal) SetSettledOn(stamp time.Time) { p.SettledOn = &stamp }
+
+// PulseGoalTable is the name of the table in the DB.
+const PulseGoalTable = "`pulse_goal`"
+
+// PulseGoalFields is a list of all columns in the DB table.
+var PulseGoalFields = []string{"id", "user_id", "metric", "comparison", "threshold", "days", "from_hour", "to_hour", "created_at", "settled_on"}
+
+// PulseGoalPrimaryFields are the primary key fields in the DB table.
+var PulseGoalPrimaryFields = []string{"id"}
+
 // PulseHosts generated for db table `pulse_hosts`.
 //
 // Pulse Hosts.
@@ -234,6 +483,9 @@ type PulseHosts struct {
 
 	// Created At
 	CreatedAt *time.Time `db:"created_at" json:"created_at"`
+
+	// Hidden
+	Hidden int64 `db:"hidden" json:"hidden"`
 }
 
 // GetUserID will return the value of UserID.
@@ -254,11 +506,17 @@ func (p *PulseHosts) GetCreatedAt() *time.Time { return p.CreatedAt }
 // SetCreatedAt sets CreatedAt to the provided value.
 func (p *PulseHosts) SetCreatedAt(stamp time.Time) { p.CreatedAt = &stamp }
 
+// GetHidden will return the value of Hidden.
+func (p *PulseHosts) GetHidden() int64 { return p.Hidden }
+
+// SetHidden sets Hidden to the provided value.
+func (p *PulseHosts) SetHidden(val int64) { p.Hidden = val }
+
 // PulseHostsTable is the name of the table in the DB.
 const PulseHostsTable = "`pulse_hosts`"
 
 // PulseHostsFields is a list of all columns in the DB table.
-var PulseHostsFields = []string{"user_id", "hostname", "created_at"}
+var PulseHostsFields = []string{"user_id", "hostname", "created_at", "hidden"}
 
 // PulseHostsPrimaryFields are the primary key fields in the DB table.
 var PulseHostsPrimaryFields = []string{"user_id", "hostname"}
@@ -270,53 +528,902 @@ type PulseHourly struct {
 	// User ID
 	UserID string `db:"user_id" json:"user_id"`
 
-	// Hostname
-	Hostname string `db:"hostname" json:"hostname"`
+	// Hostname
+	Hostname string `db:"hostname" json:"hostname"`
+
+	// Stamp
+	Stamp *time.Time `db:"stamp" json:"stamp"`
+
+	// Count
+	Count int64 `db:"count" json:"count"`
+}
+
+// GetUserID will return the value of UserID.
+func (p *PulseHourly) GetUserID() string { return p.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (p *PulseHourly) SetUserID(val string) { p.UserID = val }
+
+// GetHostname will return the value of Hostname.
+func (p *PulseHourly) GetHostname() string { return p.Hostname }
+
+// SetHostname sets Hostname to the provided value.
+func (p *PulseHourly) SetHostname(val string) { p.Hostname = val }
+
+// GetStamp will return the value of Stamp.
+func (p *PulseHourly) GetStamp() *time.Time { return p.Stamp }
+
+// SetStamp sets Stamp to the provided value.
+func (p *PulseHourly) SetStamp(stamp time.Time) { p.Stamp = &stamp }
+
+// GetCount will return the value of Count.
+func (p *PulseHourly) GetCount() int64 { return p.Count }
+
+// SetCount sets Count to the provided value.
+func (p *PulseHourly) SetCount(val int64) { p.Count = val }
+
+// PulseHourlyTable is the name of the table in the DB.
+const PulseHourlyTable = "`pulse_hourly`"
+
+// PulseHourlyFields is a list of all columns in the DB table.
+var PulseHourlyFields = []string{"user_id", "hostname", "stamp", "count"}
+
+// PulseHourlyPrimaryFields are the primary key fields in the DB table.
+var PulseHourlyPrimaryFields = []string{"user_id", "hostname", "stamp"}
+
+// PulseHourlyCategory generated for db table `pulse_hourly_category`.
+//
+// Pulse Hourly Category.
+type PulseHourlyCategory struct {
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Hostname
+	Hostname string `db:"hostname" json:"hostname"`
+
+	// Stamp
+	Stamp *time.Time `db:"stamp" json:"stamp"`
+
+	// Category
+	Category string `db:"category" json:"category"`
+
+	// Count
+	Count int64 `db:"count" json:"count"`
+}
+
+// GetUserID will return the value of UserID.
+func (p *PulseHourlyCategory) GetUserID() string { return p.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (p *PulseHourlyCategory) SetUserID(val string) { p.UserID = val }
+
+// GetHostname will return the value of Hostname.
+func (p *PulseHourlyCategory) GetHostname() string { return p.Hostname }
+
+// SetHostname sets Hostname to the provided value.
+func (p *PulseHourlyCategory) SetHostname(val string) { p.Hostname = val }
+
+// GetStamp will return the value of Stamp.
+func (p *PulseHourlyCategory) GetStamp() *time.Time { return p.Stamp }
+
+// SetStamp sets Stamp to the provided value.
+func (p *PulseHourlyCategory) SetStamp(stamp time.Time) { p.Stamp = &stamp }
+
+// GetCategory will return the value of Category.
+func (p *PulseHourlyCategory) GetCategory() string { return p.Category }
+
+// SetCategory sets Category to the provided value.
+func (p *PulseHourlyCategory) SetCategory(val string) { p.Category = val }
+
+// GetCount will return the value of Count.
+func (p *PulseHourlyCategory) GetCount() int64 { return p.Count }
+
+// SetCount sets Count to the provided value.
+func (p *PulseHourlyCategory) SetCount(val int64) { p.Count = val }
+
+// PulseHourlyCategoryTable is the name of the table in the DB.
+const PulseHourlyCategoryTable = "`pulse_hourly_category`"
+
+// PulseHourlyCategoryFields is a list of all columns in the DB table.
+var PulseHourlyCategoryFields = []string{"user_id", "hostname", "stamp", "category", "count"}
+
+// PulseHourlyCategoryPrimaryFields are the primary key fields in the DB table.
+var PulseHourlyCategoryPrimaryFields = []string{"user_id", "hostname", "stamp", "category"}
+
+// PulseIngest generated for db table `pulse_ingest`.
+//
+// Pulse Ingest.
+type PulseIngest struct {
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Batch ID
+	BatchID string `db:"batch_id" json:"batch_id"`
+
+	// Created At
+	CreatedAt *time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetUserID will return the value of UserID.
+func (p *PulseIngest) GetUserID() string { return p.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (p *PulseIngest) SetUserID(val string) { p.UserID = val }
+
+// GetBatchID will return the value of BatchID.
+func (p *PulseIngest) GetBatchID() string { return p.BatchID }
+
+// SetBatchID sets BatchID to the provided value.
+func (p *PulseIngest) SetBatchID(val string) { p.BatchID = val }
+
+// GetCreatedAt will return the value of CreatedAt.
+func (p *PulseIngest) GetCreatedAt() *time.Time { return p.CreatedAt }
+
+// SetCreatedAt sets CreatedAt to the provided value.
+func (p *PulseIngest) SetCreatedAt(stamp time.Time) { p.CreatedAt = &stamp }
+
+// PulseIngestTable is the name of the table in the DB.
+const PulseIngestTable = "`pulse_ingest`"
+
+// PulseIngestFields is a list of all columns in the DB table.
+var PulseIngestFields = []string{"user_id", "batch_id", "created_at"}
+
+// PulseIngestPrimaryFields are the primary key fields in the DB table.
+var PulseIngestPrimaryFields = []string{"user_id", "batch_id"}
+
+// PulseMinutely generated for db table `pulse_minutely`.
+//
+// Pulse Minutely.
+type PulseMinutely struct {
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Hostname
+	Hostname string `db:"hostname" json:"hostname"`
+
+	// Stamp
+	Stamp *time.Time `db:"stamp" json:"stamp"`
+
+	// Count
+	Count int64 `db:"count" json:"count"`
+}
+
+// GetUserID will return the value of UserID.
+func (p *PulseMinutely) GetUserID() string { return p.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (p *PulseMinutely) SetUserID(val string) { p.UserID = val }
+
+// GetHostname will return the value of Hostname.
+func (p *PulseMinutely) GetHostname() string { return p.Hostname }
+
+// SetHostname sets Hostname to the provided value.
+func (p *PulseMinutely) SetHostname(val string) { p.Hostname = val }
+
+// GetStamp will return the value of Stamp.
+func (p *PulseMinutely) GetStamp() *time.Time { return p.Stamp }
+
+// SetStamp sets Stamp to the provided value.
+func (p *PulseMinutely) SetStamp(stamp time.Time) { p.Stamp = &stamp }
+
+// GetCount will return the value of Count.
+func (p *PulseMinutely) GetCount() int64 { return p.Count }
+
+// SetCount sets Count to the provided value.
+func (p *PulseMinutely) SetCount(val int64) { p.Count = val }
+
+// PulseMinutelyTable is the name of the table in the DB.
+const PulseMinutelyTable = "`pulse_minutely`"
+
+// PulseMinutelyFields is a list of all columns in the DB table.
+var PulseMinutelyFields = []string{"user_id", "hostname", "stamp", "count"}
+
+// PulseMinutelyPrimaryFields are the primary key fields in the DB table.
+var PulseMinutelyPrimaryFields = []string{"user_id", "hostname", "stamp"}
+
+// PulseMonthly generated for db table `pulse_monthly`.
+//
+// Pulse Monthly.
+type PulseMonthly struct {
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Hostname
+	Hostname string `db:"hostname" json:"hostname"`
+
+	// Stamp
+	Stamp *time.Time `db:"stamp" json:"stamp"`
+
+	// Count
+	Count int64 `db:"count" json:"count"`
+}
+
+// GetUserID will return the value of UserID.
+func (p *PulseMonthly) GetUserID() string { return p.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (p *PulseMonthly) SetUserID(val string) { p.UserID = val }
+
+// GetHostname will return the value of Hostname.
+func (p *PulseMonthly) GetHostname() string { return p.Hostname }
+
+// SetHostname sets Hostname to the provided value.
+func (p *PulseMonthly) SetHostname(val string) { p.Hostname = val }
+
+// GetStamp will return the value of Stamp.
+func (p *PulseMonthly) GetStamp() *time.Time { return p.Stamp }
+
+// SetStamp sets Stamp to the provided value.
+func (p *PulseMonthly) SetStamp(stamp time.Time) { p.Stamp = &stamp }
+
+// GetCount will return the value of Count.
+func (p *PulseMonthly) GetCount() int64 { return p.Count }
+
+// SetCount sets Count to the provided value.
+func (p *PulseMonthly) SetCount(val int64) { p.Count = val }
+
+// PulseMonthlyTable is the name of the table in the DB.
+const PulseMonthlyTable = "`pulse_monthly`"
+
+// PulseMonthlyFields is a list of all columns in the DB table.
+var PulseMonthlyFields = []string{"user_id", "hostname", "stamp", "count"}
+
+// PulseMonthlyPrimaryFields are the primary key fields in the DB table.
+var PulseMonthlyPrimaryFields = []string{"user_id", "hostname", "stamp"}
+
+// PulseProfile generated for db table `pulse_profile`.
+//
+// Pulse Profile.
+type PulseProfile struct {
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Visibility
+	Visibility string `db:"visibility" json:"visibility"`
+
+	// Updated At
+	UpdatedAt *time.Time `db:"updated_at" json:"updated_at"`
+
+	// Timezone
+	Timezone string `db:"timezone" json:"timezone"`
+}
+
+// GetUserID will return the value of UserID.
+func (p *PulseProfile) GetUserID() string { return p.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (p *PulseProfile) SetUserID(val string) { p.UserID = val }
+
+// GetVisibility will return the value of Visibility.
+func (p *PulseProfile) GetVisibility() string { return p.Visibility }
+
+// SetVisibility sets Visibility to the provided value.
+func (p *PulseProfile) SetVisibility(val string) { p.Visibility = val }
+
+// GetUpdatedAt will return the value of UpdatedAt.
+func (p *PulseProfile) GetUpdatedAt() *time.Time { return p.UpdatedAt }
+
+// SetUpdatedAt sets UpdatedAt to the provided value.
+func (p *PulseProfile) SetUpdatedAt(stamp time.Time) { p.UpdatedAt = &stamp }
+
+// GetTimezone will return the value of Timezone.
+func (p *PulseProfile) GetTimezone() string { return p.Timezone }
+
+// SetTimezone sets Timezone to the provided value.
+func (p *PulseProfile) SetTimezone(val string) { p.Timezone = val }
+
+// PulseProfileTable is the name of the table in the DB.
+const PulseProfileTable = "`pulse_profile`"
+
+// PulseProfileFields is a list of all columns in the DB table.
+var PulseProfileFields = []string{"user_id", "visibility", "updated_at", "timezone"}
+
+// PulseProfilePrimaryFields are the primary key fields in the DB table.
+var PulseProfilePrimaryFields = []string{"user_id"}
+
+// PulseWeekly generated for db table `pulse_weekly`.
+//
+// Pulse Weekly.
+type PulseWeekly struct {
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Hostname
+	Hostname string `db:"hostname" json:"hostname"`
+
+	// Stamp
+	Stamp *time.Time `db:"stamp" json:"stamp"`
+
+	// Count
+	Count int64 `db:"count" json:"count"`
+}
+
+// GetUserID will return the value of UserID.
+func (p *PulseWeekly) GetUserID() string { return p.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (p *PulseWeekly) SetUserID(val string) { p.UserID = val }
+
+// GetHostname will return the value of Hostname.
+func (p *PulseWeekly) GetHostname() string { return p.Hostname }
+
+// SetHostname sets Hostname to the provided value.
+func (p *PulseWeekly) SetHostname(val string) { p.Hostname = val }
+
+// GetStamp will return the value of Stamp.
+func (p *PulseWeekly) GetStamp() *time.Time { return p.Stamp }
+
+// SetStamp sets Stamp to the provided value.
+func (p *PulseWeekly) SetStamp(stamp time.Time) { p.Stamp = &stamp }
+
+// GetCount will return the value of Count.
+func (p *PulseWeekly) GetCount() int64 { return p.Count }
+
+// SetCount sets Count to the provided value.
+func (p *PulseWeekly) SetCount(val int64) { p.Count = val }
+
+// PulseWeeklyTable is the name of the table in the DB.
+const PulseWeeklyTable = "`pulse_weekly`"
+
+// PulseWeeklyFields is a list of all columns in the DB table.
+var PulseWeeklyFields = []string{"user_id", "hostname", "stamp", "count"}
+
+// PulseWeeklyPrimaryFields are the primary key fields in the DB table.
+var PulseWeeklyPrimaryFields = []string{"user_id", "hostname", "stamp"}
+
+// Insert starts building an INSERT INTO query.
+func (m *Migrations) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: MigrationsTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := MigrationsFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (m *Migrations) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: MigrationsTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (m *Migrations) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: MigrationsTable}).Apply(opts...)
+	cols := MigrationsFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Delete starts building a DELETE query.
+func (m *Migrations) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: MigrationsTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Insert starts building an INSERT INTO query.
+func (p *PulseDaily) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDailyTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseDailyFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (p *PulseDaily) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDailyTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (p *PulseDaily) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDailyTable}).Apply(opts...)
+	cols := PulseDailyFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Delete starts building a DELETE query.
+func (p *PulseDaily) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDailyTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Insert starts building an INSERT INTO query.
+func (p *PulseDailyCategory) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDailyCategoryTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseDailyCategoryFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (p *PulseDailyCategory) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDailyCategoryTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (p *PulseDailyCategory) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDailyCategoryTable}).Apply(opts...)
+	cols := PulseDailyCategoryFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Delete starts building a DELETE query.
+func (p *PulseDailyCategory) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDailyCategoryTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Insert starts building an INSERT INTO query.
+func (p *PulseDeviceKey) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDeviceKeyTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseDeviceKeyFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (p *PulseDeviceKey) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDeviceKeyTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (p *PulseDeviceKey) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDeviceKeyTable}).Apply(opts...)
+	cols := PulseDeviceKeyFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Delete starts building a DELETE query.
+func (p *PulseDeviceKey) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseDeviceKeyTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Insert starts building an INSERT INTO query.
+func (p *PulseGoal) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseGoalTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseGoalFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (p *PulseGoal) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseGoalTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (p *PulseGoal) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseGoalTable}).Apply(opts...)
+	cols := PulseGoalFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Delete starts building a DELETE query.
+func (p *PulseGoal) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseGoalTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Insert starts building an INSERT INTO query.
+func (p *PulseHosts) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHostsTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseHostsFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (p *PulseHosts) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHostsTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (p *PulseHosts) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHostsTable}).Apply(opts...)
+	cols := PulseHostsFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
 
-	// Stamp
-	Stamp *time.Time `db:"stamp" json:"stamp"`
+// Delete starts building a DELETE query.
+func (p *PulseHosts) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHostsTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
 
-	// Count
-	Count int64 `db:"count" json:"count"`
+// Insert starts building an INSERT INTO query.
+func (p *PulseHourly) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHourlyTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseHourlyFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
 }
 
-// GetUserID will return the value of UserID.
-func (p *PulseHourly) GetUserID() string { return p.UserID }
+// Select starts building a SELECT query.
+func (p *PulseHourly) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHourlyTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
 
-// SetUserID sets UserID to the provided value.
-func (p *PulseHourly) SetUserID(val string) { p.UserID = val }
+// Update starts building a UPDATE query.
+func (p *PulseHourly) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHourlyTable}).Apply(opts...)
+	cols := PulseHourlyFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
 
-// GetHostname will return the value of Hostname.
-func (p *PulseHourly) GetHostname() string { return p.Hostname }
+// Delete starts building a DELETE query.
+func (p *PulseHourly) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHourlyTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
 
-// SetHostname sets Hostname to the provided value.
-func (p *PulseHourly) SetHostname(val string) { p.Hostname = val }
+// Insert starts building an INSERT INTO query.
+func (p *PulseHourlyCategory) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHourlyCategoryTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseHourlyCategoryFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
 
-// GetStamp will return the value of Stamp.
-func (p *PulseHourly) GetStamp() *time.Time { return p.Stamp }
+// Select starts building a SELECT query.
+func (p *PulseHourlyCategory) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHourlyCategoryTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
 
-// SetStamp sets Stamp to the provided value.
-func (p *PulseHourly) SetStamp(stamp time.Time) { p.Stamp = &stamp }
+// Update starts building a UPDATE query.
+func (p *PulseHourlyCategory) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHourlyCategoryTable}).Apply(opts...)
+	cols := PulseHourlyCategoryFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
 
-// GetCount will return the value of Count.
-func (p *PulseHourly) GetCount() int64 { return p.Count }
+// Delete starts building a DELETE query.
+func (p *PulseHourlyCategory) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseHourlyCategoryTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
 
-// SetCount sets Count to the provided value.
-func (p *PulseHourly) SetCount(val int64) { p.Count = val }
+// Insert starts building an INSERT INTO query.
+func (p *PulseIngest) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseIngestTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseIngestFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
 
-// PulseHourlyTable is the name of the table in the DB.
-const PulseHourlyTable = "`pulse_hourly`"
+// Select starts building a SELECT query.
+func (p *PulseIngest) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseIngestTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
 
-// PulseHourlyFields is a list of all columns in the DB table.
-var PulseHourlyFields = []string{"user_id", "hostname", "stamp", "count"}
+// Update starts building a UPDATE query.
+func (p *PulseIngest) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseIngestTable}).Apply(opts...)
+	cols := PulseIngestFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
 
-// PulseHourlyPrimaryFields are the primary key fields in the DB table.
-var PulseHourlyPrimaryFields = []string{"user_id", "hostname", "stamp"}
+// Delete starts building a DELETE query.
+func (p *PulseIngest) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseIngestTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
 
 // Insert starts building an INSERT INTO query.
-func (m *Migrations) Insert(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: MigrationsTable, Statement: "INSERT INTO"}).Apply(opts...)
-	cols := MigrationsFields
+func (p *PulseMinutely) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseMinutelyTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseMinutelyFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -324,8 +1431,8 @@ func (m *Migrations) Insert(opts ...QueryOption) string {
 }
 
 // Select starts building a SELECT query.
-func (m *Migrations) Select(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: MigrationsTable}).Apply(opts...)
+func (p *PulseMinutely) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseMinutelyTable}).Apply(opts...)
 	cols := "*"
 	if len(cfg.Columns) > 0 {
 		cols = strings.Join(cfg.Columns, ", ")
@@ -344,9 +1451,9 @@ func (m *Migrations) Select(opts ...QueryOption) string {
 }
 
 // Update starts building a UPDATE query.
-func (m *Migrations) Update(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: MigrationsTable}).Apply(opts...)
-	cols := MigrationsFields
+func (p *PulseMinutely) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseMinutelyTable}).Apply(opts...)
+	cols := PulseMinutelyFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -365,8 +1472,8 @@ func (m *Migrations) Update(opts ...QueryOption) string {
 }
 
 // Delete starts building a DELETE query.
-func (m *Migrations) Delete(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: MigrationsTable}).Apply(opts...)
+func (p *PulseMinutely) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseMinutelyTable}).Apply(opts...)
 	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
 	if cfg.Where != "" {
 		query += " WHERE " + cfg.Where
@@ -375,9 +1482,9 @@ func (m *Migrations) Delete(opts ...QueryOption) string {
 }
 
 // Insert starts building an INSERT INTO query.
-func (p *PulseDaily) Insert(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseDailyTable, Statement: "INSERT INTO"}).Apply(opts...)
-	cols := PulseDailyFields
+func (p *PulseMonthly) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseMonthlyTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseMonthlyFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -385,8 +1492,8 @@ func (p *PulseDaily) Insert(opts ...QueryOption) string {
 }
 
 // Select starts building a SELECT query.
-func (p *PulseDaily) Select(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseDailyTable}).Apply(opts...)
+func (p *PulseMonthly) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseMonthlyTable}).Apply(opts...)
 	cols := "*"
 	if len(cfg.Columns) > 0 {
 		cols = strings.Join(cfg.Columns, ", ")
@@ -405,9 +1512,9 @@ func (p *PulseDaily) Select(opts ...QueryOption) string {
 }
 
 // Update starts building a UPDATE query.
-func (p *PulseDaily) Update(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseDailyTable}).Apply(opts...)
-	cols := PulseDailyFields
+func (p *PulseMonthly) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseMonthlyTable}).Apply(opts...)
+	cols := PulseMonthlyFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -426,8 +1533,8 @@ func (p *PulseDaily) Update(opts ...QueryOption) string {
 }
 
 // Delete starts building a DELETE query.
-func (p *PulseDaily) Delete(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseDailyTable}).Apply(opts...)
+func (p *PulseMonthly) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseMonthlyTable}).Apply(opts...)
 	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
 	if cfg.Where != "" {
 		query += " WHERE " + cfg.Where
@@ -436,9 +1543,9 @@ func (p *PulseDaily) Delete(opts ...QueryOption) string {
 }
 
 // Insert starts building an INSERT INTO query.
-func (p *PulseHosts) Insert(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseHostsTable, Statement: "INSERT INTO"}).Apply(opts...)
-	cols := PulseHostsFields
+func (p *PulseProfile) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseProfileTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseProfileFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -446,8 +1553,8 @@ func (p *PulseHosts) Insert(opts ...QueryOption) string {
 }
 
 // Select starts building a SELECT query.
-func (p *PulseHosts) Select(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseHostsTable}).Apply(opts...)
+func (p *PulseProfile) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseProfileTable}).Apply(opts...)
 	cols := "*"
 	if len(cfg.Columns) > 0 {
 		cols = strings.Join(cfg.Columns, ", ")
@@ -466,9 +1573,9 @@ func (p *PulseHosts) Select(opts ...QueryOption) string {
 }
 
 // Update starts building a UPDATE query.
-func (p *PulseHosts) Update(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseHostsTable}).Apply(opts...)
-	cols := PulseHostsFields
+func (p *PulseProfile) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseProfileTable}).Apply(opts...)
+	cols := PulseProfileFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -487,8 +1594,8 @@ func (p *PulseHosts) Update(opts ...QueryOption) string {
 }
 
 // Delete starts building a DELETE query.
-func (p *PulseHosts) Delete(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseHostsTable}).Apply(opts...)
+func (p *PulseProfile) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseProfileTable}).Apply(opts...)
 	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
 	if cfg.Where != "" {
 		query += " WHERE " + cfg.Where
@@ -497,9 +1604,9 @@ func (p *PulseHosts) Delete(opts ...QueryOption) string {
 }
 
 // Insert starts building an INSERT INTO query.
-func (p *PulseHourly) Insert(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseHourlyTable, Statement: "INSERT INTO"}).Apply(opts...)
-	cols := PulseHourlyFields
+func (p *PulseWeekly) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseWeeklyTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := PulseWeeklyFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -507,8 +1614,8 @@ func (p *PulseHourly) Insert(opts ...QueryOption) string {
 }
 
 // Select starts building a SELECT query.
-func (p *PulseHourly) Select(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseHourlyTable}).Apply(opts...)
+func (p *PulseWeekly) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseWeeklyTable}).Apply(opts...)
 	cols := "*"
 	if len(cfg.Columns) > 0 {
 		cols = strings.Join(cfg.Columns, ", ")
@@ -527,9 +1634,9 @@ func (p *PulseHourly) Select(opts ...QueryOption) string {
 }
 
 // Update starts building a UPDATE query.
-func (p *PulseHourly) Update(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseHourlyTable}).Apply(opts...)
-	cols := PulseHourlyFields
+func (p *PulseWeekly) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseWeeklyTable}).Apply(opts...)
+	cols := PulseWeeklyFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -548,8 +1655,8 @@ func (p *PulseHourly) Update(opts ...QueryOption) string {
 }
 
 // Delete starts building a DELETE query.
-func (p *PulseHourly) Delete(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: PulseHourlyTable}).Apply(opts...)
+func (p *PulseWeekly) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: PulseWeeklyTable}).Apply(opts...)
 	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
 	if cfg.Where != "" {
 		query += " WHERE " + cfg.Where
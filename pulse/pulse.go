@@ -5,7 +5,8 @@ import (
 	"github.com/titpetric/platform-app/pulse/service"
 )
 
-// NewModule creates a new pulse service module.
-func NewModule() *service.PulseModule {
-	return service.NewPulseModule()
+// NewModule creates a new pulse service module. Goal alerts are emailed
+// with mailer, usually the email module, a nil mailer disables them.
+func NewModule(mailer service.Mailer) *service.PulseModule {
+	return service.NewPulseModule(mailer)
 }
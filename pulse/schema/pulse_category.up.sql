@@ -0,0 +1,13 @@
+-- Add daily keystroke counts per key category.
+--
+-- category is one of alphanumeric, modifier, navigation, function, delete
+-- or other. Individual keys are never recorded.
+CREATE TABLE IF NOT EXISTS pulse_daily_category (
+    user_id   CHAR(26) NOT NULL,
+    hostname  TEXT NOT NULL,
+    stamp     DATE NOT NULL,
+    category  TEXT NOT NULL,
+    count     INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (user_id, hostname, stamp, category)
+);
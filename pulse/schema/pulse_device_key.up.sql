@@ -0,0 +1,19 @@
+-- Add device keys, scoped credentials for the pulse recorder.
+--
+-- A device key can only ingest pulses for the host it is bound to. Only
+-- the SHA-256 hash of the key is stored, the key itself is shown once
+-- when it is created. Revoked keys are kept with revoked_at set.
+CREATE TABLE IF NOT EXISTS pulse_device_key (
+    id           CHAR(26) NOT NULL,
+    user_id      CHAR(26) NOT NULL,
+    hostname     TEXT NOT NULL,
+    key_hash     TEXT NOT NULL,
+    created_at   DATETIME NOT NULL,
+    last_used_at DATETIME NULL,
+    revoked_at   DATETIME NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pulse_device_key_key_hash ON pulse_device_key(key_hash);
+CREATE INDEX IF NOT EXISTS idx_pulse_device_key_user_id ON pulse_device_key(user_id, hostname);
@@ -0,0 +1,22 @@
+-- Add goals, thresholds on daily activity that send email alerts.
+--
+-- metric is keystrokes or active_minutes, comparison is at_most or
+-- at_least, and days is all, weekdays or weekends. Activity is counted
+-- from from_hour to to_hour in the user timezone. settled_on is the last
+-- day the goal was alerted for, or found met.
+CREATE TABLE IF NOT EXISTS pulse_goal (
+    id          CHAR(26) NOT NULL,
+    user_id     CHAR(26) NOT NULL,
+    metric      TEXT NOT NULL,
+    comparison  TEXT NOT NULL,
+    threshold   INTEGER NOT NULL,
+    days        TEXT NOT NULL DEFAULT 'all',
+    from_hour   INTEGER NOT NULL DEFAULT 0,
+    to_hour     INTEGER NOT NULL DEFAULT 24,
+    created_at  DATETIME NOT NULL,
+    settled_on  DATE NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pulse_goal_user_id ON pulse_goal(user_id);
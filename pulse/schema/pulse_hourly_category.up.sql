@@ -0,0 +1,13 @@
+-- Add hourly keystroke counts per key category.
+--
+-- Daily category rows are rebuilt from these when the user changes their
+-- timezone, the same way daily rows are rebuilt from pulse_hourly.
+CREATE TABLE IF NOT EXISTS pulse_hourly_category (
+    user_id   CHAR(26) NOT NULL,
+    hostname  TEXT NOT NULL,
+    stamp     DATETIME NOT NULL,
+    category  TEXT NOT NULL,
+    count     INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (user_id, hostname, stamp, category)
+);
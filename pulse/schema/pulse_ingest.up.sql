@@ -0,0 +1,13 @@
+-- Track ingested batch IDs so retried requests are applied only once.
+--
+-- Rows older than the dedup TTL are purged on ingest, a replay after
+-- that window would be applied again.
+CREATE TABLE IF NOT EXISTS pulse_ingest (
+    user_id    CHAR(26) NOT NULL,
+    batch_id   CHAR(26) NOT NULL,
+    created_at DATETIME NOT NULL,
+
+    PRIMARY KEY (user_id, batch_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pulse_ingest_created_at ON pulse_ingest(created_at);
@@ -0,0 +1,12 @@
+-- Add per-minute keystroke counts.
+--
+-- Stamps are minutes in UTC. Active minutes, typing sessions and idle
+-- gaps are derived from these rows.
+CREATE TABLE IF NOT EXISTS pulse_minutely (
+    user_id   CHAR(26) NOT NULL,
+    hostname  TEXT NOT NULL,
+    stamp     DATETIME NOT NULL,
+    count     INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (user_id, hostname, stamp)
+);
@@ -0,0 +1,13 @@
+-- Add privacy settings for pulse profiles.
+--
+-- visibility is one of public, unlisted or private. Users without a
+-- pulse_profile row are public. Hidden hosts are only shown to their owner.
+CREATE TABLE IF NOT EXISTS pulse_profile (
+    user_id    CHAR(26) NOT NULL,
+    visibility TEXT NOT NULL DEFAULT 'public',
+    updated_at DATETIME NOT NULL,
+
+    PRIMARY KEY (user_id)
+);
+
+ALTER TABLE pulse_hosts ADD COLUMN hidden INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,24 @@
+-- Add weekly and monthly rollups for long term retention.
+--
+-- Weekly stamps are the Monday of the week, monthly stamps the first day
+-- of the month. Daily rows are added to the rollups once they can no
+-- longer change, and rolled_up marks them so they are added only once.
+CREATE TABLE IF NOT EXISTS pulse_weekly (
+    user_id   CHAR(26) NOT NULL,
+    hostname  TEXT NOT NULL,
+    stamp     DATE NOT NULL,
+    count     INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (user_id, hostname, stamp)
+);
+
+CREATE TABLE IF NOT EXISTS pulse_monthly (
+    user_id   CHAR(26) NOT NULL,
+    hostname  TEXT NOT NULL,
+    stamp     DATE NOT NULL,
+    count     INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (user_id, hostname, stamp)
+);
+
+ALTER TABLE pulse_daily ADD COLUMN rolled_up INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+-- Record the timezone daily rows of a user were built in.
+--
+-- Ingest compares it to the timezone on the user profile, and rebuilds
+-- the daily rows when the user changed it. Empty means unknown.
+ALTER TABLE pulse_profile ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+// activitySummary holds activity metrics formatted for the user page.
+type activitySummary struct {
+	CurrentStreak string           `json:"currentStreak"`
+	LongestStreak string           `json:"longestStreak"`
+	ActiveToday   string           `json:"activeToday"`
+	SessionsToday int              `json:"sessionsToday"`
+	WeekChange    string           `json:"weekChange"`
+	Sessions      []sessionSummary `json:"sessions"`
+}
+
+// sessionSummary is a typing session formatted for the user page.
+type sessionSummary struct {
+	Label    string `json:"label"`
+	Duration string `json:"duration"`
+	Count    int64  `json:"count"`
+}
+
+// summarizeActivity formats activity metrics, listing up to maxSessions
+// recent sessions.
+func summarizeActivity(a *storage.Activity, maxSessions int) activitySummary {
+	result := activitySummary{
+		CurrentStreak: plural(a.CurrentStreak, "day", "days"),
+		LongestStreak: plural(a.LongestStreak, "day", "days"),
+		WeekChange:    "no data for last week",
+	}
+
+	if n := len(a.Days); n > 0 {
+		today := a.Days[n-1]
+		result.ActiveToday = formatMinutes(today.ActiveMinutes)
+		result.SessionsToday = today.Sessions
+	}
+
+	if a.Change != nil {
+		result.WeekChange = fmt.Sprintf("%+.0f%% vs last week", *a.Change)
+	}
+
+	for _, session := range a.Sessions[:min(len(a.Sessions), maxSessions)] {
+		result.Sessions = append(result.Sessions, sessionSummary{
+			Label:    session.Start.Format("Mon Jan 2, 15:04") + "–" + session.End.Format("15:04"),
+			Duration: formatMinutes(int(session.Duration() / time.Minute)),
+			Count:    session.Count,
+		})
+	}
+	return result
+}
+
+func formatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%d min", minutes)
+	}
+	return fmt.Sprintf("%dh %dmin", minutes/60, minutes%60)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, one)
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
+
+// GetUserActivity returns derived activity metrics for a user: active
+// minutes per day, typing sessions, streaks and week over week change.
+func (h *Handlers) GetUserActivity(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.getUserActivity(w, r))
+}
+
+func (h *Handlers) getUserActivity(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	username := r.PathValue("username")
+
+	user, err := h.userStorage.GetByUsername(ctx, username)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusNotFound, Err: fmt.Errorf("user not found: %s", username)}
+	}
+
+	acc, err := h.access(r, user)
+	if err != nil {
+		return err
+	}
+
+	activity, err := h.storage.GetUserActivity(ctx, user.ID, location(r, user), acc.Exclude...)
+	if err != nil {
+		return err
+	}
+
+	platform.JSON(w, r, http.StatusOK, activity)
+	return nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+func TestSummarizeActivity(t *testing.T) {
+	start := time.Date(2025, 3, 10, 9, 30, 0, 0, time.UTC)
+	change := 12.4
+
+	summary := summarizeActivity(&storage.Activity{
+		Days: []storage.DayActivity{
+			{Date: "2025-03-09", ActiveMinutes: 10},
+			{Date: "2025-03-10", ActiveMinutes: 95, Sessions: 2},
+		},
+		Sessions: []storage.Session{
+			{Start: start, End: start.Add(75 * time.Minute), Count: 900},
+			{Start: start.Add(-2 * time.Hour), End: start.Add(-110 * time.Minute), Count: 100},
+		},
+		CurrentStreak: 1,
+		LongestStreak: 4,
+		Change:        &change,
+	}, 1)
+
+	assert.Equal(t, "1 day", summary.CurrentStreak)
+	assert.Equal(t, "4 days", summary.LongestStreak)
+	assert.Equal(t, "1h 35min", summary.ActiveToday)
+	assert.Equal(t, 2, summary.SessionsToday)
+	assert.Equal(t, "+12% vs last week", summary.WeekChange)
+	assert.Equal(t, []sessionSummary{
+		{Label: "Mon Mar 10, 09:30–10:45", Duration: "1h 15min", Count: 900},
+	}, summary.Sessions)
+
+	summary = summarizeActivity(&storage.Activity{}, 5)
+	assert.Equal(t, "no data for last week", summary.WeekChange)
+	assert.Empty(t, summary.Sessions)
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+	usermodel "github.com/titpetric/platform-app/user/model"
+)
+
+// badgeMaxAge is how long badges and sparklines may be cached, in seconds.
+const badgeMaxAge = 300
+
+// badgeLabels label the badge for each supported period.
+var badgeLabels = map[storage.Period]string{
+	storage.PeriodToday: "keystrokes today",
+	storage.PeriodWeek:  "keystrokes this week",
+	storage.PeriodMonth: "keystrokes this month",
+}
+
+// formatCount shortens a count for badges, as in 950, 1.2k, 123k or 4.5M.
+func formatCount(n int64) string {
+	format := func(value float64, suffix string) string {
+		if value < 10 {
+			return strings.TrimSuffix(strconv.FormatFloat(value, 'f', 1, 64), ".0") + suffix
+		}
+		return strconv.FormatInt(int64(value), 10) + suffix
+	}
+
+	switch {
+	case n < 1000:
+		return strconv.FormatInt(n, 10)
+	case n < 1000000:
+		return format(float64(n)/1000, "k")
+	}
+	return format(float64(n)/1000000, "M")
+}
+
+// textWidth estimates the width in pixels of text set in 11px Verdana.
+func textWidth(text string) int {
+	return len([]rune(text))*7 + 10
+}
+
+// badgeSVG renders a flat badge with a label and a value.
+func badgeSVG(label, value string) string {
+	labelWidth, valueWidth := textWidth(label), textWidth(value)
+	width := labelWidth + valueWidth
+	title := html.EscapeString(label + ": " + value)
+	label, value = html.EscapeString(label), html.EscapeString(value)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s">`, width, title)
+	fmt.Fprintf(&b, `<title>%s</title>`, title)
+	b.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&b, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, width)
+	fmt.Fprintf(&b, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="#3b82f6"/><rect width="%d" height="20" fill="url(#s)"/></g>`, labelWidth, labelWidth, valueWidth, width)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text><text x="%d" y="14">%s</text>`, labelWidth/2, label, labelWidth+valueWidth/2, value)
+	b.WriteString(`</g></svg>`)
+	return b.String()
+}
+
+// Sparkline dimensions in pixels.
+const (
+	sparklineWidth  = 150
+	sparklineHeight = 30
+)
+
+// sparklineSVG renders counts, oldest first, as a line with a filled area.
+func sparklineSVG(counts []int64, title string) string {
+	var maxCount int64
+	for _, c := range counts {
+		maxCount = max(maxCount, c)
+	}
+
+	// The line is inset by a pixel so its stroke isn't cut off.
+	step := float64(sparklineWidth)
+	if len(counts) > 1 {
+		step = float64(sparklineWidth) / float64(len(counts)-1)
+	}
+	points := make([]string, len(counts))
+	for i, c := range counts {
+		y := float64(sparklineHeight - 1)
+		if maxCount > 0 {
+			y -= float64(c) / float64(maxCount) * float64(sparklineHeight-2)
+		}
+		points[i] = fmt.Sprintf("%.1f,%.1f", float64(i)*step, y)
+	}
+	line := strings.Join(points, " ")
+	area := fmt.Sprintf("0,%d %s %d,%d", sparklineHeight, line, sparklineWidth, sparklineHeight)
+	title = html.EscapeString(title)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`, sparklineWidth, sparklineHeight, sparklineWidth, sparklineHeight, title)
+	fmt.Fprintf(&b, `<title>%s</title>`, title)
+	fmt.Fprintf(&b, `<polygon points="%s" fill="#3b82f6" fill-opacity=".2"/>`, area)
+	fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="#3b82f6" stroke-width="1.5" stroke-linejoin="round"/>`, line)
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// writeSVG writes an SVG image with cache headers. Images showing the
+// owner's own data may include hidden hosts or a private profile, so
+// they are not stored by shared caches.
+func writeSVG(w http.ResponseWriter, svg string, owner bool) {
+	scope := "public"
+	if owner {
+		scope = "private"
+	}
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, badgeMaxAge))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(svg))
+}
+
+// svgUser returns the user of a badge or sparkline request, with their
+// access rules.
+func (h *Handlers) svgUser(r *http.Request) (*usermodel.User, *access, error) {
+	username := r.PathValue("username")
+
+	user, err := h.userStorage.GetByUsername(r.Context(), username)
+	if err != nil {
+		return nil, nil, &RequestError{StatusCode: http.StatusNotFound, Err: fmt.Errorf("user not found: %s", username)}
+	}
+
+	acc, err := h.access(r, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, acc, nil
+}
+
+// Badge serves an SVG badge with the keystrokes of a user for a period,
+// `?period=today|week|month`, the week by default.
+func (h *Handlers) Badge(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.badge(w, r))
+}
+
+func (h *Handlers) badge(w http.ResponseWriter, r *http.Request) error {
+	period, err := parsePeriod(r)
+	if err != nil {
+		return err
+	}
+	label, ok := badgeLabels[period]
+	if !ok {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("badges cover today, the week or the month")}
+	}
+
+	user, acc, err := h.svgUser(r)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(location(r, user))
+	start, _ := period.Bounds(now)
+
+	daily, err := h.storage.GetUserDaily(r.Context(), user.ID, storage.Range{
+		From:    start,
+		To:      now,
+		Exclude: acc.Exclude,
+	})
+	if err != nil {
+		return fmt.Errorf("get daily data: %w", err)
+	}
+
+	var total int64
+	for _, d := range daily {
+		total += d.Count
+	}
+
+	writeSVG(w, badgeSVG(label, formatCount(total)), acc.Owner)
+	return nil
+}
+
+// Sparkline serves an SVG sparkline of the daily keystrokes of a user over
+// the last 30 days.
+func (h *Handlers) Sparkline(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.sparkline(w, r))
+}
+
+func (h *Handlers) sparkline(w http.ResponseWriter, r *http.Request) error {
+	user, acc, err := h.svgUser(r)
+	if err != nil {
+		return err
+	}
+
+	loc := location(r, user)
+	daily := storage.LastDays(30, loc)
+	daily.Exclude = acc.Exclude
+
+	dailyData, err := h.storage.GetUserDaily(r.Context(), user.ID, daily)
+	if err != nil {
+		return fmt.Errorf("get daily data: %w", err)
+	}
+
+	byDay := make(map[string]int64)
+	for _, d := range dailyData {
+		byDay[d.Stamp] += d.Count
+	}
+
+	// 30 days, from 29 days ago to today.
+	now := time.Now().In(loc)
+	counts := make([]int64, 30)
+	var total int64
+	for i := range counts {
+		counts[i] = byDay[now.AddDate(0, 0, i-29).Format("2006-01-02")]
+		total += counts[i]
+	}
+
+	title := fmt.Sprintf("%s keystrokes in the last 30 days", formatCount(total))
+	writeSVG(w, sparklineSVG(counts, title), acc.Owner)
+	return nil
+}
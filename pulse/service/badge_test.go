@@ -0,0 +1,55 @@
+package service
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCount(t *testing.T) {
+	cases := map[int64]string{
+		0:        "0",
+		950:      "950",
+		1000:     "1k",
+		1234:     "1.2k",
+		123456:   "123k",
+		4500000:  "4.5M",
+		12000000: "12M",
+	}
+	for n, want := range cases {
+		assert.Equal(t, want, formatCount(n), n)
+	}
+}
+
+func TestBadgeSVG(t *testing.T) {
+	svg := badgeSVG("keystrokes this week", "123k")
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="188" height="20"`))
+	assert.Contains(t, svg, `<title>keystrokes this week: 123k</title>`)
+	assert.Contains(t, svg, `<text x="75" y="14">keystrokes this week</text><text x="169" y="14">123k</text>`)
+
+	assert.Contains(t, badgeSVG("<label>", "1"), "&lt;label&gt;")
+}
+
+func TestSparklineSVG(t *testing.T) {
+	svg := sparklineSVG([]int64{0, 10, 5}, "15 keystrokes in the last 30 days")
+	assert.Contains(t, svg, `<title>15 keystrokes in the last 30 days</title>`)
+	assert.Contains(t, svg, `<polyline points="0.0,29.0 75.0,1.0 150.0,15.0"`)
+	assert.Contains(t, svg, `<polygon points="0,30 0.0,29.0 75.0,1.0 150.0,15.0 150,30"`)
+
+	// Without keystrokes the line is flat along the bottom.
+	assert.Contains(t, sparklineSVG([]int64{0, 0}, ""), `<polyline points="0.0,29.0 150.0,29.0"`)
+}
+
+func TestWriteSVG(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeSVG(w, "<svg/>", false)
+	assert.Equal(t, "image/svg+xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "<svg/>", w.Body.String())
+
+	w = httptest.NewRecorder()
+	writeSVG(w, "<svg/>", true)
+	assert.Equal(t, "private, max-age=300", w.Header().Get("Cache-Control"))
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+// CalendarDays is the number of days on the calendar heatmap.
+const CalendarDays = 365
+
+// PunchcardDays is the number of days covered by the punchcard. Hourly
+// rows are kept unless hourly retention is configured, a shorter
+// retention leaves the older days out.
+const PunchcardDays = 90
+
+// Calendar and punchcard cell sizes in pixels, and the room left for
+// labels.
+const (
+	calendarCell   = 10
+	calendarStep   = 12
+	calendarLeft   = 28
+	calendarTop    = 16
+	punchcardStep  = 20
+	punchcardLeft  = 32
+	punchcardBelow = 16
+)
+
+// weekdayLabels label the rows of the calendar and punchcard, Monday
+// first.
+var weekdayLabels = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// weekdayRow returns the row of a weekday, with weeks starting on Monday.
+func weekdayRow(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+// heatLevel buckets a count into levels 0 to 4, 0 for no keystrokes.
+func heatLevel(count, maxCount int64) int {
+	if count <= 0 || maxCount <= 0 {
+		return 0
+	}
+	return int(min(max((count*4+maxCount-1)/maxCount, 1), 4))
+}
+
+// heatOpacity is the fill opacity of each heat level.
+var heatOpacity = []string{".08", ".35", ".55", ".78", "1"}
+
+// calendarSVG renders a heatmap of the CalendarDays days up to today, a
+// column per week and a row per weekday.
+func calendarSVG(days []storage.DayCount, today time.Time) string {
+	counts := make(map[string]int64, len(days))
+	var maxCount, total int64
+	for _, d := range days {
+		counts[d.Stamp] += d.Count
+		maxCount = max(maxCount, counts[d.Stamp])
+		total += d.Count
+	}
+
+	// Days are stepped at noon, so daylight saving changes don't skip
+	// or repeat a date.
+	loc := today.Location()
+	last := time.Date(today.Year(), today.Month(), today.Day(), 12, 0, 0, 0, loc)
+	first := last.AddDate(0, 0, 1-CalendarDays)
+	gridStart := first.AddDate(0, 0, -weekdayRow(first.Weekday()))
+	weeks := 0
+	for day := gridStart; !day.After(last); day = day.AddDate(0, 0, 7) {
+		weeks++
+	}
+
+	width := calendarLeft + weeks*calendarStep
+	height := calendarTop + 7*calendarStep
+	title := html.EscapeString(fmt.Sprintf("%s keystrokes in the last year", formatCount(total)))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`, width, height, width, height, title)
+	fmt.Fprintf(&b, `<title>%s</title>`, title)
+	b.WriteString(`<g fill="#888" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="9">`)
+	for _, row := range []int{0, 2, 4} {
+		fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`, calendarTop+row*calendarStep+calendarCell-1, weekdayLabels[row])
+	}
+	b.WriteString(`</g><g fill="#3b82f6">`)
+
+	week, labelled := 0, 0
+	for day := gridStart; !day.After(last); day = day.AddDate(0, 0, 1) {
+		row := weekdayRow(day.Weekday())
+		if row == 0 && day.After(gridStart) {
+			week++
+		}
+		if day.Before(first) {
+			continue
+		}
+
+		x := calendarLeft + week*calendarStep
+		// Months are labelled above the week of their first Monday,
+		// if there is room for the label.
+		if row == 0 && day.Day() <= 7 && (labelled == 0 || week-labelled >= 3) {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" fill="#888" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="9">%s</text>`, x, calendarTop-5, day.Format("Jan"))
+			labelled = week
+		}
+
+		stamp := day.Format("2006-01-02")
+		count := counts[stamp]
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill-opacity="%s"><title>%s — %d keystrokes</title></rect>`,
+			x, calendarTop+row*calendarStep, calendarCell, calendarCell, heatOpacity[heatLevel(count, maxCount)], stamp, count)
+	}
+	b.WriteString(`</g></svg>`)
+	return b.String()
+}
+
+// punchcardSVG renders keystrokes per weekday and hour as circles, their
+// area proportional to the count.
+func punchcardSVG(p *storage.Punchcard, title string) string {
+	maxCount := p.Max()
+	width := punchcardLeft + 24*punchcardStep
+	height := 7*punchcardStep + punchcardBelow
+	title = html.EscapeString(title)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`, width, height, width, height, title)
+	fmt.Fprintf(&b, `<title>%s</title>`, title)
+	b.WriteString(`<g fill="#888" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="9">`)
+	for row, label := range weekdayLabels {
+		fmt.Fprintf(&b, `<text x="0" y="%d">%s</text>`, row*punchcardStep+punchcardStep/2+3, label)
+	}
+	for hour := 0; hour < 24; hour += 3 {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">%02d</text>`, punchcardLeft+hour*punchcardStep+punchcardStep/2, height-4, hour)
+	}
+	b.WriteString(`</g><g fill="#3b82f6">`)
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		row := weekdayRow(day)
+		for hour, count := range p[day] {
+			radius := 0.0
+			if maxCount > 0 && count > 0 {
+				radius = max(math.Sqrt(float64(count)/float64(maxCount))*(punchcardStep/2-1), 1)
+			}
+			fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="%.1f"><title>%s %02d:00 — %d keystrokes</title></circle>`,
+				punchcardLeft+hour*punchcardStep+punchcardStep/2, row*punchcardStep+punchcardStep/2, radius, weekdayLabels[row], hour, count)
+		}
+	}
+	b.WriteString(`</g></svg>`)
+	return b.String()
+}
+
+// Calendar serves an SVG heatmap of the daily keystrokes of a user over
+// the last CalendarDays days.
+func (h *Handlers) Calendar(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.calendar(w, r))
+}
+
+func (h *Handlers) calendar(w http.ResponseWriter, r *http.Request) error {
+	user, acc, err := h.svgUser(r)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().In(location(r, user))
+
+	days, err := h.storage.GetUserCalendar(r.Context(), user.ID, storage.Range{
+		From:    now.AddDate(0, 0, 1-CalendarDays),
+		To:      now,
+		Exclude: acc.Exclude,
+	})
+	if err != nil {
+		return fmt.Errorf("get calendar data: %w", err)
+	}
+
+	writeSVG(w, calendarSVG(days, now), acc.Owner)
+	return nil
+}
+
+// Punchcard serves an SVG punchcard of the keystrokes of a user per
+// weekday and hour over the last PunchcardDays days.
+func (h *Handlers) Punchcard(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.punchcard(w, r))
+}
+
+func (h *Handlers) punchcard(w http.ResponseWriter, r *http.Request) error {
+	user, acc, err := h.svgUser(r)
+	if err != nil {
+		return err
+	}
+
+	loc := location(r, user)
+	rng := storage.LastDays(PunchcardDays, loc)
+	rng.Exclude = acc.Exclude
+
+	punchcard, err := h.storage.GetUserPunchcard(r.Context(), user.ID, loc, rng)
+	if err != nil {
+		return fmt.Errorf("get punchcard data: %w", err)
+	}
+
+	title := fmt.Sprintf("Keystrokes by weekday and hour over the last %d days (%s)", PunchcardDays, loc)
+	writeSVG(w, punchcardSVG(punchcard, title), acc.Owner)
+	return nil
+}
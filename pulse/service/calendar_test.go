@@ -0,0 +1,57 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+func TestHeatLevel(t *testing.T) {
+	assert.Equal(t, 0, heatLevel(0, 100))
+	assert.Equal(t, 0, heatLevel(5, 0))
+	assert.Equal(t, 1, heatLevel(1, 100))
+	assert.Equal(t, 1, heatLevel(25, 100))
+	assert.Equal(t, 2, heatLevel(26, 100))
+	assert.Equal(t, 4, heatLevel(100, 100))
+}
+
+func TestCalendarSVG(t *testing.T) {
+	// A Wednesday, the first day is a Thursday a year earlier.
+	today := time.Date(2025, 3, 12, 18, 0, 0, 0, time.UTC)
+	svg := calendarSVG([]storage.DayCount{
+		{Stamp: "2025-03-12", Count: 100},
+		{Stamp: "2025-03-10", Count: 20},
+		{Stamp: "2024-03-12", Count: 50},
+	}, today)
+
+	assert.Equal(t, CalendarDays, strings.Count(svg, "<rect "))
+	// 53 weeks, from Monday 2024-03-11 to Sunday 2025-03-16.
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="664" height="100"`))
+	assert.Contains(t, svg, `<title>170 keystrokes in the last year</title>`)
+
+	// The last day is in the last week, on Wednesday.
+	assert.Contains(t, svg, `<rect x="652" y="40" width="10" height="10" rx="2" fill-opacity="1"><title>2025-03-12 — 100 keystrokes</title></rect>`)
+	assert.Contains(t, svg, `<rect x="652" y="16" width="10" height="10" rx="2" fill-opacity=".35"><title>2025-03-10 — 20 keystrokes</title></rect>`)
+	assert.Contains(t, svg, `<rect x="652" y="28" width="10" height="10" rx="2" fill-opacity=".08"><title>2025-03-11 — 0 keystrokes</title></rect>`)
+	// The day a year ago is left off the calendar.
+	assert.NotContains(t, svg, "2024-03-12")
+	assert.Contains(t, svg, "2024-03-13")
+	assert.Contains(t, svg, `>Apr</text>`)
+}
+
+func TestPunchcardSVG(t *testing.T) {
+	var p storage.Punchcard
+	p[time.Monday][9] = 400
+	p[time.Sunday][23] = 100
+
+	svg := punchcardSVG(&p, "weekly <rhythm>")
+	assert.Equal(t, 7*24, strings.Count(svg, "<circle "))
+	assert.Contains(t, svg, `<title>weekly &lt;rhythm&gt;</title>`)
+	assert.Contains(t, svg, `<circle cx="222" cy="10" r="9.0"><title>Mon 09:00 — 400 keystrokes</title></circle>`)
+	assert.Contains(t, svg, `<circle cx="502" cy="130" r="4.5"><title>Sun 23:00 — 100 keystrokes</title></circle>`)
+	assert.Contains(t, svg, `<circle cx="42" cy="10" r="0.0"><title>Mon 00:00 — 0 keystrokes</title></circle>`)
+}
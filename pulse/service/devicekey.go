@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+	"github.com/titpetric/platform-app/user"
+)
+
+type deviceKeyContextKey struct{}
+
+// deviceKeyFromContext returns the device key that authenticated the
+// request, if any.
+func deviceKeyFromContext(ctx context.Context) (*storage.DeviceKey, bool) {
+	key, ok := ctx.Value(deviceKeyContextKey{}).(*storage.DeviceKey)
+	return key, ok
+}
+
+// deviceKeyAuth authenticates requests carrying a device key in the
+// Authorization header. Other requests are passed to fallback, which
+// authenticates user tokens.
+func (h *Handlers) deviceKeyAuth(fallback func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		userAuth := fallback(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !storage.IsDeviceKey(token) {
+				userAuth.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			key, err := h.storage.AuthenticateDeviceKey(ctx, token)
+			if err != nil {
+				h.errorHandler(w, r, &RequestError{StatusCode: http.StatusUnauthorized, Err: err})
+				return
+			}
+
+			owner, err := h.userStorage.Get(ctx, key.UserID)
+			if err == nil {
+				err = owner.Validate()
+			}
+			if err != nil {
+				h.errorHandler(w, r, &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired})
+				return
+			}
+
+			ctx = user.SetSessionUser(ctx, owner)
+			ctx = context.WithValue(ctx, deviceKeyContextKey{}, key)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// bindEntries checks that a device key only ingests for its own host.
+// Entries without a hostname are recorded for the bound host.
+func bindEntries(ctx context.Context, entries []storage.Entry) error {
+	key, ok := deviceKeyFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for i, entry := range entries {
+		switch entry.Hostname {
+		case "":
+			entries[i].Hostname = key.Hostname
+		case key.Hostname:
+		default:
+			return &RequestError{StatusCode: http.StatusForbidden, Err: fmt.Errorf("device key is bound to %q, not %q", key.Hostname, entry.Hostname)}
+		}
+	}
+	return nil
+}
+
+// DeviceKeyCreated holds a new device key. The key is only returned once.
+type DeviceKeyCreated struct {
+	storage.DeviceKey
+	Key string `json:"key"`
+}
+
+// deviceKeyError maps device key storage errors to request errors.
+func deviceKeyError(err error) error {
+	if errors.Is(err, storage.ErrDeviceKeyNotFound) {
+		return &RequestError{StatusCode: http.StatusNotFound, Err: err}
+	}
+	return hostError(err)
+}
+
+// GetDeviceKeys lists the device keys of the authenticated user.
+func (h *Handlers) GetDeviceKeys(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.getDeviceKeys(w, r))
+}
+
+func (h *Handlers) getDeviceKeys(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	keys, err := h.storage.ListDeviceKeys(ctx, sessionUser.ID)
+	if err != nil {
+		return err
+	}
+
+	platform.JSON(w, r, http.StatusOK, keys)
+	return nil
+}
+
+// PostDeviceKey creates a device key for a host of the authenticated
+// user.
+func (h *Handlers) PostDeviceKey(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.postDeviceKey(w, r))
+}
+
+func (h *Handlers) postDeviceKey(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	body := struct {
+		Hostname string `json:"hostname"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+
+	key, secret, err := h.storage.CreateDeviceKey(ctx, sessionUser.ID, body.Hostname)
+	if err != nil {
+		return deviceKeyError(err)
+	}
+
+	platform.JSON(w, r, http.StatusCreated, DeviceKeyCreated{DeviceKey: *key, Key: secret})
+	return nil
+}
+
+// DeleteDeviceKey revokes a device key of the authenticated user.
+func (h *Handlers) DeleteDeviceKey(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.deleteDeviceKey(w, r))
+}
+
+func (h *Handlers) deleteDeviceKey(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	if err := h.storage.RevokeDeviceKey(ctx, sessionUser.ID, r.PathValue("id")); err != nil {
+		return deviceKeyError(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// PostDeviceKeyForm handles the create and revoke forms for device keys
+// on the device management page. A created key is shown once.
+func (h *Handlers) PostDeviceKeyForm(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.postDeviceKeyForm(w, r))
+}
+
+func (h *Handlers) postDeviceKeyForm(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+
+	var (
+		message string
+		err     error
+	)
+	switch r.FormValue("action") {
+	case "create":
+		hostname := r.FormValue("hostname")
+		key, secret, createErr := h.storage.CreateDeviceKey(ctx, sessionUser.ID, hostname)
+		if createErr == nil {
+			return h.renderHostsPage(w, r, &DeviceKeyCreated{DeviceKey: *key, Key: secret})
+		}
+		err = deviceKeyError(createErr)
+	case "revoke":
+		err = deviceKeyError(h.storage.RevokeDeviceKey(ctx, sessionUser.ID, r.FormValue("id")))
+		message = "Revoked the device key."
+	default:
+		err = &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("unknown action")}
+	}
+
+	query := url.Values{}
+	if err != nil {
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) {
+			return err
+		}
+		query.Set("error", reqErr.Error())
+	} else {
+		query.Set("message", message)
+	}
+
+	http.Redirect(w, r, "/pulse/hosts?"+query.Encode(), http.StatusSeeOther)
+	return nil
+}
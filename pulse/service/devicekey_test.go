@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+func TestBindEntries(t *testing.T) {
+	entries := []storage.Entry{{Hostname: "laptop"}, {Hostname: "desktop"}}
+	require.NoError(t, bindEntries(context.Background(), entries))
+	assert.Equal(t, "desktop", entries[1].Hostname)
+
+	ctx := context.WithValue(context.Background(), deviceKeyContextKey{}, &storage.DeviceKey{Hostname: "laptop"})
+
+	entries = []storage.Entry{{Hostname: "laptop"}, {Hostname: ""}}
+	require.NoError(t, bindEntries(ctx, entries))
+	assert.Equal(t, "laptop", entries[1].Hostname)
+
+	err := bindEntries(ctx, []storage.Entry{{Hostname: "laptop"}, {Hostname: "desktop"}})
+	var reqErr *RequestError
+	require.True(t, errors.As(err, &reqErr))
+	assert.Equal(t, http.StatusForbidden, reqErr.StatusCode)
+}
@@ -0,0 +1,13 @@
+package service
+
+// RequestError is an error carrying the HTTP status code to respond with.
+type RequestError struct {
+	StatusCode int
+
+	Err error
+}
+
+// Error returns the underlying error message.
+func (r *RequestError) Error() string {
+	return r.Err.Error()
+}
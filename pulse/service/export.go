@@ -0,0 +1,194 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+	"github.com/titpetric/platform-app/user"
+	usermodel "github.com/titpetric/platform-app/user/model"
+)
+
+// exportRow is a single row of exported pulse data.
+type exportRow struct {
+	Hostname string `json:"hostname"`
+	Stamp    string `json:"stamp"`
+	Count    int64  `json:"count"`
+}
+
+// GetUserHourly exports a user's hourly pulse data as JSON or CSV.
+func (h *Handlers) GetUserHourly(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.getUserExport(w, r, "hourly"))
+}
+
+// GetUserDaily exports a user's daily pulse data as JSON or CSV.
+func (h *Handlers) GetUserDaily(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.getUserExport(w, r, "daily"))
+}
+
+func (h *Handlers) getUserExport(w http.ResponseWriter, r *http.Request, kind string) error {
+	ctx := r.Context()
+	username := r.PathValue("username")
+
+	user, err := h.userStorage.GetByUsername(ctx, username)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusNotFound, Err: fmt.Errorf("user not found: %s", username)}
+	}
+
+	acc, err := h.access(r, user)
+	if err != nil {
+		return err
+	}
+
+	return h.export(w, r, user, acc.Exclude, kind)
+}
+
+// GetExport exports the authenticated user's pulse data as JSON or CSV.
+// The `resolution` query parameter selects daily (default) or hourly
+// rows.
+func (h *Handlers) GetExport(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.getExport(w, r))
+}
+
+func (h *Handlers) getExport(w http.ResponseWriter, r *http.Request) error {
+	sessionUser, ok := user.GetSessionUser(r.Context())
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	kind := r.URL.Query().Get("resolution")
+	switch kind {
+	case "":
+		kind = "daily"
+	case "daily", "hourly":
+	default:
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: fmt.Errorf("invalid resolution: %q", kind)}
+	}
+
+	return h.export(w, r, sessionUser, nil, kind)
+}
+
+// export writes the daily or hourly rows of u within the requested range,
+// leaving out the hosts in exclude.
+func (h *Handlers) export(w http.ResponseWriter, r *http.Request, u *usermodel.User, exclude []string, kind string) error {
+	ctx := r.Context()
+	loc := location(r, u)
+
+	rng, err := parseRange(r, loc)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+	rng.Exclude = exclude
+
+	var data []storage.DailyHostCount
+	switch kind {
+	case "hourly":
+		data, err = h.storage.GetUserHourlyAll(ctx, u.ID, rng)
+	default:
+		data, err = h.storage.GetUserDaily(ctx, u.ID, rng)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows := make([]exportRow, 0, len(data))
+	for _, d := range data {
+		stamp := d.Stamp
+		if kind == "hourly" {
+			t, err := storage.ParseStamp(d.Stamp)
+			if err != nil {
+				return err
+			}
+			stamp = t.In(loc).Format(time.RFC3339)
+		}
+		rows = append(rows, exportRow{
+			Hostname: d.Hostname,
+			Stamp:    stamp,
+			Count:    d.Count,
+		})
+	}
+
+	if wantsCSV(r) {
+		return writeCSV(w, fmt.Sprintf("%s-%s.csv", u.Username, kind), rows)
+	}
+
+	platform.JSON(w, r, http.StatusOK, rows)
+	return nil
+}
+
+// parseRange reads the `from`, `to` and `host` query parameters. Dates
+// are accepted as YYYY-MM-DD in loc, or as RFC3339 timestamps. The range
+// defaults to the last 30 days.
+func parseRange(r *http.Request, loc *time.Location) (storage.Range, error) {
+	query := r.URL.Query()
+	rng := storage.LastDays(30, loc)
+	rng.Hostname = query.Get("host")
+
+	if v := query.Get("from"); v != "" {
+		from, err := parseDate(v, loc, false)
+		if err != nil {
+			return rng, fmt.Errorf("invalid from: %w", err)
+		}
+		rng.From = from
+	}
+	if v := query.Get("to"); v != "" {
+		to, err := parseDate(v, loc, true)
+		if err != nil {
+			return rng, fmt.Errorf("invalid to: %w", err)
+		}
+		rng.To = to
+	}
+	if rng.To.Before(rng.From) {
+		return rng, errors.New("to must not be before from")
+	}
+	return rng, nil
+}
+
+// parseDate parses a date or timestamp. A date used as the end of a
+// range covers the whole day.
+func parseDate(value string, loc *time.Location, end bool) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		if end {
+			t = t.Add(24*time.Hour - time.Second)
+		}
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, err
+	}
+	return t.In(loc), nil
+}
+
+// wantsCSV reports whether the client asked for CSV, either with the
+// Accept header or with `?format=csv`.
+func wantsCSV(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "csv"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/csv")
+}
+
+func writeCSV(w http.ResponseWriter, filename string, rows []exportRow) error {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"hostname", "stamp", "count"}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := out.Write([]string{row.Hostname, row.Stamp, strconv.FormatInt(row.Count, 10)}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Ljubljana")
+	require.NoError(t, err)
+
+	r := httptest.NewRequest("GET", "/api/pulse/user/daily?from=2025-03-01&to=2025-03-31&host=lab", nil)
+	rng, err := parseRange(r, loc)
+	require.NoError(t, err)
+	assert.Equal(t, "lab", rng.Hostname)
+	assert.Equal(t, "2025-03-01 00:00:00", rng.From.Format("2006-01-02 15:04:05"))
+	assert.Equal(t, "2025-03-31 23:59:59", rng.To.Format("2006-01-02 15:04:05"))
+	assert.Equal(t, loc, rng.From.Location())
+
+	r = httptest.NewRequest("GET", "/api/pulse/user/daily?from=2025-03-31&to=2025-03-01", nil)
+	_, err = parseRange(r, loc)
+	assert.Error(t, err)
+
+	r = httptest.NewRequest("GET", "/api/pulse/user/daily?from=yesterday", nil)
+	_, err = parseRange(r, loc)
+	assert.Error(t, err)
+}
+
+func TestWantsCSV(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/pulse/user/daily", nil)
+	assert.False(t, wantsCSV(r))
+
+	r.Header.Set("Accept", "text/csv")
+	assert.True(t, wantsCSV(r))
+
+	r = httptest.NewRequest("GET", "/api/pulse/user/daily?format=csv", nil)
+	assert.True(t, wantsCSV(r))
+
+	r = httptest.NewRequest("GET", "/api/pulse/user/daily?format=json", nil)
+	r.Header.Set("Accept", "text/csv")
+	assert.False(t, wantsCSV(r))
+}
@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/titpetric/platform"
+	"github.com/titpetric/vuego"
+
+	emailmodel "github.com/titpetric/platform-app/email/model"
+	"github.com/titpetric/platform-app/pulse/storage"
+	"github.com/titpetric/platform-app/user"
+	usermodel "github.com/titpetric/platform-app/user/model"
+)
+
+// Mailer queues emails for sending. The email module handler implements it.
+type Mailer interface {
+	AddEmail(ctx context.Context, email *emailmodel.Email) error
+}
+
+// GoalUsers looks up the users goals belong to. The user storage
+// implements it.
+type GoalUsers interface {
+	Get(ctx context.Context, id string) (*usermodel.User, error)
+	GetEmail(ctx context.Context, userID string) (string, error)
+}
+
+// GoalOptions configures the goal alerts job.
+type GoalOptions struct {
+	// Interval is how often goals are checked (default: 5 minutes)
+	Interval time.Duration
+	// Logger for structured logging
+	Logger *slog.Logger
+}
+
+// DefaultGoalOptions returns the default goal alert options.
+func DefaultGoalOptions() GoalOptions {
+	return GoalOptions{
+		Interval: 5 * time.Minute,
+		Logger:   slog.New(slog.NewTextHandler(os.Stderr, nil)),
+	}
+}
+
+// GoalOptionsFromEnv returns the default goal alert options, with the
+// interval overridden by PULSE_GOALS_INTERVAL.
+func GoalOptionsFromEnv() GoalOptions {
+	options := DefaultGoalOptions()
+	if value, ok := os.LookupEnv("PULSE_GOALS_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(value); err == nil && interval > 0 {
+			options.Interval = interval
+		}
+	}
+	return options
+}
+
+// GoalReport holds the number of goals checked, alerts sent and goals
+// that failed to check.
+type GoalReport struct {
+	Checked int `json:"checked"`
+	Alerts  int `json:"alerts"`
+	Failed  int `json:"failed"`
+}
+
+// Goals periodically checks user goals and emails alerts.
+type Goals struct {
+	storage     *storage.Storage
+	userStorage GoalUsers
+	mailer      Mailer
+	options     GoalOptions
+	logger      *slog.Logger
+
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewGoals creates a goal alerts job sending email with mailer.
+func NewGoals(s *storage.Storage, userStorage GoalUsers, mailer Mailer, options GoalOptions) *Goals {
+	if options.Interval <= 0 {
+		options.Interval = 5 * time.Minute
+	}
+	if options.Logger == nil {
+		options.Logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Goals{
+		storage:     s,
+		userStorage: userStorage,
+		mailer:      mailer,
+		options:     options,
+		logger:      options.Logger,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Start checks goals now, and then at every interval.
+func (g *Goals) Start() {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		ticker := time.NewTicker(g.options.Interval)
+		defer ticker.Stop()
+
+		for {
+			g.Run(g.ctx)
+
+			select {
+			case <-g.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the job and waits for a running pass to finish.
+func (g *Goals) Stop() {
+	g.cancel()
+	g.wg.Wait()
+}
+
+// Run checks all goals once, and emails an alert for each failed goal.
+// A goal alerts at most once per day. Goals that fail to check are
+// logged and retried on the next run.
+func (g *Goals) Run(ctx context.Context) (*GoalReport, error) {
+	report, err := g.run(ctx, time.Now())
+	if err != nil {
+		g.logger.Error("pulse goals failed", "error", err)
+		return nil, err
+	}
+
+	g.logger.Info("pulse goals done", "checked", report.Checked, "alerts", report.Alerts, "failed", report.Failed)
+	return report, nil
+}
+
+func (g *Goals) run(ctx context.Context, now time.Time) (*GoalReport, error) {
+	goals, err := g.storage.ListAllGoals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GoalReport{}
+	for _, goal := range goals {
+		if err := g.check(ctx, &goal, now, report); err != nil {
+			g.logger.Error("pulse goal failed", "goal", goal.ID, "user", goal.UserID, "error", err)
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+// check checks a goal, and emails an alert if it failed. The goal is
+// settled before the alert is queued, so an alert is never sent twice.
+func (g *Goals) check(ctx context.Context, goal *storage.Goal, now time.Time, report *GoalReport) error {
+	u, err := g.userStorage.Get(ctx, goal.UserID)
+	if err != nil {
+		// The user is gone, their goals are left alone.
+		return nil
+	}
+
+	check, err := g.storage.CheckGoal(ctx, goal, now.In(u.Location()))
+	if err != nil {
+		return err
+	}
+	if check == nil {
+		return nil
+	}
+	report.Checked++
+
+	// A met at most goal can still fail later in the day.
+	if check.Met && goal.Comparison == storage.GoalAtMost {
+		return nil
+	}
+
+	if check.Met {
+		return g.storage.SettleGoal(ctx, goal.ID, check.Day)
+	}
+
+	address, err := g.userStorage.GetEmail(ctx, goal.UserID)
+	if err != nil {
+		return err
+	}
+	if err := g.storage.SettleGoal(ctx, goal.ID, check.Day); err != nil {
+		return err
+	}
+
+	subject, body := goalAlert(goal, check, u.FullName)
+	if err := g.mailer.AddEmail(ctx, emailmodel.NewEmail(address, subject, body)); err != nil {
+		return fmt.Errorf("send goal alert: %w", err)
+	}
+	report.Alerts++
+	return nil
+}
+
+// goalAlert composes the alert email for a failed goal check.
+func goalAlert(goal *storage.Goal, check *storage.GoalCheck, fullName string) (subject, body string) {
+	unit := "keystrokes"
+	if goal.Metric == storage.GoalActiveMinutes {
+		unit = "active minutes"
+	}
+
+	if goal.Comparison == storage.GoalAtMost {
+		subject = "Pulse goal exceeded"
+		body = fmt.Sprintf("Hi %s,\n\nYou reached %d %s today, over your goal of %s.\n\nTime for a break?\n", fullName, check.Value, unit, goal)
+	} else {
+		subject = "Pulse goal missed"
+		body = fmt.Sprintf("Hi %s,\n\nYou had %d %s on %s, short of your goal of %s.\n", fullName, check.Value, unit, check.Day.Format("Monday, January 2"), goal)
+	}
+	return subject, body + "\nYou can change your goals on the pulse goals page.\n"
+}
+
+// goalError maps goal storage errors to request errors.
+func goalError(err error) error {
+	if errors.Is(err, storage.ErrGoalNotFound) {
+		return &RequestError{StatusCode: http.StatusNotFound, Err: err}
+	}
+	return err
+}
+
+// GetGoals lists the goals of the authenticated user.
+func (h *Handlers) GetGoals(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.getGoals(w, r))
+}
+
+func (h *Handlers) getGoals(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	goals, err := h.storage.ListGoals(ctx, sessionUser.ID)
+	if err != nil {
+		return err
+	}
+
+	platform.JSON(w, r, http.StatusOK, goals)
+	return nil
+}
+
+// PostGoal adds a goal for the authenticated user.
+func (h *Handlers) PostGoal(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.postGoal(w, r))
+}
+
+func (h *Handlers) postGoal(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	goal := storage.Goal{
+		Days:   storage.GoalEveryDay,
+		ToHour: 24,
+	}
+	if err := json.NewDecoder(r.Body).Decode(&goal); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+	goal.UserID = sessionUser.ID
+
+	if err := h.storage.CreateGoal(ctx, &goal); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+
+	platform.JSON(w, r, http.StatusCreated, goal)
+	return nil
+}
+
+// DeleteGoal removes a goal of the authenticated user.
+func (h *Handlers) DeleteGoal(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.deleteGoal(w, r))
+}
+
+func (h *Handlers) deleteGoal(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	if err := h.storage.DeleteGoal(ctx, sessionUser.ID, r.PathValue("id")); err != nil {
+		return goalError(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// GoalsPage serves the goals page.
+func (h *Handlers) GoalsPage(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.goalsPage(w, r))
+}
+
+func (h *Handlers) goalsPage(w http.ResponseWriter, r *http.Request) error {
+	type goalRow struct {
+		ID          string `json:"id"`
+		Description string `json:"description"`
+	}
+
+	type viewData struct {
+		Title    string    `json:"title"`
+		Username string    `json:"username"`
+		Goals    []goalRow `json:"goals"`
+		Message  string    `json:"message"`
+		Error    string    `json:"error"`
+	}
+
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return nil
+	}
+
+	goals, err := h.storage.ListGoals(ctx, sessionUser.ID)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]goalRow, 0, len(goals))
+	for _, goal := range goals {
+		rows = append(rows, goalRow{
+			ID:          goal.ID,
+			Description: goal.String(),
+		})
+	}
+
+	query := r.URL.Query()
+	data := viewData{
+		Title:    "Pulse goals",
+		Username: sessionUser.Username,
+		Goals:    rows,
+		Message:  query.Get("message"),
+		Error:    query.Get("error"),
+	}
+
+	goalsPage := vuego.View[viewData](h.vuego, "goals.vuego", data)
+
+	return goalsPage.Render(ctx, w)
+}
+
+// PostGoalForm handles the add and delete forms on the goals page.
+func (h *Handlers) PostGoalForm(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.postGoalForm(w, r))
+}
+
+func (h *Handlers) postGoalForm(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+
+	var (
+		message string
+		err     error
+	)
+	switch r.FormValue("action") {
+	case "add":
+		goal := storage.Goal{
+			UserID:     sessionUser.ID,
+			Metric:     storage.GoalMetric(r.FormValue("metric")),
+			Comparison: storage.GoalComparison(r.FormValue("comparison")),
+			Days:       storage.GoalDays(r.FormValue("days")),
+		}
+		goal.Threshold, _ = strconv.ParseInt(r.FormValue("threshold"), 10, 64)
+		goal.FromHour, _ = strconv.Atoi(r.FormValue("from_hour"))
+		goal.ToHour, _ = strconv.Atoi(r.FormValue("to_hour"))
+
+		if createErr := h.storage.CreateGoal(ctx, &goal); createErr != nil {
+			err = &RequestError{StatusCode: http.StatusBadRequest, Err: createErr}
+		}
+		message = "Added goal: " + goal.String() + "."
+	case "delete":
+		err = goalError(h.storage.DeleteGoal(ctx, sessionUser.ID, r.FormValue("id")))
+		message = "Deleted the goal."
+	default:
+		err = &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("unknown action")}
+	}
+
+	query := url.Values{}
+	if err != nil {
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) {
+			return err
+		}
+		query.Set("error", reqErr.Error())
+	} else {
+		query.Set("message", message)
+	}
+
+	http.Redirect(w, r, "/pulse/goals?"+query.Encode(), http.StatusSeeOther)
+	return nil
+}
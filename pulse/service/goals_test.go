@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	emailmodel "github.com/titpetric/platform-app/email/model"
+	"github.com/titpetric/platform-app/pulse/schema"
+	"github.com/titpetric/platform-app/pulse/storage"
+	usermodel "github.com/titpetric/platform-app/user/model"
+)
+
+func TestGoalAlert(t *testing.T) {
+	goal := &storage.Goal{Metric: storage.GoalActiveMinutes, Comparison: storage.GoalAtMost, Threshold: 360, Days: storage.GoalEveryDay, FromHour: 20, ToHour: 24}
+	subject, body := goalAlert(goal, &storage.GoalCheck{Value: 372}, "Jane")
+	assert.Equal(t, "Pulse goal exceeded", subject)
+	assert.Contains(t, body, "Hi Jane,")
+	assert.Contains(t, body, "372 active minutes today, over your goal of at most 360 active minutes between 20:00 and 24:00 every day")
+
+	goal = &storage.Goal{Metric: storage.GoalKeystrokes, Comparison: storage.GoalAtLeast, Threshold: 5000, Days: storage.GoalWeekdays, ToHour: 24}
+	day := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+	subject, body = goalAlert(goal, &storage.GoalCheck{Day: day, Value: 100}, "Jane")
+	assert.Equal(t, "Pulse goal missed", subject)
+	assert.Contains(t, body, "100 keystrokes on Wednesday, March 11, short of your goal of at least 5000 keystrokes on weekdays")
+}
+
+type mockGoalUsers struct{}
+
+func (mockGoalUsers) Get(_ context.Context, id string) (*usermodel.User, error) {
+	return &usermodel.User{ID: id, FullName: id}, nil
+}
+
+func (mockGoalUsers) GetEmail(_ context.Context, userID string) (string, error) {
+	if userID == "NOEMAIL" {
+		return "", errors.New("no email")
+	}
+	return userID + "@titpetric.com", nil
+}
+
+type mockMailer struct {
+	sent []string
+}
+
+func (m *mockMailer) AddEmail(_ context.Context, email *emailmodel.Email) error {
+	m.sent = append(m.sent, email.Recipient)
+	if email.Recipient == "BOUNCE@titpetric.com" {
+		return errors.New("queue full")
+	}
+	return nil
+}
+
+func TestGoalsRun(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlx.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, storage.Migrate(ctx, db, schema.Migrations()))
+	s := storage.NewStorage(db)
+
+	for _, userID := range []string{"BOUNCE", "NOEMAIL", "OK"} {
+		require.NoError(t, s.CreateGoal(ctx, &storage.Goal{UserID: userID, Metric: storage.GoalKeystrokes, Comparison: storage.GoalAtLeast, Threshold: 100, Days: storage.GoalEveryDay, ToHour: 24}))
+	}
+
+	mailer := &mockMailer{}
+	goals := NewGoals(s, mockGoalUsers{}, mailer, GoalOptions{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+
+	// Failing goals don't stop the others from alerting.
+	now := time.Now().AddDate(0, 0, 3)
+	report, err := goals.run(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, &GoalReport{Checked: 3, Alerts: 1, Failed: 2}, report)
+	assert.Equal(t, []string{"BOUNCE@titpetric.com", "OK@titpetric.com"}, mailer.sent)
+
+	// Goals are settled before alerting, so alerts aren't sent twice.
+	// Goals that failed before settling are retried.
+	report, err = goals.run(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, &GoalReport{Checked: 1, Failed: 1}, report)
+	assert.Len(t, mailer.sent, 2)
+}
@@ -1,9 +1,13 @@
 package service
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -11,47 +15,140 @@ import (
 	"github.com/titpetric/platform"
 	"github.com/titpetric/vuego"
 
+	"github.com/titpetric/platform-app/pulse/model"
 	"github.com/titpetric/platform-app/pulse/storage"
 	"github.com/titpetric/platform-app/user"
+	usermodel "github.com/titpetric/platform-app/user/model"
 	userstorage "github.com/titpetric/platform-app/user/storage"
 )
 
 // Handlers serves pulse HTTP endpoints.
 type Handlers struct {
-	storage     *storage.Storage
-	userStorage *userstorage.UserStorage
-	vuego       vuego.Template
-	fs          fs.FS
+	storage      *storage.Storage
+	userStorage  *userstorage.UserStorage
+	groupStorage *userstorage.GroupStorage
+	vuego        vuego.Template
+	fs           fs.FS
 }
 
 // NewHandlers creates handlers backed by the given storage.
-func NewHandlers(storage *storage.Storage, userStorage *userstorage.UserStorage, viewFS fs.FS) *Handlers {
+func NewHandlers(storage *storage.Storage, userStorage *userstorage.UserStorage, groupStorage *userstorage.GroupStorage, viewFS fs.FS) *Handlers {
 	return &Handlers{
-		fs:          viewFS,
-		storage:     storage,
-		userStorage: userStorage,
-		vuego:       vuego.NewFS(viewFS),
+		fs:           viewFS,
+		storage:      storage,
+		userStorage:  userStorage,
+		groupStorage: groupStorage,
+		vuego:        vuego.NewFS(viewFS),
+	}
+}
+
+// reservedUsernames are the pulse routes at the level of usernames, in
+// /pulse/{username} and /api/pulse/{username}. Users can't register them.
+var reservedUsernames = []string{
+	"export",
+	"goals",
+	"hosts",
+	"ingest",
+	"keys",
+	"leaderboard",
+	"settings",
+	"stats",
+	"teams",
+}
+
+// ReservedUsers looks up users by username. The user storage implements
+// it.
+type ReservedUsers interface {
+	GetByUsername(ctx context.Context, username string) (*usermodel.User, error)
+}
+
+// checkReservedUsernames logs existing users with a reserved username.
+// Reserving a name only rejects new sign-ups, the pulse pages of a user
+// registered before are shadowed by the route until they are renamed.
+func checkReservedUsernames(ctx context.Context, users ReservedUsers, logger *slog.Logger) {
+	for _, name := range reservedUsernames {
+		u, err := users.GetByUsername(ctx, name)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			logger.Error("failed to check reserved usernames", "error", err)
+			return
+		}
+		logger.Warn("username is reserved by pulse routes, rename the user", "user_id", u.ID, "username", u.Username)
 	}
 }
 
 // Mount registers pulse routes on the router.
 func (h *Handlers) Mount(r platform.Router) {
+	usermodel.ReserveUsernames(reservedUsernames...)
+
 	r.Get("/assets/*", http.FileServer(http.FS(h.fs)).ServeHTTP)
 
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/pulse", http.StatusFound)
 	})
-	r.Get("/pulse", h.IndexPage)
-	r.Get("/pulse/{username}", h.UserPage)
 
+	// Pages are public, a logged in user can see their own private data.
 	r.Group(func(r platform.Router) {
-		r.Use(user.NewMiddleware(user.AuthHeader()))
+		r.Use(user.NewMiddleware(user.AuthCookie(), user.AuthOptional()))
+		r.Get("/pulse", h.IndexPage)
+		r.Get("/pulse/settings", h.SettingsPage)
+		r.Post("/pulse/settings", h.PostSettings)
+		r.Get("/pulse/goals", h.GoalsPage)
+		r.Post("/pulse/goals", h.PostGoalForm)
+		r.Get("/pulse/hosts", h.HostsPage)
+		r.Post("/pulse/hosts/{hostname}", h.PostHost)
+		r.Post("/pulse/keys", h.PostDeviceKeyForm)
+		r.Post("/pulse/teams", h.PostTeams)
+		r.Get("/pulse/teams/{id}", h.TeamPage)
+		r.Post("/pulse/teams/{id}", h.PostTeam)
+		r.Get("/pulse/{username}", h.UserPage)
+		r.Get("/pulse/{username}/live", h.Live)
+		r.Get("/pulse/{username}/badge.svg", h.Badge)
+		r.Get("/pulse/{username}/sparkline.svg", h.Sparkline)
+		r.Get("/pulse/{username}/calendar.svg", h.Calendar)
+		r.Get("/pulse/{username}/punchcard.svg", h.Punchcard)
+	})
+
+	r.Group(func(r platform.Router) {
+		r.Use(user.NewMiddleware(user.AuthHeader(), user.AuthCookie(), user.AuthOptional()))
+		r.Get("/api/pulse/settings", h.GetSettings)
+		r.Get("/api/pulse/stats", h.GetStats)
+		r.Get("/api/pulse/export", h.GetExport)
+		r.Put("/api/pulse/settings", h.PutSettings)
+		r.Get("/api/pulse/goals", h.GetGoals)
+		r.Post("/api/pulse/goals", h.PostGoal)
+		r.Delete("/api/pulse/goals/{id}", h.DeleteGoal)
+		r.Get("/api/pulse/hosts", h.GetHosts)
+		r.Patch("/api/pulse/hosts/{hostname}", h.PatchHost)
+		r.Delete("/api/pulse/hosts/{hostname}", h.DeleteHost)
+		r.Get("/api/pulse/keys", h.GetDeviceKeys)
+		r.Post("/api/pulse/keys", h.PostDeviceKey)
+		r.Delete("/api/pulse/keys/{id}", h.DeleteDeviceKey)
+		r.Get("/api/pulse/leaderboard", h.GetLeaderboard)
+		r.Get("/api/pulse/teams/{id}/leaderboard", h.GetTeamLeaderboard)
+		r.Get("/api/pulse/{username}/hourly", h.GetUserHourly)
+		r.Get("/api/pulse/{username}/daily", h.GetUserDaily)
+		r.Get("/api/pulse/{username}/activity", h.GetUserActivity)
+	})
+
+	// Ingest accepts user tokens and device keys bound to a host.
+	r.Group(func(r platform.Router) {
+		r.Use(h.deviceKeyAuth(user.NewMiddleware(user.AuthHeader())))
 		r.Post("/api/pulse/ingest", h.PostIngest)
+		r.Post("/api/pulse/ingest/batch", h.PostIngestBatch)
 	})
 }
 
 func (h *Handlers) errorHandler(w http.ResponseWriter, r *http.Request, err error) {
 	if err != nil {
+		var reqErr *RequestError
+		if errors.As(err, &reqErr) {
+			http.Error(w, reqErr.Error(), reqErr.StatusCode)
+			return
+		}
+
 		ctx := r.Context()
 		oida.RecordError(ctx, err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -64,47 +161,56 @@ func (h *Handlers) IndexPage(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handlers) indexPage(w http.ResponseWriter, r *http.Request) error {
-	type userEntry struct {
-		Username string `json:"username"`
-		Count    int64  `json:"count"`
-		Href     string `json:"href"`
+	type teamEntry struct {
+		Title string `json:"title"`
+		Href  string `json:"href"`
 	}
 
 	type viewData struct {
-		Title string      `json:"title"`
-		Menu  []any       `json:"menu"`
-		Users []userEntry `json:"users"`
+		Title    string       `json:"title"`
+		Menu     []any        `json:"menu"`
+		Periods  []periodTab  `json:"periods"`
+		Rankings []rankingRow `json:"rankings"`
+		Teams    []teamEntry  `json:"teams"`
+		LoggedIn bool         `json:"loggedIn"`
 	}
 
 	ctx := r.Context()
+	period, err := parsePeriod(r)
+	if err != nil {
+		return err
+	}
 
 	users, err := h.userStorage.List(ctx)
 	if err != nil {
 		return fmt.Errorf("list users: %w", err)
 	}
 
-	counts, err := h.storage.ListUserCounts(ctx)
+	// Only public profiles are ranked, users always see themselves.
+	board, err := h.leaderboard(r, users, period, false, false)
 	if err != nil {
-		return fmt.Errorf("list user counts: %w", err)
+		return err
 	}
 
-	countByUser := make(map[string]int64, len(counts))
-	for _, c := range counts {
-		countByUser[c.UserID] = c.Count
+	teams, err := h.groupStorage.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list teams: %w", err)
 	}
 
-	entries := make([]userEntry, 0, len(users))
-	for _, u := range users {
-		entries = append(entries, userEntry{
-			Username: u.Username,
-			Count:    countByUser[u.ID],
-			Href:     "/pulse/" + u.Username,
+	teamEntries := make([]teamEntry, 0, len(teams))
+	for _, team := range teams {
+		teamEntries = append(teamEntries, teamEntry{
+			Title: team.Title,
+			Href:  "/pulse/teams/" + team.ID,
 		})
 	}
 
 	data := viewData{
-		Title: fmt.Sprintf("Pulse keystroke analytics"),
-		Users: entries,
+		Title:    fmt.Sprintf("Pulse keystroke analytics"),
+		Periods:  periodTabs("/pulse", period),
+		Rankings: rankingRows(board),
+		Teams:    teamEntries,
+		LoggedIn: user.IsLoggedIn(ctx),
 	}
 
 	indexPage := vuego.View[viewData](h.vuego, "index.vuego", data)
@@ -132,23 +238,35 @@ func (h *Handlers) userPage(w http.ResponseWriter, r *http.Request) error {
 		Tooltip string `json:"tooltip"`
 	}
 
+	type categoryShare struct {
+		Label   string `json:"label"`
+		Percent int    `json:"percent"`
+		Style   string `json:"style"`
+		Tooltip string `json:"tooltip"`
+	}
+
 	type hostDaily struct {
-		Hostname   string     `json:"hostname"`
-		Color      string     `json:"color"`
-		Total      int64      `json:"total"`
-		TotalLabel string     `json:"totalLabel"`
-		NumDays    int        `json:"numDays"`
-		NumLabel   string     `json:"numLabel"`
-		Bars       []dailyBar `json:"bars"`
+		Hostname   string          `json:"hostname"`
+		Color      string          `json:"color"`
+		Total      int64           `json:"total"`
+		TotalLabel string          `json:"totalLabel"`
+		NumDays    int             `json:"numDays"`
+		NumLabel   string          `json:"numLabel"`
+		Bars       []dailyBar      `json:"bars"`
+		Mix        []categoryShare `json:"mix"`
 	}
 
 	type viewData struct {
-		Title      string      `json:"title"`
-		Username   string      `json:"username"`
-		FullName   string      `json:"fullName"`
-		Hourly     []hourlyBar `json:"hourly"`
-		Devices    []hostDaily `json:"devices"`
-		TotalCount int64       `json:"totalCount"`
+		Title      string          `json:"title"`
+		Username   string          `json:"username"`
+		FullName   string          `json:"fullName"`
+		Timezone   string          `json:"timezone"`
+		Owner      bool            `json:"owner"`
+		Hourly     []hourlyBar     `json:"hourly"`
+		Devices    []hostDaily     `json:"devices"`
+		Activity   activitySummary `json:"activity"`
+		Live       *liveView       `json:"live"`
+		TotalCount int64           `json:"totalCount"`
 	}
 
 	ctx := r.Context()
@@ -156,23 +274,49 @@ func (h *Handlers) userPage(w http.ResponseWriter, r *http.Request) error {
 
 	user, err := h.userStorage.GetByUsername(ctx, username)
 	if err != nil {
-		return fmt.Errorf("get user: %w", err)
+		return &RequestError{StatusCode: http.StatusNotFound, Err: fmt.Errorf("user not found: %s", username)}
 	}
 
-	hourlyData, err := h.storage.GetUserHourly(ctx, user.ID)
+	acc, err := h.access(r, user)
+	if err != nil {
+		return err
+	}
+
+	loc := location(r, user)
+
+	daily := storage.LastDays(30, loc)
+	daily.Exclude = acc.Exclude
+
+	hourlyData, err := h.storage.GetUserHourly(ctx, user.ID, loc, daily)
 	if err != nil {
 		return fmt.Errorf("get hourly data: %w", err)
 	}
 
-	dailyData, err := h.storage.GetUserDaily(ctx, user.ID)
+	dailyData, err := h.storage.GetUserDaily(ctx, user.ID, daily)
 	if err != nil {
 		return fmt.Errorf("get daily data: %w", err)
 	}
 
+	activity, err := h.storage.GetUserActivity(ctx, user.ID, loc, acc.Exclude...)
+	if err != nil {
+		return fmt.Errorf("get activity: %w", err)
+	}
+
+	categoryData, err := h.storage.GetUserCategories(ctx, user.ID, daily)
+	if err != nil {
+		return fmt.Errorf("get category data: %w", err)
+	}
+
 	hosts, err := h.storage.GetUserHosts(ctx, user.ID)
 	if err != nil {
 		return fmt.Errorf("get hosts: %w", err)
 	}
+	hosts = acc.visibleHosts(hosts)
+
+	live, err := h.liveState(r, user.ID, loc, acc.Exclude)
+	if err != nil {
+		return fmt.Errorf("get live activity: %w", err)
+	}
 
 	// Build hourly bars (24 hours, 0-23) with percentages for CSS
 	hourlyMap := make(map[int]int64)
@@ -222,7 +366,7 @@ func (h *Handlers) userPage(w http.ResponseWriter, r *http.Request) error {
 	var totalCount int64
 
 	// Build fixed list of 30 date stamps (29 days ago to today)
-	now := time.Now().UTC()
+	now := time.Now().In(loc)
 	var dateStamps []string
 	for i := 29; i >= 0; i-- {
 		dateStamps = append(dateStamps, now.AddDate(0, 0, -i).Format("2006-01-02"))
@@ -237,6 +381,15 @@ func (h *Handlers) userPage(w http.ResponseWriter, r *http.Request) error {
 		hostDailyMap[d.Hostname][d.Stamp] = d.Count
 	}
 
+	// Index key category counts by host
+	hostCategoryMap := make(map[string]map[model.Category]int64)
+	for _, c := range categoryData {
+		if hostCategoryMap[c.Hostname] == nil {
+			hostCategoryMap[c.Hostname] = make(map[model.Category]int64)
+		}
+		hostCategoryMap[c.Hostname][c.Category] = c.Count
+	}
+
 	for i, host := range hosts {
 		color := colors[i%len(colors)]
 		counts := hostDailyMap[host]
@@ -271,6 +424,25 @@ func (h *Handlers) userPage(w http.ResponseWriter, r *http.Request) error {
 			}
 		}
 
+		var categoryTotal int64
+		for _, c := range hostCategoryMap[host] {
+			categoryTotal += c
+		}
+		var mix []categoryShare
+		for j, category := range model.Categories() {
+			c := hostCategoryMap[host][category]
+			if c == 0 {
+				continue
+			}
+			percent := int(c * 100 / categoryTotal)
+			mix = append(mix, categoryShare{
+				Label:   string(category),
+				Percent: percent,
+				Style:   fmt.Sprintf("width: %.2f%%; background-color: %s", float64(c)*100/float64(categoryTotal), colors[j%len(colors)]),
+				Tooltip: fmt.Sprintf("%s — %d%% (%d keystrokes)", category, percent, c),
+			})
+		}
+
 		totalCount += hostTotal
 
 		devices = append(devices, hostDaily{
@@ -281,6 +453,7 @@ func (h *Handlers) userPage(w http.ResponseWriter, r *http.Request) error {
 			NumDays:    activeDays,
 			NumLabel:   "days",
 			Bars:       bars,
+			Mix:        mix,
 		})
 	}
 
@@ -288,8 +461,12 @@ func (h *Handlers) userPage(w http.ResponseWriter, r *http.Request) error {
 		Title:      fmt.Sprintf("Pulse for %s @%s", user.FullName, user.Username),
 		Username:   user.Username,
 		FullName:   user.FullName,
+		Timezone:   loc.String(),
+		Owner:      acc.Owner,
 		Hourly:     hourly,
 		Devices:    devices,
+		Activity:   summarizeActivity(activity, 5),
+		Live:       live,
 		TotalCount: totalCount,
 	}
 
@@ -298,6 +475,17 @@ func (h *Handlers) userPage(w http.ResponseWriter, r *http.Request) error {
 	return userPage.Render(ctx, w)
 }
 
+// location returns the timezone to render a user's pulse data in. The
+// `tz` query parameter overrides the timezone from the user profile.
+func location(r *http.Request, u *usermodel.User) *time.Location {
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc
+		}
+	}
+	return u.Location()
+}
+
 // PostIngest handles pulse data ingestion requests.
 func (h *Handlers) PostIngest(w http.ResponseWriter, r *http.Request) {
 	h.errorHandler(w, r, h.postIngest(w, r))
@@ -305,23 +493,71 @@ func (h *Handlers) PostIngest(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) postIngest(w http.ResponseWriter, r *http.Request) error {
 	type ingestBody struct {
+		ID       string `json:"id"`
 		Count    int64  `json:"count"`
 		Hostname string `json:"hostname"`
 	}
 
 	body := ingestBody{}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		return err
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
 	}
 
 	if body.Count <= 0 {
-		return fmt.Errorf("count must be positive: %d", body.Count)
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: fmt.Errorf("count must be positive: %d", body.Count)}
+	}
+
+	ctx := r.Context()
+	entries := []storage.Entry{
+		{
+			Hostname: body.Hostname,
+			Stamp:    time.Now(),
+			Count:    body.Count,
+		},
+	}
+	if err := bindEntries(ctx, entries); err != nil {
+		return err
+	}
+	if err := h.storage.PulseBatch(ctx, body.ID, entries); err != nil {
+		return ingestError(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ingestError maps ingest validation errors to request errors, so
+// clients don't retry them.
+func ingestError(err error) error {
+	if errors.Is(err, storage.ErrInvalidBatch) {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+	return hostError(err)
+}
+
+// PostIngestBatch handles timestamped batch keystroke ingestion.
+func (h *Handlers) PostIngestBatch(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.postIngestBatch(w, r))
+}
+
+func (h *Handlers) postIngestBatch(w http.ResponseWriter, r *http.Request) error {
+	type ingestBody struct {
+		ID      string          `json:"id"`
+		Entries []storage.Entry `json:"entries"`
+	}
+
+	body := ingestBody{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
 	}
 
 	ctx := r.Context()
-	if err := h.storage.Pulse(ctx, body.Count, body.Hostname); err != nil {
+	if err := bindEntries(ctx, body.Entries); err != nil {
 		return err
 	}
+	if err := h.storage.PulseBatch(ctx, body.ID, body.Entries); err != nil {
+		return ingestError(err)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 	return nil
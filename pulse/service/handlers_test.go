@@ -0,0 +1,100 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+	usermodel "github.com/titpetric/platform-app/user/model"
+)
+
+func TestReservedUsernames(t *testing.T) {
+	r := chi.NewRouter()
+	NewHandlers(nil, nil, nil, fstest.MapFS{}).Mount(r)
+
+	// Every static route at the level of usernames is reserved.
+	err := chi.Walk(r, func(_ string, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		for _, prefix := range []string{"/pulse/", "/api/pulse/"} {
+			rest, ok := strings.CutPrefix(route, prefix)
+			if !ok {
+				continue
+			}
+			name, _, _ := strings.Cut(rest, "/")
+			if name != "" && !strings.HasPrefix(name, "{") {
+				assert.True(t, slices.Contains(reservedUsernames, name), "%s is not reserved", route)
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	req := &usermodel.UserCreateRequest{Username: "settings"}
+	assert.ErrorIs(t, req.ValidateUsername(), usermodel.ErrUsernameReserved)
+}
+
+type mockReservedUsers map[string]error
+
+func (m mockReservedUsers) GetByUsername(_ context.Context, username string) (*usermodel.User, error) {
+	if err, ok := m[username]; ok {
+		return nil, err
+	}
+	if username == "settings" {
+		return &usermodel.User{ID: "SETTINGSUSER", Username: username}, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func TestCheckReservedUsernames(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	checkReservedUsernames(context.Background(), mockReservedUsers{}, logger)
+	assert.Contains(t, buf.String(), "level=WARN")
+	assert.Contains(t, buf.String(), "user_id=SETTINGSUSER username=settings")
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+
+	buf.Reset()
+	checkReservedUsernames(context.Background(), mockReservedUsers{"export": errors.New("no such table: user")}, logger)
+	assert.Contains(t, buf.String(), "level=ERROR")
+	assert.Contains(t, buf.String(), "no such table: user")
+}
+
+func TestIngestBadRequest(t *testing.T) {
+	h := NewHandlers(nil, nil, nil, fstest.MapFS{})
+
+	for _, tc := range []struct {
+		handler http.HandlerFunc
+		body    string
+	}{
+		{h.PostIngest, `{"count":`},
+		{h.PostIngest, `{"count":0,"hostname":"lab"}`},
+		{h.PostIngestBatch, `{"entries":`},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/pulse/ingest", strings.NewReader(tc.body))
+		w := httptest.NewRecorder()
+		tc.handler(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, tc.body)
+	}
+
+	var reqErr *RequestError
+	err := ingestError(fmt.Errorf("%w: too many entries", storage.ErrInvalidBatch))
+	require.True(t, errors.As(err, &reqErr))
+	assert.Equal(t, http.StatusBadRequest, reqErr.StatusCode)
+
+	err = ingestError(errors.New("database is locked"))
+	assert.False(t, errors.As(err, &reqErr))
+}
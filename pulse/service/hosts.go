@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/titpetric/platform"
+	"github.com/titpetric/vuego"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+	"github.com/titpetric/platform-app/user"
+)
+
+// HostUpdate changes a host. Only one of Hostname and MergeInto may be
+// set: Hostname renames the host, MergeInto moves its history into
+// another host and removes it.
+type HostUpdate struct {
+	Hostname  *string `json:"hostname"`
+	MergeInto *string `json:"merge_into"`
+	Hidden    *bool   `json:"hidden"`
+}
+
+// hostError maps host storage errors to request errors.
+func hostError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrHostNotFound):
+		return &RequestError{StatusCode: http.StatusNotFound, Err: err}
+	case errors.Is(err, storage.ErrHostExists):
+		return &RequestError{StatusCode: http.StatusConflict, Err: err}
+	case errors.Is(err, storage.ErrInvalidHostname):
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+	return err
+}
+
+func (h *Handlers) updateHost(ctx context.Context, userID, hostname string, update HostUpdate) error {
+	if update.Hostname != nil && update.MergeInto != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("hostname and merge_into can't be used together")}
+	}
+
+	switch {
+	case update.Hostname != nil:
+		if err := h.storage.RenameHost(ctx, userID, hostname, *update.Hostname); err != nil {
+			return hostError(err)
+		}
+		hostname = *update.Hostname
+	case update.MergeInto != nil:
+		if *update.MergeInto == hostname {
+			return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("can't merge a host into itself")}
+		}
+		if err := h.storage.MergeHosts(ctx, userID, hostname, *update.MergeInto); err != nil {
+			return hostError(err)
+		}
+		hostname = *update.MergeInto
+	}
+
+	if update.Hidden != nil {
+		if err := h.storage.SetHostHidden(ctx, userID, hostname, *update.Hidden); err != nil {
+			return hostError(err)
+		}
+	}
+	return nil
+}
+
+// GetHosts lists the hosts of the authenticated user.
+func (h *Handlers) GetHosts(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.getHosts(w, r))
+}
+
+func (h *Handlers) getHosts(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	hosts, err := h.storage.ListHostSummaries(ctx, sessionUser.ID)
+	if err != nil {
+		return err
+	}
+
+	platform.JSON(w, r, http.StatusOK, hosts)
+	return nil
+}
+
+// PatchHost renames, merges or hides a host of the authenticated user.
+func (h *Handlers) PatchHost(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.patchHost(w, r))
+}
+
+func (h *Handlers) patchHost(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	body := HostUpdate{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+
+	if err := h.updateHost(ctx, sessionUser.ID, r.PathValue("hostname"), body); err != nil {
+		return err
+	}
+
+	hosts, err := h.storage.ListHostSummaries(ctx, sessionUser.ID)
+	if err != nil {
+		return err
+	}
+
+	platform.JSON(w, r, http.StatusOK, hosts)
+	return nil
+}
+
+// DeleteHost removes a host of the authenticated user with its history.
+func (h *Handlers) DeleteHost(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.deleteHost(w, r))
+}
+
+func (h *Handlers) deleteHost(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	if err := h.storage.DeleteHost(ctx, sessionUser.ID, r.PathValue("hostname")); err != nil {
+		return hostError(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// HostsPage serves the device management page.
+func (h *Handlers) HostsPage(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.hostsPage(w, r))
+}
+
+func (h *Handlers) hostsPage(w http.ResponseWriter, r *http.Request) error {
+	return h.renderHostsPage(w, r, nil)
+}
+
+// renderHostsPage renders the device management page, showing newKey if
+// a device key was just created.
+func (h *Handlers) renderHostsPage(w http.ResponseWriter, r *http.Request, newKey *DeviceKeyCreated) error {
+	type hostRow struct {
+		Hostname   string `json:"hostname"`
+		Count      int64  `json:"count"`
+		LastActive string `json:"lastActive"`
+		Hidden     bool   `json:"hidden"`
+	}
+
+	type keyRow struct {
+		ID        string `json:"id"`
+		Hostname  string `json:"hostname"`
+		CreatedAt string `json:"createdAt"`
+		LastUsed  string `json:"lastUsed"`
+	}
+
+	type viewData struct {
+		Title    string            `json:"title"`
+		Username string            `json:"username"`
+		Hosts    []hostRow         `json:"hosts"`
+		Keys     []keyRow          `json:"keys"`
+		NewKey   *DeviceKeyCreated `json:"newKey"`
+		Message  string            `json:"message"`
+		Error    string            `json:"error"`
+	}
+
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return nil
+	}
+
+	hosts, err := h.storage.ListHostSummaries(ctx, sessionUser.ID)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]hostRow, 0, len(hosts))
+	for _, host := range hosts {
+		row := hostRow{
+			Hostname:   host.Hostname,
+			Count:      host.Count,
+			LastActive: "never",
+			Hidden:     host.Hidden,
+		}
+		if host.LastActive != nil {
+			row.LastActive = host.LastActive.Format("2006-01-02")
+		}
+		rows = append(rows, row)
+	}
+
+	keys, err := h.storage.ListDeviceKeys(ctx, sessionUser.ID)
+	if err != nil {
+		return err
+	}
+
+	keyRows := make([]keyRow, 0, len(keys))
+	for _, key := range keys {
+		row := keyRow{
+			ID:        key.ID,
+			Hostname:  key.Hostname,
+			CreatedAt: key.CreatedAt.Format("2006-01-02"),
+			LastUsed:  "never",
+		}
+		if key.LastUsedAt != nil {
+			row.LastUsed = key.LastUsedAt.Format("2006-01-02 15:04")
+		}
+		keyRows = append(keyRows, row)
+	}
+
+	query := r.URL.Query()
+	data := viewData{
+		Title:    "Pulse devices",
+		Username: sessionUser.Username,
+		Hosts:    rows,
+		Keys:     keyRows,
+		NewKey:   newKey,
+		Message:  query.Get("message"),
+		Error:    query.Get("error"),
+	}
+
+	hostsPage := vuego.View[viewData](h.vuego, "hosts.vuego", data)
+
+	return hostsPage.Render(ctx, w)
+}
+
+// PostHost handles the rename, merge and delete forms on the device
+// management page.
+func (h *Handlers) PostHost(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.postHost(w, r))
+}
+
+func (h *Handlers) postHost(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+
+	hostname := r.PathValue("hostname")
+
+	var (
+		message string
+		err     error
+	)
+	switch r.FormValue("action") {
+	case "rename":
+		to := r.FormValue("hostname")
+		err = h.updateHost(ctx, sessionUser.ID, hostname, HostUpdate{Hostname: &to})
+		message = "Renamed " + hostname + " to " + to + "."
+	case "merge":
+		into := r.FormValue("merge_into")
+		err = h.updateHost(ctx, sessionUser.ID, hostname, HostUpdate{MergeInto: &into})
+		message = "Merged " + hostname + " into " + into + "."
+	case "delete":
+		err = hostError(h.storage.DeleteHost(ctx, sessionUser.ID, hostname))
+		message = "Deleted " + hostname + "."
+	default:
+		err = &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("unknown action")}
+	}
+
+	query := url.Values{}
+	if err != nil {
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) {
+			return err
+		}
+		query.Set("error", reqErr.Error())
+	} else {
+		query.Set("message", message)
+	}
+
+	http.Redirect(w, r, "/pulse/hosts?"+query.Encode(), http.StatusSeeOther)
+	return nil
+}
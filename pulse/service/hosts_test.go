@@ -0,0 +1,29 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+func TestHostError(t *testing.T) {
+	status := func(err error) int {
+		var reqErr *RequestError
+		require.True(t, errors.As(hostError(err), &reqErr))
+		return reqErr.StatusCode
+	}
+
+	assert.Equal(t, http.StatusNotFound, status(fmt.Errorf("%w: lab", storage.ErrHostNotFound)))
+	assert.Equal(t, http.StatusConflict, status(storage.ErrHostExists))
+	assert.Equal(t, http.StatusBadRequest, status(storage.ErrInvalidHostname))
+
+	other := errors.New("database is locked")
+	assert.Equal(t, other, hostError(other))
+	assert.NoError(t, hostError(nil))
+}
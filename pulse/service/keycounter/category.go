@@ -0,0 +1,67 @@
+package keycounter
+
+import "github.com/titpetric/platform-app/pulse/model"
+
+// Linux key codes from <linux/input-event-codes.h>, grouped by category.
+var keyCategories = func() map[uint16]model.Category {
+	result := make(map[uint16]model.Category)
+	set := func(category model.Category, codes ...uint16) {
+		for _, code := range codes {
+			result[code] = category
+		}
+	}
+	span := func(from, to uint16) []uint16 {
+		var codes []uint16
+		for code := from; code <= to; code++ {
+			codes = append(codes, code)
+		}
+		return codes
+	}
+
+	// KEY_1..KEY_0, KEY_Q..KEY_P, KEY_A..KEY_L, KEY_Z..KEY_M
+	set(model.CategoryAlphanumeric, span(2, 11)...)
+	set(model.CategoryAlphanumeric, span(16, 25)...)
+	set(model.CategoryAlphanumeric, span(30, 38)...)
+	set(model.CategoryAlphanumeric, span(44, 50)...)
+	// KEY_KP7..KEY_KP0
+	set(model.CategoryAlphanumeric, 71, 72, 73, 75, 76, 77, 79, 80, 81, 82)
+
+	// KEY_LEFTCTRL, KEY_LEFTSHIFT, KEY_RIGHTSHIFT, KEY_LEFTALT, KEY_CAPSLOCK,
+	// KEY_NUMLOCK, KEY_SCROLLLOCK, KEY_RIGHTCTRL, KEY_RIGHTALT,
+	// KEY_LEFTMETA, KEY_RIGHTMETA
+	set(model.CategoryModifier, 29, 42, 54, 56, 58, 69, 70, 97, 100, 125, 126)
+
+	// KEY_HOME..KEY_INSERT
+	set(model.CategoryNavigation, span(102, 110)...)
+
+	// KEY_ESC, KEY_F1..KEY_F10, KEY_F11, KEY_F12, KEY_F13..KEY_F24
+	set(model.CategoryFunction, 1)
+	set(model.CategoryFunction, span(59, 68)...)
+	set(model.CategoryFunction, 87, 88)
+	set(model.CategoryFunction, span(183, 194)...)
+
+	// KEY_BACKSPACE, KEY_DELETE
+	set(model.CategoryDelete, 14, 111)
+
+	return result
+}()
+
+// Classify returns the category of a key code.
+func Classify(code uint16) model.Category {
+	if category, ok := keyCategories[code]; ok {
+		return category
+	}
+	return model.CategoryOther
+}
+
+// Counts holds key presses per category.
+type Counts map[model.Category]int64
+
+// Total returns the sum of all category counts.
+func (c Counts) Total() int64 {
+	var total int64
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
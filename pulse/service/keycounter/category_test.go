@@ -0,0 +1,31 @@
+package keycounter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/titpetric/platform-app/pulse/model"
+)
+
+func TestClassify(t *testing.T) {
+	cases := map[uint16]model.Category{
+		30:  model.CategoryAlphanumeric, // KEY_A
+		11:  model.CategoryAlphanumeric, // KEY_0
+		42:  model.CategoryModifier,     // KEY_LEFTSHIFT
+		125: model.CategoryModifier,     // KEY_LEFTMETA
+		103: model.CategoryNavigation,   // KEY_UP
+		1:   model.CategoryFunction,     // KEY_ESC
+		88:  model.CategoryFunction,     // KEY_F12
+		14:  model.CategoryDelete,       // KEY_BACKSPACE
+		111: model.CategoryDelete,       // KEY_DELETE
+		57:  model.CategoryOther,        // KEY_SPACE
+		28:  model.CategoryOther,        // KEY_ENTER
+	}
+	for code, want := range cases {
+		assert.Equal(t, want, Classify(code), "code %d", code)
+	}
+
+	counts := Counts{model.CategoryAlphanumeric: 3, model.CategoryDelete: 1}
+	assert.Equal(t, int64(4), counts.Total())
+}
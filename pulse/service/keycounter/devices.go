@@ -0,0 +1,72 @@
+package keycounter
+
+import (
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Device describes an input device file.
+type Device struct {
+	// Path is the event device file, e.g. /dev/input/event3.
+	Path string
+	// Name is the device name reported by the kernel.
+	Name string
+
+	// keys is the EV_KEY capability bitmap, as returned by EVIOCGBIT.
+	keys []uint
+}
+
+// probeDevice reads the name and key capabilities of an event device from
+// sysfs. The kernel exposes the EVIOCGNAME and EVIOCGBIT(EV_KEY) results
+// there, so no ioctl on the device itself is needed. Missing sysfs entries
+// leave the device unnamed and without capabilities.
+func probeDevice(sysfs, path string) Device {
+	dir := filepath.Join(sysfs, filepath.Base(path), "device")
+	d := Device{
+		Path: path,
+	}
+	if name, err := os.ReadFile(filepath.Join(dir, "name")); err == nil {
+		d.Name = strings.TrimSpace(string(name))
+	}
+	if keys, err := os.ReadFile(filepath.Join(dir, "capabilities", "key")); err == nil {
+		d.keys = parseBitmap(string(keys))
+	}
+	return d
+}
+
+// parseBitmap parses a sysfs capability bitmap. The bitmap is printed as
+// space separated hex words, most significant word first.
+func parseBitmap(value string) []uint {
+	fields := strings.Fields(value)
+	result := make([]uint, len(fields))
+	for i, field := range fields {
+		word, err := strconv.ParseUint(field, 16, bits.UintSize)
+		if err != nil {
+			return nil
+		}
+		result[len(fields)-1-i] = uint(word)
+	}
+	return result
+}
+
+// HasKey reports whether the device can emit the key code.
+func (d Device) HasKey(code uint16) bool {
+	word, bit := int(code)/bits.UintSize, uint(code)%bits.UintSize
+	return word < len(d.keys) && d.keys[word]&(1<<bit) != 0
+}
+
+// IsKeyboard reports whether the device has the keys of a typing keyboard.
+// Mice, power buttons and lid switches also emit EV_KEY events, but
+// none of them can type letters.
+func (d Device) IsKeyboard() bool {
+	// KEY_Q..KEY_P, KEY_A..KEY_L, KEY_Z..KEY_M, KEY_SPACE
+	for _, code := range []uint16{16, 25, 30, 38, 44, 50, 57} {
+		if !d.HasKey(code) {
+			return false
+		}
+	}
+	return true
+}
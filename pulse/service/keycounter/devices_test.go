@@ -0,0 +1,169 @@
+package keycounter
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/pulse/model"
+)
+
+const (
+	keyboardCaps = "ffffffffffffffff fffffffffffffffe"
+	mouseCaps    = "10000 0 0 0 0"
+)
+
+// fakeDevice creates an event file and its sysfs description.
+func fakeDevice(t *testing.T, dir, name, label, caps string) string {
+	t.Helper()
+
+	sysfs := filepath.Join(dir, "sys", name, "device")
+	require.NoError(t, os.MkdirAll(filepath.Join(sysfs, "capabilities"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(sysfs, "name"), []byte(label+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(sysfs, "capabilities", "key"), []byte(caps+"\n"), 0o644))
+
+	path := filepath.Join(dir, "dev", name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+	return path
+}
+
+// keyEvents returns key press and release events for the key codes, as
+// read from an event device.
+func keyEvents(t *testing.T, codes ...uint16) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	for _, code := range codes {
+		for _, value := range []int32{keyPress, 0} {
+			ev := inputEvent{Type: evKey, Code: code, Value: value}
+			require.NoError(t, binary.Write(&buf, binary.LittleEndian, ev))
+		}
+	}
+	return buf.Bytes()
+}
+
+// pressKeys appends key press and release events to an event file.
+func pressKeys(t *testing.T, path string, codes ...uint16) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.Write(keyEvents(t, codes...))
+	require.NoError(t, err)
+}
+
+func TestProbeDevice(t *testing.T) {
+	dir := t.TempDir()
+	keyboard := probeDevice(filepath.Join(dir, "sys"), fakeDevice(t, dir, "event0", "Fake Keyboard", keyboardCaps))
+	mouse := probeDevice(filepath.Join(dir, "sys"), fakeDevice(t, dir, "event1", "Fake Mouse", mouseCaps))
+	unknown := probeDevice(filepath.Join(dir, "sys"), filepath.Join(dir, "dev", "event9"))
+
+	assert.Equal(t, "Fake Keyboard", keyboard.Name)
+	assert.True(t, keyboard.IsKeyboard())
+	assert.True(t, mouse.HasKey(0x110)) // BTN_LEFT
+	assert.False(t, mouse.IsKeyboard())
+	assert.False(t, unknown.IsKeyboard())
+
+	opts := &Options{}
+	assert.True(t, opts.Match(keyboard))
+	assert.False(t, opts.Match(mouse))
+
+	opts = &Options{Include: []string{"mouse"}, Exclude: []string{"keyboard"}}
+	assert.False(t, opts.Match(keyboard))
+	assert.True(t, opts.Match(mouse))
+
+	opts = &Options{AllDevices: true, Exclude: []string{"MOUSE"}}
+	assert.True(t, opts.Match(unknown))
+	assert.False(t, opts.Match(mouse))
+}
+
+func TestKeyboardCounter_HotPlug(t *testing.T) {
+	dir := t.TempDir()
+	keyboard := fakeDevice(t, dir, "event0", "Fake Keyboard", keyboardCaps)
+	mouse := fakeDevice(t, dir, "event1", "Fake Mouse", mouseCaps)
+
+	var mu sync.Mutex
+	total := make(Counts)
+	devices := make(map[string]bool)
+
+	opts := &Options{
+		FlushInterval:  20 * time.Millisecond,
+		RescanInterval: 20 * time.Millisecond,
+		Devices:        filepath.Join(dir, "dev", "event*"),
+		Sysfs:          filepath.Join(dir, "sys"),
+		FlushFn: func(c Counts) {
+			mu.Lock()
+			defer mu.Unlock()
+			for category, n := range c {
+				total[category] += n
+			}
+		},
+		DeviceFn: func(d Device, added bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			devices[filepath.Base(d.Path)] = added
+		},
+	}
+	counted := func(category model.Category) int64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return total[category]
+	}
+	reading := func(name string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return devices[name]
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() {
+		done <- KeyboardCounter(ctx, opts)
+	}()
+
+	// KEY_A, KEY_BACKSPACE on the keyboard, BTN_LEFT on the mouse.
+	pressKeys(t, keyboard, 30, 14)
+	pressKeys(t, mouse, 0x110)
+
+	assert.Eventually(t, func() bool {
+		return counted(model.CategoryAlphanumeric) == 1 && counted(model.CategoryDelete) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, reading("event0"))
+	assert.False(t, reading("event1"))
+
+	// A keyboard plugged in later is picked up.
+	plugged := fakeDevice(t, dir, "event2", "Another Keyboard", keyboardCaps)
+	pressKeys(t, plugged, 59) // KEY_F1
+	assert.Eventually(t, func() bool {
+		return counted(model.CategoryFunction) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// An unplugged keyboard is dropped.
+	require.NoError(t, os.Remove(keyboard))
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		added, seen := devices["event0"]
+		return seen && !added
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("KeyboardCounter did not exit after context cancellation")
+	}
+	assert.Equal(t, int64(0), counted(model.CategoryOther))
+}
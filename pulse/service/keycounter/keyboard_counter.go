@@ -3,11 +3,16 @@ package keycounter
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/titpetric/platform-app/pulse/model"
 )
 
 // Linux input event constants
@@ -26,90 +31,228 @@ type inputEvent struct {
 	Value int32
 }
 
-// KeyboardCounter counts keypresses and flushes the total at a fixed interval.
-// It blocks until ctx is cancelled.
+// pollInterval is how long a reader waits at the end of a regular file
+// before reading again. Event devices block instead of returning EOF.
+const pollInterval = 100 * time.Millisecond
+
+// KeyboardCounter counts keypresses of input devices per category and
+// flushes the counts at a fixed interval. It blocks until ctx is
+// cancelled. See EvdevSource for device selection.
 func KeyboardCounter(ctx context.Context, opts *Options) error {
-	if opts == nil {
-		return fmt.Errorf("no options configured for counter")
-	}
-	eventFiles, err := filepath.Glob("/dev/input/event*")
-	if err != nil {
-		return fmt.Errorf("failed to list input devices: %w", err)
+	return Count(ctx, NewEvdevSource(opts), opts)
+}
+
+// EvdevSource reads key presses from Linux event devices. Devices are
+// rescanned periodically, so keyboards plugged in later are picked up and
+// unplugged ones are dropped. Reading event devices usually needs root.
+type EvdevSource struct {
+	opts *Options
+}
+
+// NewEvdevSource creates a source reading the devices selected by opts.
+func NewEvdevSource(opts *Options) *EvdevSource {
+	return &EvdevSource{
+		opts: opts,
 	}
-	if len(eventFiles) == 0 {
+}
+
+// Read reads key presses until ctx is cancelled.
+func (s *EvdevSource) Read(ctx context.Context, press func(model.Category)) error {
+	c := newCounter(s.opts, press)
+	defer c.close()
+
+	found, err := c.scan(ctx)
+	if found == 0 {
+		if err != nil {
+			return err
+		}
 		return fmt.Errorf("no input devices found")
 	}
+	if len(c.readers) == 0 && err != nil {
+		return fmt.Errorf("no readable input devices found: %w", err)
+	}
+
+	rescanTicker := time.NewTicker(s.opts.rescanInterval())
+	defer rescanTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-rescanTicker.C:
+			// Open errors are retried on the next scan.
+			_, _ = c.scan(ctx)
+
+		case path := <-c.gone:
+			c.remove(path)
+		}
+	}
+}
+
+// counter reads key events from a changing set of devices.
+type counter struct {
+	opts  *Options
+	press func(model.Category)
 
-	var count int32
-	done := make(chan struct{})
+	// readers and skipped are keyed by device path and only used
+	// from the EvdevSource.Read goroutine.
+	readers map[string]*deviceReader
+	skipped map[string]bool
+
+	gone chan string
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// deviceReader is an open device.
+type deviceReader struct {
+	device Device
+	file   *os.File
+	closed atomic.Bool
+}
+
+func newCounter(opts *Options, press func(model.Category)) *counter {
+	return &counter{
+		opts:    opts,
+		press:   press,
+		readers: make(map[string]*deviceReader),
+		skipped: make(map[string]bool),
+		gone:    make(chan string),
+		done:    make(chan struct{}),
+	}
+}
+
+// scan opens matching devices that aren't read yet, and drops devices
+// that disappeared. It returns the number of device files found.
+func (c *counter) scan(ctx context.Context) (int, error) {
+	paths, err := filepath.Glob(c.opts.devices())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list input devices: %w", err)
+	}
+
+	var errs []error
+	present := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		present[path] = true
+		if c.readers[path] != nil || c.skipped[path] {
+			continue
+		}
+
+		device := probeDevice(c.opts.sysfs(), path)
+		if !c.opts.Match(device) {
+			c.skipped[path] = true
+			continue
+		}
 
-	// Start readers for each event device
-	var opened int
-	for _, path := range eventFiles {
 		f, err := os.Open(path)
 		if err != nil {
+			errs = append(errs, err)
 			continue
 		}
-		opened++
 
-		go func(file *os.File) {
-			defer file.Close()
+		r := &deviceReader{
+			device: device,
+			file:   f,
+		}
+		c.readers[path] = r
+		c.wg.Add(1)
+		go c.read(ctx, r)
 
-			buf := make([]byte, eventSize)
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-				}
+		c.opts.device(device, true)
+	}
 
-				_, err := file.Read(buf)
-				if err != nil {
-					return
-				}
+	for path := range c.readers {
+		if !present[path] {
+			c.remove(path)
+		}
+	}
+	for path := range c.skipped {
+		if !present[path] {
+			delete(c.skipped, path)
+		}
+	}
 
-				var ev inputEvent
-				binary.Read(
-					bytesReader(buf),
-					binary.LittleEndian,
-					&ev,
-				)
+	return len(paths), errors.Join(errs...)
+}
 
-				if ev.Type == evKey && ev.Value == keyPress {
-					atomic.AddInt32(&count, 1)
-				}
-			}
-		}(f)
+// remove stops reading from a device.
+func (c *counter) remove(path string) {
+	r, ok := c.readers[path]
+	if !ok {
+		return
 	}
+	delete(c.readers, path)
 
-	if opened == 0 {
-		return fmt.Errorf("no readable input devices found")
-	}
+	r.closed.Store(true)
+	r.file.Close()
 
-	ticker := time.NewTicker(opts.FlushInterval)
-	defer ticker.Stop()
+	c.opts.device(r.device, false)
+}
 
-	flush := func() {
-		n := atomic.SwapInt32(&count, 0)
-		if n > 0 {
-			opts.Flush(n)
-		}
+// close stops all readers and waits for them to finish.
+func (c *counter) close() {
+	close(c.done)
+	for _, r := range c.readers {
+		r.closed.Store(true)
+		r.file.Close()
 	}
+	c.wg.Wait()
+}
 
-	defer flush()
+// read counts key presses from a device until it is removed, fails or
+// ctx is cancelled. A failed device is reported on the gone channel.
+func (c *counter) read(ctx context.Context, r *deviceReader) {
+	defer c.wg.Done()
 
+	buf := make([]byte, eventSize)
 	for {
-		select {
-		case <-ctx.Done():
-			close(done)
-			return ctx.Err()
+		if ctx.Err() != nil || r.closed.Load() {
+			return
+		}
+
+		_, err := io.ReadFull(r.file, buf)
+		if errors.Is(err, io.EOF) {
+			select {
+			case <-ctx.Done():
+			case <-c.done:
+			case <-time.After(pollInterval):
+			}
+			continue
+		}
+		if err != nil {
+			if !r.closed.Load() {
+				select {
+				case c.gone <- r.device.Path:
+				case <-c.done:
+				}
+			}
+			return
+		}
 
-		case <-ticker.C:
-			flush()
+		if ev := decodeEvent(buf); ev.isKeyPress() {
+			c.press(Classify(ev.Code))
 		}
 	}
 }
 
+// decodeEvent decodes an input event read from a device.
+func decodeEvent(buf []byte) inputEvent {
+	var ev inputEvent
+	binary.Read(
+		bytesReader(buf),
+		binary.LittleEndian,
+		&ev,
+	)
+	return ev
+}
+
+// isKeyPress reports whether the event is a key going down. Releases and
+// autorepeats are not counted.
+func (ev inputEvent) isKeyPress() bool {
+	return ev.Type == evKey && ev.Value == keyPress
+}
+
 // bytesReader avoids allocations when decoding input events
 func bytesReader(b []byte) *reader {
 	return &reader{b: b}
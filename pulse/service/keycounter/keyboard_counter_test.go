@@ -16,10 +16,10 @@ func TestKeyboardCounter_CancelsCleanly(t *testing.T) {
 
 	ctx, cancel := context.WithCancel(t.Context())
 
-	var flushCalls int32
-	flushFn := func(n int32) {
-		atomic.AddInt32(&flushCalls, n)
-		t.Logf("keys: %d", n)
+	var flushCalls int64
+	flushFn := func(c Counts) {
+		atomic.AddInt64(&flushCalls, c.Total())
+		t.Logf("keys: %d %v", c.Total(), c)
 	}
 
 	done := make(chan error, 1)
@@ -1,15 +1,50 @@
 package keycounter
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// Defaults for device discovery.
+const (
+	// DefaultDevices is the glob of event devices to read from.
+	DefaultDevices = "/dev/input/event*"
+	// DefaultSysfs is the sysfs directory describing event devices.
+	DefaultSysfs = "/sys/class/input"
+	// DefaultRescanInterval is how often new devices are looked for.
+	DefaultRescanInterval = 10 * time.Second
+)
 
 // Options holds configuration options for the keyboard counter.
 type Options struct {
-	FlushFn       func(int32)
+	FlushFn       func(Counts)
 	FlushInterval time.Duration
+
+	// The remaining options select devices for EvdevSource.
+
+	// Devices is a glob of event device files, DefaultDevices if empty.
+	Devices string
+	// Sysfs is the sysfs input class directory, DefaultSysfs if empty.
+	Sysfs string
+	// RescanInterval is how often Devices is globbed to pick up
+	// plugged in devices, DefaultRescanInterval if zero.
+	RescanInterval time.Duration
+
+	// Include selects devices by name, in addition to keyboards. A device
+	// is included when its name contains any of the values.
+	Include []string
+	// Exclude skips devices whose name contains any of the values. It
+	// takes precedence over Include.
+	Exclude []string
+	// AllDevices reads from every device, not only keyboards.
+	AllDevices bool
+
+	// DeviceFn is called when a device is added or removed.
+	DeviceFn func(d Device, added bool)
 }
 
 // NewOptions will create a new *Options.
-func NewOptions(flushFn func(int32), flushInterval time.Duration) *Options {
+func NewOptions(flushFn func(Counts), flushInterval time.Duration) *Options {
 	return &Options{
 		FlushFn:       flushFn,
 		FlushInterval: flushInterval,
@@ -17,8 +52,54 @@ func NewOptions(flushFn func(int32), flushInterval time.Duration) *Options {
 }
 
 // Flush to options function.
-func (o *Options) Flush(c int32) {
+func (o *Options) Flush(c Counts) {
 	if o.FlushFn != nil {
 		o.FlushFn(c)
 	}
 }
+
+// Match reports whether keystrokes should be counted from the device.
+// Names are matched case insensitively.
+func (o *Options) Match(d Device) bool {
+	if containsAny(d.Name, o.Exclude) {
+		return false
+	}
+	return o.AllDevices || d.IsKeyboard() || containsAny(d.Name, o.Include)
+}
+
+func (o *Options) device(d Device, added bool) {
+	if o.DeviceFn != nil {
+		o.DeviceFn(d, added)
+	}
+}
+
+func (o *Options) devices() string {
+	if o.Devices != "" {
+		return o.Devices
+	}
+	return DefaultDevices
+}
+
+func (o *Options) sysfs() string {
+	if o.Sysfs != "" {
+		return o.Sysfs
+	}
+	return DefaultSysfs
+}
+
+func (o *Options) rescanInterval() time.Duration {
+	if o.RescanInterval > 0 {
+		return o.RescanInterval
+	}
+	return DefaultRescanInterval
+}
+
+func containsAny(name string, values []string) bool {
+	name = strings.ToLower(name)
+	for _, value := range values {
+		if value != "" && strings.Contains(name, strings.ToLower(value)) {
+			return true
+		}
+	}
+	return false
+}
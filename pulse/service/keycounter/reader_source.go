@@ -0,0 +1,83 @@
+package keycounter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"unicode"
+
+	"github.com/titpetric/platform-app/pulse/model"
+)
+
+// ReaderSource counts the characters or lines read from a reader, such as
+// stdin. It needs no access to input devices.
+type ReaderSource struct {
+	reader io.Reader
+	lines  bool
+}
+
+// NewReaderSource creates a source reading from r. Every character counts
+// as a key press, or with lines set, every line counts as one press of
+// enter.
+func NewReaderSource(r io.Reader, lines bool) *ReaderSource {
+	return &ReaderSource{
+		reader: r,
+		lines:  lines,
+	}
+}
+
+// classifyRune returns the category of the key typing a character.
+func classifyRune(r rune) model.Category {
+	switch {
+	case unicode.IsLetter(r), unicode.IsDigit(r):
+		return model.CategoryAlphanumeric
+	case r == '\b', r == 0x7f:
+		return model.CategoryDelete
+	case r == 0x1b:
+		return model.CategoryFunction
+	}
+	return model.CategoryOther
+}
+
+// Read counts key presses until the reader is exhausted or ctx is
+// cancelled.
+func (s *ReaderSource) Read(ctx context.Context, press func(model.Category)) error {
+	// Reads block, so they are done in the background. A read pending
+	// when ctx is cancelled is abandoned.
+	presses := make(chan model.Category)
+	failed := make(chan error, 1)
+	go func() {
+		r := bufio.NewReader(s.reader)
+		for {
+			c, _, err := r.ReadRune()
+			if err != nil {
+				failed <- err
+				return
+			}
+			if s.lines && c != '\n' {
+				continue
+			}
+
+			select {
+			case presses <- classifyRune(c):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case category := <-presses:
+			press(category)
+		case err := <-failed:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
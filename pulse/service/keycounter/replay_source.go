@@ -0,0 +1,75 @@
+package keycounter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/titpetric/platform-app/pulse/model"
+)
+
+// ReplaySource counts the key presses of a recorded event device dump, as
+// written by `cat /dev/input/event3 > keys.dump`. Replays are
+// deterministic, so they can drive the recorder in tests.
+type ReplaySource struct {
+	reader   io.Reader
+	realtime bool
+}
+
+// NewReplaySource creates a source replaying the input events read from
+// r. Events are replayed as fast as possible, or with realtime set, with
+// the delays between them as recorded.
+func NewReplaySource(r io.Reader, realtime bool) *ReplaySource {
+	return &ReplaySource{
+		reader:   r,
+		realtime: realtime,
+	}
+}
+
+// eventTime returns the time an event was recorded.
+func (ev inputEvent) eventTime() time.Time {
+	return time.Unix(ev.Sec, ev.Usec*int64(time.Microsecond))
+}
+
+// Read replays the dump until its end or until ctx is cancelled.
+func (s *ReplaySource) Read(ctx context.Context, press func(model.Category)) error {
+	var last time.Time
+	buf := make([]byte, eventSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		_, err := io.ReadFull(s.reader, buf)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("replay: truncated input event")
+		}
+		if err != nil {
+			return fmt.Errorf("replay: %w", err)
+		}
+
+		ev := decodeEvent(buf)
+		if s.realtime {
+			stamp := ev.eventTime()
+			if !last.IsZero() && stamp.After(last) {
+				timer := time.NewTimer(stamp.Sub(last))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+			last = stamp
+		}
+
+		if ev.isKeyPress() {
+			press(Classify(ev.Code))
+		}
+	}
+}
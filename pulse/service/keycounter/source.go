@@ -0,0 +1,65 @@
+package keycounter
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/titpetric/platform-app/pulse/model"
+)
+
+// Source produces key presses for the counter.
+type Source interface {
+	// Read calls press for every key press, until ctx is cancelled or the
+	// source is exhausted. A source that runs out of input returns nil.
+	// press may be called concurrently, but not after Read returns.
+	Read(ctx context.Context, press func(model.Category)) error
+}
+
+// Count counts the key presses of a source per category and flushes the
+// counts every opts.FlushInterval, and once more when the source stops.
+// It blocks until the source stops and returns its error.
+func Count(ctx context.Context, source Source, opts *Options) error {
+	if opts == nil {
+		return fmt.Errorf("no options configured for counter")
+	}
+
+	counters := make(map[model.Category]*atomic.Int64)
+	for _, category := range model.Categories() {
+		counters[category] = new(atomic.Int64)
+	}
+	press := func(category model.Category) {
+		counters[category].Add(1)
+	}
+	flush := func() {
+		counts := make(Counts)
+		for category, counter := range counters {
+			if n := counter.Swap(0); n > 0 {
+				counts[category] = n
+			}
+		}
+		if len(counts) > 0 {
+			opts.Flush(counts)
+		}
+	}
+	defer flush()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- source.Read(ctx, press)
+	}()
+
+	flushTicker := time.NewTicker(opts.FlushInterval)
+	defer flushTicker.Stop()
+
+	for {
+		select {
+		case err := <-done:
+			return err
+
+		case <-flushTicker.C:
+			flush()
+		}
+	}
+}
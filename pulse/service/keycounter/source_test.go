@@ -0,0 +1,118 @@
+package keycounter
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/pulse/model"
+)
+
+// countSource counts a source to its end and returns the flushed counts.
+func countSource(t *testing.T, source Source) (Counts, error) {
+	t.Helper()
+
+	var mu sync.Mutex
+	total := make(Counts)
+	opts := NewOptions(func(c Counts) {
+		mu.Lock()
+		defer mu.Unlock()
+		for category, n := range c {
+			total[category] += n
+		}
+	}, time.Hour)
+
+	err := Count(t.Context(), source, opts)
+
+	mu.Lock()
+	defer mu.Unlock()
+	return total, err
+}
+
+func TestReplaySource(t *testing.T) {
+	// KEY_H, KEY_I, KEY_SPACE, KEY_BACKSPACE, KEY_LEFTSHIFT, KEY_UP
+	dump := keyEvents(t, 35, 23, 57, 14, 42, 103)
+
+	// Autorepeats and other event types are not counted.
+	var buf bytes.Buffer
+	buf.Write(dump)
+	for _, ev := range []inputEvent{
+		{Type: evKey, Code: 30, Value: 2},
+		{Type: 0x02, Code: 0, Value: 1},
+	} {
+		require.NoError(t, binary.Write(&buf, binary.LittleEndian, ev))
+	}
+
+	counts, err := countSource(t, NewReplaySource(&buf, false))
+	require.NoError(t, err)
+	assert.Equal(t, Counts{
+		model.CategoryAlphanumeric: 2,
+		model.CategoryOther:        1,
+		model.CategoryDelete:       1,
+		model.CategoryModifier:     1,
+		model.CategoryNavigation:   1,
+	}, counts)
+
+	// A truncated dump is an error, the counted presses are still flushed.
+	truncated := keyEvents(t, 30, 30)
+	counts, err = countSource(t, NewReplaySource(bytes.NewReader(truncated[:len(truncated)-eventSize-1]), false))
+	assert.ErrorContains(t, err, "truncated")
+	assert.Equal(t, Counts{model.CategoryAlphanumeric: 1}, counts)
+}
+
+func TestReplaySource_Realtime(t *testing.T) {
+	var buf bytes.Buffer
+	for i, code := range []uint16{30, 48} {
+		ev := inputEvent{Usec: int64(i) * 50000, Type: evKey, Code: code, Value: keyPress}
+		require.NoError(t, binary.Write(&buf, binary.LittleEndian, ev))
+	}
+
+	start := time.Now()
+	counts, err := countSource(t, NewReplaySource(&buf, true))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, Counts{model.CategoryAlphanumeric: 2}, counts)
+}
+
+func TestReaderSource(t *testing.T) {
+	input := "Hi there,\nzdravo 42\x7f\n"
+
+	counts, err := countSource(t, NewReaderSource(strings.NewReader(input), false))
+	require.NoError(t, err)
+	assert.Equal(t, Counts{
+		model.CategoryAlphanumeric: 15,
+		model.CategoryOther:        5,
+		model.CategoryDelete:       1,
+	}, counts)
+
+	counts, err = countSource(t, NewReaderSource(strings.NewReader(input), true))
+	require.NoError(t, err)
+	assert.Equal(t, Counts{model.CategoryOther: 2}, counts)
+}
+
+func TestReaderSource_Cancel(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err := Count(ctx, NewReaderSource(r, false), NewOptions(nil, time.Hour))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSyntheticSource(t *testing.T) {
+	counts, err := countSource(t, NewSyntheticSource(60000, 32))
+	require.NoError(t, err)
+	assert.Equal(t, int64(32), counts.Total())
+	assert.Equal(t, int64(22), counts[model.CategoryAlphanumeric])
+	assert.Equal(t, int64(4), counts[model.CategoryOther])
+}
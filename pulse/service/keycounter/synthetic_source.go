@@ -0,0 +1,62 @@
+package keycounter
+
+import (
+	"context"
+	"time"
+
+	"github.com/titpetric/platform-app/pulse/model"
+)
+
+// syntheticPattern is the category mix of generated key presses, roughly
+// that of typing prose.
+var syntheticPattern = []model.Category{
+	model.CategoryAlphanumeric,
+	model.CategoryAlphanumeric,
+	model.CategoryAlphanumeric,
+	model.CategoryAlphanumeric,
+	model.CategoryOther,
+	model.CategoryAlphanumeric,
+	model.CategoryAlphanumeric,
+	model.CategoryModifier,
+	model.CategoryAlphanumeric,
+	model.CategoryAlphanumeric,
+	model.CategoryAlphanumeric,
+	model.CategoryOther,
+	model.CategoryAlphanumeric,
+	model.CategoryDelete,
+	model.CategoryAlphanumeric,
+	model.CategoryNavigation,
+}
+
+// SyntheticSource generates key presses at a steady rate. It is useful to
+// try out the recorder and server without a keyboard.
+type SyntheticSource struct {
+	rate  int
+	limit int
+}
+
+// NewSyntheticSource creates a source generating rate key presses per
+// minute. With a limit above zero, it stops after limit key presses.
+func NewSyntheticSource(rate, limit int) *SyntheticSource {
+	return &SyntheticSource{
+		rate:  max(rate, 1),
+		limit: limit,
+	}
+}
+
+// Read generates key presses until the limit is reached or ctx is
+// cancelled.
+func (s *SyntheticSource) Read(ctx context.Context, press func(model.Category)) error {
+	ticker := time.NewTicker(time.Minute / time.Duration(s.rate))
+	defer ticker.Stop()
+
+	for i := 0; s.limit <= 0 || i < s.limit; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			press(syntheticPattern[i%len(syntheticPattern)])
+		}
+	}
+	return nil
+}
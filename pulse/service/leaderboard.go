@@ -0,0 +1,374 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/titpetric/platform"
+	"github.com/titpetric/vuego"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+	"github.com/titpetric/platform-app/user"
+	usermodel "github.com/titpetric/platform-app/user/model"
+)
+
+// periodLabels are the leaderboard period names shown on pages.
+var periodLabels = map[storage.Period]string{
+	storage.PeriodToday: "Today",
+	storage.PeriodWeek:  "This week",
+	storage.PeriodMonth: "This month",
+	storage.PeriodAll:   "All time",
+}
+
+// Ranking is a leaderboard entry with the user it ranks.
+type Ranking struct {
+	Username string `json:"username"`
+	storage.Standing
+}
+
+// Leaderboard holds ranked users for a period.
+type Leaderboard struct {
+	Period   storage.Period `json:"period"`
+	Rankings []Ranking      `json:"rankings"`
+}
+
+// periodTab links to a leaderboard period.
+type periodTab struct {
+	Label  string `json:"label"`
+	Href   string `json:"href"`
+	Active bool   `json:"active"`
+}
+
+// rankingRow is a leaderboard entry formatted for pages.
+type rankingRow struct {
+	Rank     int    `json:"rank"`
+	Username string `json:"username"`
+	Href     string `json:"href"`
+	Count    int64  `json:"count"`
+	Change   string `json:"change"`
+}
+
+// parsePeriod returns the period from the `period` query parameter,
+// defaulting to the current week.
+func parsePeriod(r *http.Request) (storage.Period, error) {
+	period := storage.Period(r.URL.Query().Get("period"))
+	if period == "" {
+		return storage.PeriodWeek, nil
+	}
+	if !period.Valid() {
+		return "", &RequestError{StatusCode: http.StatusBadRequest, Err: fmt.Errorf("invalid period: %q", period)}
+	}
+	return period, nil
+}
+
+// periodTabs returns links to each period on the page at path.
+func periodTabs(path string, active storage.Period) []periodTab {
+	tabs := make([]periodTab, 0, len(periodLabels))
+	for _, period := range storage.Periods() {
+		tabs = append(tabs, periodTab{
+			Label:  periodLabels[period],
+			Href:   path + "?" + url.Values{"period": {string(period)}}.Encode(),
+			Active: period == active,
+		})
+	}
+	return tabs
+}
+
+// formatChange describes a rank change for the leaderboard pages.
+func formatChange(s storage.Standing) string {
+	switch {
+	case s.Count == 0:
+		return ""
+	case s.PreviousRank == 0:
+		return "new"
+	case s.Change > 0:
+		return fmt.Sprintf("▲ %d", s.Change)
+	case s.Change < 0:
+		return fmt.Sprintf("▼ %d", -s.Change)
+	}
+	return "–"
+}
+
+// viewerLocation returns the timezone periods are taken in: the session
+// user's timezone, or UTC. The `tz` query parameter overrides it.
+func viewerLocation(r *http.Request) *time.Location {
+	if viewer, ok := user.GetSessionUser(r.Context()); ok {
+		return location(r, viewer)
+	}
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// leaderboard ranks users for the period. Users with a private profile
+// are left out, unless they are the session user. With unlisted set,
+// unlisted profiles are ranked too. With idle set, users without
+// keystrokes in the period are kept at the bottom.
+func (h *Handlers) leaderboard(r *http.Request, users []usermodel.User, period storage.Period, unlisted, idle bool) (*Leaderboard, error) {
+	ctx := r.Context()
+
+	visibility, err := h.storage.ListVisibility(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list visibility: %w", err)
+	}
+
+	userIDs := make([]string, 0, len(users))
+	usernames := make(map[string]string, len(users))
+	for _, u := range users {
+		switch visibility[u.ID] {
+		case storage.VisibilityPrivate:
+			if !isOwner(r, &u) {
+				continue
+			}
+		case storage.VisibilityUnlisted:
+			if !unlisted && !isOwner(r, &u) {
+				continue
+			}
+		}
+		userIDs = append(userIDs, u.ID)
+		usernames[u.ID] = u.Username
+	}
+
+	standings, err := h.storage.Leaderboard(ctx, userIDs, period, time.Now().In(viewerLocation(r)))
+	if err != nil {
+		return nil, fmt.Errorf("leaderboard: %w", err)
+	}
+
+	result := &Leaderboard{
+		Period:   period,
+		Rankings: make([]Ranking, 0, len(standings)),
+	}
+	for _, standing := range standings {
+		if standing.Count == 0 && !idle {
+			continue
+		}
+		result.Rankings = append(result.Rankings, Ranking{
+			Username: usernames[standing.UserID],
+			Standing: standing,
+		})
+	}
+	return result, nil
+}
+
+// rankingRows formats a leaderboard for pages.
+func rankingRows(board *Leaderboard) []rankingRow {
+	rows := make([]rankingRow, 0, len(board.Rankings))
+	for _, ranking := range board.Rankings {
+		rows = append(rows, rankingRow{
+			Rank:     ranking.Rank,
+			Username: ranking.Username,
+			Href:     "/pulse/" + ranking.Username,
+			Count:    ranking.Count,
+			Change:   formatChange(ranking.Standing),
+		})
+	}
+	return rows
+}
+
+// teamMembers returns the users in a team. Unknown teams are reported as
+// not found.
+func (h *Handlers) teamMembers(ctx context.Context, teamID string) (*usermodel.UserGroup, []usermodel.User, error) {
+	team, err := h.groupStorage.Get(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, &RequestError{StatusCode: http.StatusNotFound, Err: fmt.Errorf("team not found: %s", teamID)}
+		}
+		return nil, nil, err
+	}
+
+	memberIDs, err := h.groupStorage.ListMembers(ctx, teamID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users, err := h.userStorage.List(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list users: %w", err)
+	}
+
+	members := make([]usermodel.User, 0, len(memberIDs))
+	for _, u := range users {
+		if slices.Contains(memberIDs, u.ID) {
+			members = append(members, u)
+		}
+	}
+	return team, members, nil
+}
+
+// GetLeaderboard ranks all public users for a period.
+func (h *Handlers) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.getLeaderboard(w, r))
+}
+
+func (h *Handlers) getLeaderboard(w http.ResponseWriter, r *http.Request) error {
+	period, err := parsePeriod(r)
+	if err != nil {
+		return err
+	}
+
+	users, err := h.userStorage.List(r.Context())
+	if err != nil {
+		return fmt.Errorf("list users: %w", err)
+	}
+
+	board, err := h.leaderboard(r, users, period, false, false)
+	if err != nil {
+		return err
+	}
+
+	platform.JSON(w, r, http.StatusOK, board)
+	return nil
+}
+
+// GetTeamLeaderboard ranks the members of a team for a period.
+func (h *Handlers) GetTeamLeaderboard(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.getTeamLeaderboard(w, r))
+}
+
+func (h *Handlers) getTeamLeaderboard(w http.ResponseWriter, r *http.Request) error {
+	period, err := parsePeriod(r)
+	if err != nil {
+		return err
+	}
+
+	_, members, err := h.teamMembers(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+
+	board, err := h.leaderboard(r, members, period, true, true)
+	if err != nil {
+		return err
+	}
+
+	platform.JSON(w, r, http.StatusOK, board)
+	return nil
+}
+
+// TeamPage serves the leaderboard of a team.
+func (h *Handlers) TeamPage(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.teamPage(w, r))
+}
+
+func (h *Handlers) teamPage(w http.ResponseWriter, r *http.Request) error {
+	type viewData struct {
+		Title    string       `json:"title"`
+		Team     string       `json:"team"`
+		Action   string       `json:"action"`
+		Periods  []periodTab  `json:"periods"`
+		Rankings []rankingRow `json:"rankings"`
+		LoggedIn bool         `json:"loggedIn"`
+		Member   bool         `json:"member"`
+	}
+
+	ctx := r.Context()
+	period, err := parsePeriod(r)
+	if err != nil {
+		return err
+	}
+
+	team, members, err := h.teamMembers(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+
+	board, err := h.leaderboard(r, members, period, true, true)
+	if err != nil {
+		return err
+	}
+
+	path := "/pulse/teams/" + team.ID
+	data := viewData{
+		Title:    "Pulse team " + team.Title,
+		Team:     team.Title,
+		Action:   path,
+		Periods:  periodTabs(path, period),
+		Rankings: rankingRows(board),
+	}
+	if viewer, ok := user.GetSessionUser(ctx); ok {
+		data.LoggedIn = true
+		data.Member = slices.ContainsFunc(members, func(u usermodel.User) bool {
+			return u.ID == viewer.ID
+		})
+	}
+
+	teamPage := vuego.View[viewData](h.vuego, "team.vuego", data)
+
+	return teamPage.Render(ctx, w)
+}
+
+// PostTeams creates a team, with the session user as its first member.
+func (h *Handlers) PostTeams(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.postTeams(w, r))
+}
+
+func (h *Handlers) postTeams(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+
+	team, err := h.groupStorage.Create(ctx, r.FormValue("title"))
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+	if err := h.groupStorage.AddMember(ctx, team.ID, sessionUser.ID); err != nil {
+		return err
+	}
+
+	http.Redirect(w, r, "/pulse/teams/"+team.ID, http.StatusSeeOther)
+	return nil
+}
+
+// PostTeam handles the join and leave forms on the team page.
+func (h *Handlers) PostTeam(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.postTeam(w, r))
+}
+
+func (h *Handlers) postTeam(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+
+	team, _, err := h.teamMembers(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+
+	switch r.FormValue("action") {
+	case "join":
+		err = h.groupStorage.AddMember(ctx, team.ID, sessionUser.ID)
+	case "leave":
+		err = h.groupStorage.RemoveMember(ctx, team.ID, sessionUser.ID)
+	default:
+		err = &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("unknown action")}
+	}
+	if err != nil {
+		return err
+	}
+
+	http.Redirect(w, r, "/pulse/teams/"+team.ID, http.StatusSeeOther)
+	return nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+func TestParsePeriod(t *testing.T) {
+	period, err := parsePeriod(httptest.NewRequest("GET", "/pulse", nil))
+	require.NoError(t, err)
+	assert.Equal(t, storage.PeriodWeek, period)
+
+	period, err = parsePeriod(httptest.NewRequest("GET", "/pulse?period=all", nil))
+	require.NoError(t, err)
+	assert.Equal(t, storage.PeriodAll, period)
+
+	_, err = parsePeriod(httptest.NewRequest("GET", "/pulse?period=year", nil))
+	assert.Error(t, err)
+}
+
+func TestFormatChange(t *testing.T) {
+	assert.Equal(t, "", formatChange(storage.Standing{Rank: 3}))
+	assert.Equal(t, "new", formatChange(storage.Standing{Count: 5, Rank: 1}))
+	assert.Equal(t, "▲ 2", formatChange(storage.Standing{Count: 5, Rank: 1, PreviousRank: 3, Change: 2}))
+	assert.Equal(t, "▼ 1", formatChange(storage.Standing{Count: 5, Rank: 2, PreviousRank: 1, Change: -1}))
+	assert.Equal(t, "–", formatChange(storage.Standing{Count: 5, Rank: 1, PreviousRank: 1}))
+}
+
+func TestPeriodTabs(t *testing.T) {
+	tabs := periodTabs("/pulse/teams/T1", storage.PeriodMonth)
+	require.Len(t, tabs, 4)
+	assert.Equal(t, "/pulse/teams/T1?period=month", tabs[2].Href)
+	assert.True(t, tabs[2].Active)
+	assert.False(t, tabs[0].Active)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+// LiveMinutes is the number of minutes shown on the live chart.
+const LiveMinutes = 60
+
+// TypingTimeout is how long after the last keystrokes a user is still
+// shown as typing.
+const TypingTimeout = 2 * time.Minute
+
+// liveKeepAlive is the interval of comments sent to keep idle live
+// streams open through proxies.
+const liveKeepAlive = 25 * time.Second
+
+// liveBar is a minute on the live chart.
+type liveBar struct {
+	Minute  int64  `json:"minute"`
+	Count   int64  `json:"count"`
+	Style   string `json:"style"`
+	Tooltip string `json:"tooltip"`
+}
+
+// liveView holds the initial state of the live card on the user page.
+type liveView struct {
+	Status string    `json:"status"`
+	Bars   []liveBar `json:"bars"`
+}
+
+// liveBars returns a bar for each of the last LiveMinutes minutes up to
+// now, oldest first. Bars are identified by their Unix minute, tooltips
+// show the time in loc.
+func liveBars(minutes []storage.MinuteCount, now time.Time, loc *time.Location) ([]liveBar, error) {
+	counts := make(map[int64]int64, len(minutes))
+	var maxCount int64
+	for _, m := range minutes {
+		stamp, err := storage.ParseStamp(m.Stamp)
+		if err != nil {
+			return nil, err
+		}
+		minute := stamp.Unix() / 60
+		counts[minute] += m.Count
+		maxCount = max(maxCount, counts[minute])
+	}
+
+	last := now.Unix() / 60
+	bars := make([]liveBar, LiveMinutes)
+	for i := range bars {
+		minute := last - LiveMinutes + 1 + int64(i)
+		count := counts[minute]
+		percent := int64(0)
+		if maxCount > 0 && count > 0 {
+			percent = max(count*100/maxCount, 2)
+		}
+		bars[i] = liveBar{
+			Minute:  minute,
+			Count:   count,
+			Style:   fmt.Sprintf("height: %dpx", percent*57/100+3),
+			Tooltip: fmt.Sprintf("%s — %d keystrokes", time.Unix(minute*60, 0).In(loc).Format("15:04"), count),
+		}
+	}
+	return bars, nil
+}
+
+// typingStatus describes what a user is typing on, given their last
+// active minute.
+func typingStatus(last *storage.DailyHostCount, now time.Time) (string, error) {
+	if last == nil {
+		return "Not typing right now", nil
+	}
+	stamp, err := storage.ParseStamp(last.Stamp)
+	if err != nil {
+		return "", err
+	}
+	// Minutes are truncated, the keystrokes may be up to a minute later.
+	if now.Sub(stamp) > TypingTimeout+time.Minute {
+		return "Not typing right now", nil
+	}
+	return "Currently typing on " + last.Hostname, nil
+}
+
+// liveState returns the initial state of the live card for a user.
+func (h *Handlers) liveState(r *http.Request, userID string, loc *time.Location, exclude []string) (*liveView, error) {
+	ctx := r.Context()
+	now := time.Now()
+
+	minutes, err := h.storage.GetUserMinutes(ctx, userID, storage.Range{
+		From:    now.Truncate(time.Minute).Add(-(LiveMinutes - 1) * time.Minute),
+		To:      now,
+		Exclude: exclude,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bars, err := liveBars(minutes, now, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := h.storage.GetLastActive(ctx, userID, exclude...)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := typingStatus(last, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &liveView{
+		Status: status,
+		Bars:   bars,
+	}, nil
+}
+
+// writeEvent writes an ingest event as a server-sent `pulse` event.
+func writeEvent(w io.Writer, event storage.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: pulse\ndata: %s\n\n", data)
+	return err
+}
+
+// Live streams the keystrokes of a user as server-sent events, as they
+// are ingested. Hidden hosts are left out for other users.
+func (h *Handlers) Live(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.live(w, r))
+}
+
+func (h *Handlers) live(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	username := r.PathValue("username")
+
+	user, err := h.userStorage.GetByUsername(ctx, username)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusNotFound, Err: fmt.Errorf("user not found: %s", username)}
+	}
+
+	acc, err := h.access(r, user)
+	if err != nil {
+		return err
+	}
+
+	events, unsubscribe := h.storage.Subscribe(user.ID)
+	defer unsubscribe()
+
+	// The stream outlives the server write timeout.
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.WriteString(w, "retry: 5000\n\n"); err != nil {
+		return nil
+	}
+	if err := rc.Flush(); err != nil {
+		return nil
+	}
+
+	keepAlive := time.NewTicker(liveKeepAlive)
+	defer keepAlive.Stop()
+
+	// Write errors mean the client went away, the stream just ends.
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		case event := <-events:
+			if slices.Contains(acc.Exclude, event.Hostname) {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return nil
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
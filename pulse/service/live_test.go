@@ -0,0 +1,57 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+func TestLiveBars(t *testing.T) {
+	now := time.Date(2026, 3, 12, 10, 30, 20, 0, time.UTC)
+	bars, err := liveBars([]storage.MinuteCount{
+		{Stamp: "2026-03-12 09:31:00", Count: 5},
+		{Stamp: "2026-03-12 10:30:00", Count: 50},
+		{Stamp: "2026-03-12 09:00:00", Count: 500},
+	}, now, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, bars, LiveMinutes)
+
+	assert.Equal(t, time.Date(2026, 3, 12, 9, 31, 0, 0, time.UTC).Unix()/60, bars[0].Minute)
+	assert.Equal(t, int64(5), bars[0].Count)
+	assert.Equal(t, "09:31 — 5 keystrokes", bars[0].Tooltip)
+	assert.Equal(t, "height: 3px", bars[1].Style)
+	assert.Equal(t, int64(50), bars[LiveMinutes-1].Count)
+}
+
+func TestTypingStatus(t *testing.T) {
+	now := time.Date(2026, 3, 12, 10, 30, 20, 0, time.UTC)
+
+	status, err := typingStatus(nil, now)
+	require.NoError(t, err)
+	assert.Equal(t, "Not typing right now", status)
+
+	status, err = typingStatus(&storage.DailyHostCount{Hostname: "laptop", Stamp: "2026-03-12 10:28:00"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "Currently typing on laptop", status)
+
+	status, err = typingStatus(&storage.DailyHostCount{Hostname: "laptop", Stamp: "2026-03-12 10:20:00"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "Not typing right now", status)
+}
+
+func TestWriteEvent(t *testing.T) {
+	var buf bytes.Buffer
+	event := storage.Event{
+		UserID:   "TESTUSER",
+		Hostname: "laptop",
+		Stamp:    time.Date(2026, 3, 12, 10, 30, 0, 0, time.UTC),
+		Count:    42,
+	}
+	require.NoError(t, writeEvent(&buf, event))
+	assert.Equal(t, "event: pulse\ndata: {\"hostname\":\"laptop\",\"stamp\":\"2026-03-12T10:30:00Z\",\"count\":42}\n\n", buf.String())
+}
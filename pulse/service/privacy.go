@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+	"github.com/titpetric/platform-app/user"
+	usermodel "github.com/titpetric/platform-app/user/model"
+)
+
+// access describes what the current request may see of a user's pulse data.
+type access struct {
+	// Owner is true when the session user is the profile owner.
+	Owner bool
+	// Visibility is the profile visibility.
+	Visibility storage.Visibility
+	// Exclude lists hidden hosts to leave out for other users.
+	Exclude []string
+}
+
+// isOwner reports whether the session user is u.
+func isOwner(r *http.Request, u *usermodel.User) bool {
+	viewer, ok := user.GetSessionUser(r.Context())
+	return ok && viewer.ID == u.ID
+}
+
+// access resolves the privacy settings of u for the current request.
+// Private profiles of other users are reported as not found.
+func (h *Handlers) access(r *http.Request, u *usermodel.User) (*access, error) {
+	ctx := r.Context()
+
+	visibility, err := h.storage.GetVisibility(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &access{
+		Owner:      isOwner(r, u),
+		Visibility: visibility,
+	}
+	if result.Owner {
+		return result, nil
+	}
+
+	if visibility == storage.VisibilityPrivate {
+		return nil, &RequestError{StatusCode: http.StatusNotFound, Err: fmt.Errorf("user not found: %s", u.Username)}
+	}
+
+	result.Exclude, err = h.storage.GetHiddenHosts(ctx, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// visibleHosts filters hosts with the exclusion list.
+func (a *access) visibleHosts(hosts []string) []string {
+	if len(a.Exclude) == 0 {
+		return hosts
+	}
+
+	hidden := make(map[string]bool, len(a.Exclude))
+	for _, host := range a.Exclude {
+		hidden[host] = true
+	}
+
+	result := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if !hidden[host] {
+			result = append(result, host)
+		}
+	}
+	return result
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+// RetentionOptions configures the retention job.
+type RetentionOptions struct {
+	// Interval is how often the job runs (default: 1 hour)
+	Interval time.Duration
+	// Policy sets how many days of each table are kept
+	Policy storage.RetentionPolicy
+	// Logger for structured logging
+	Logger *slog.Logger
+}
+
+// DefaultRetentionOptions returns the default retention, which keeps all
+// rows forever. Deleting old rows is opt-in, see RetentionOptionsFromEnv.
+func DefaultRetentionOptions() RetentionOptions {
+	return RetentionOptions{
+		Interval: time.Hour,
+		Logger:   slog.New(slog.NewTextHandler(os.Stderr, nil)),
+	}
+}
+
+// RetentionOptionsFromEnv returns the default retention, overridden by
+// PULSE_RETENTION_* environment variables.
+func RetentionOptionsFromEnv() RetentionOptions {
+	options := DefaultRetentionOptions()
+	if value, ok := os.LookupEnv("PULSE_RETENTION_INTERVAL"); ok {
+		if interval, err := time.ParseDuration(value); err == nil && interval > 0 {
+			options.Interval = interval
+		}
+	}
+
+	policy := &options.Policy
+	policy.MinutelyDays = getEnvInt("PULSE_RETENTION_MINUTELY_DAYS", policy.MinutelyDays)
+	policy.HourlyDays = getEnvInt("PULSE_RETENTION_HOURLY_DAYS", policy.HourlyDays)
+	policy.DailyDays = getEnvInt("PULSE_RETENTION_DAILY_DAYS", policy.DailyDays)
+	policy.WeeklyDays = getEnvInt("PULSE_RETENTION_WEEKLY_DAYS", policy.WeeklyDays)
+	policy.MonthlyDays = getEnvInt("PULSE_RETENTION_MONTHLY_DAYS", policy.MonthlyDays)
+	return options
+}
+
+// getEnvInt gets an integer environment variable with a default value
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if intVal, err := strconv.Atoi(value); err == nil {
+			return intVal
+		}
+	}
+	return defaultValue
+}
+
+// Retention periodically rolls up and prunes pulse data.
+type Retention struct {
+	storage *storage.Storage
+	options RetentionOptions
+	logger  *slog.Logger
+
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewRetention creates a retention job.
+func NewRetention(s *storage.Storage, options RetentionOptions) *Retention {
+	if options.Interval <= 0 {
+		options.Interval = time.Hour
+	}
+	if options.Logger == nil {
+		options.Logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Retention{
+		storage: s,
+		options: options,
+		logger:  options.Logger,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start runs the job now, and then at every interval.
+func (r *Retention) Start() {
+	if policy := r.options.Policy; policy != (storage.RetentionPolicy{}) {
+		r.logger.Warn("pulse retention deletes old rows",
+			"minutely_days", policy.MinutelyDays,
+			"hourly_days", policy.HourlyDays,
+			"daily_days", policy.DailyDays,
+			"weekly_days", policy.WeeklyDays,
+			"monthly_days", policy.MonthlyDays)
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.options.Interval)
+		defer ticker.Stop()
+
+		for {
+			r.Run(r.ctx)
+
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the job and waits for a running pass to finish.
+func (r *Retention) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// Run rolls up and prunes pulse data once, and logs what was pruned.
+func (r *Retention) Run(ctx context.Context) (*storage.RetentionReport, error) {
+	report, err := r.storage.Prune(ctx, r.options.Policy, time.Now())
+	if err != nil {
+		r.logger.Error("pulse retention failed", "error", err)
+		return nil, err
+	}
+
+	r.logger.Info("pulse retention done",
+		"backfilled", report.Backfilled,
+		"rolled_up", report.RolledUp,
+		"pruned", report.Pruned(),
+		"minutely", report.Minutely,
+		"hourly", report.Hourly,
+		"daily", report.Daily,
+		"categories", report.Categories,
+		"weekly", report.Weekly,
+		"monthly", report.Monthly)
+	return report, nil
+}
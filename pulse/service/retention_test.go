@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+func TestRetentionOptionsFromEnv(t *testing.T) {
+	t.Setenv("PULSE_RETENTION_INTERVAL", "15m")
+	t.Setenv("PULSE_RETENTION_HOURLY_DAYS", "14")
+	t.Setenv("PULSE_RETENTION_MONTHLY_DAYS", "3650")
+	t.Setenv("PULSE_RETENTION_DAILY_DAYS", "many")
+
+	options := RetentionOptionsFromEnv()
+	assert.Equal(t, 15*time.Minute, options.Interval)
+	assert.Equal(t, 0, options.Policy.MinutelyDays)
+	assert.Equal(t, 14, options.Policy.HourlyDays)
+	assert.Equal(t, 0, options.Policy.DailyDays)
+	assert.Equal(t, 0, options.Policy.WeeklyDays)
+	assert.Equal(t, 3650, options.Policy.MonthlyDays)
+}
+
+func TestDefaultRetentionOptions(t *testing.T) {
+	// Nothing is deleted unless configured.
+	assert.Equal(t, storage.RetentionPolicy{}, DefaultRetentionOptions().Policy)
+}
@@ -3,6 +3,8 @@ package service
 
 import (
 	"context"
+	"log/slog"
+	"os"
 
 	"github.com/titpetric/platform"
 
@@ -15,15 +17,23 @@ import (
 type PulseModule struct {
 	platform.UnimplementedModule
 
-	storage     *storage.Storage
-	userStorage *userstorage.UserStorage
+	storage      *storage.Storage
+	userStorage  *userstorage.UserStorage
+	groupStorage *userstorage.GroupStorage
 
-	handlers *Handlers
+	mailer Mailer
+
+	handlers  *Handlers
+	retention *Retention
+	goals     *Goals
 }
 
-// NewPulseModule creates a new pulse module.
-func NewPulseModule() *PulseModule {
-	return &PulseModule{}
+// NewPulseModule creates a new pulse module. Goal alerts are emailed with
+// mailer, a nil mailer disables them.
+func NewPulseModule(mailer Mailer) *PulseModule {
+	return &PulseModule{
+		mailer: mailer,
+	}
 }
 
 // Name returns the module name.
@@ -37,7 +47,27 @@ func (p *PulseModule) Start(ctx context.Context) error {
 		return err
 	}
 
-	p.handlers = NewHandlers(p.storage, p.userStorage, FS(ctx))
+	p.handlers = NewHandlers(p.storage, p.userStorage, p.groupStorage, FS(ctx))
+	checkReservedUsernames(ctx, p.userStorage, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	p.retention = NewRetention(p.storage, RetentionOptionsFromEnv())
+	p.retention.Start()
+
+	if p.mailer != nil {
+		p.goals = NewGoals(p.storage, p.userStorage, p.mailer, GoalOptionsFromEnv())
+		p.goals.Start()
+	}
+	return nil
+}
+
+// Stop stops the retention and goal alert jobs.
+func (p *PulseModule) Stop(context.Context) error {
+	if p.retention != nil {
+		p.retention.Stop()
+	}
+	if p.goals != nil {
+		p.goals.Stop()
+	}
 	return nil
 }
 
@@ -64,6 +94,7 @@ func (p *PulseModule) setupUserStorage(ctx context.Context) error {
 	}
 
 	p.userStorage = userstorage.NewUserStorage(db)
+	p.groupStorage = userstorage.NewGroupStorage(db)
 	return nil
 }
 
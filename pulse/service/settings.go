@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/titpetric/platform"
+	"github.com/titpetric/vuego"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+	"github.com/titpetric/platform-app/user"
+)
+
+// Settings holds the pulse privacy settings of a user.
+type Settings struct {
+	Visibility storage.Visibility `json:"visibility"`
+	Hosts      []SettingsHost     `json:"hosts"`
+}
+
+// SettingsHost holds the visibility of a single host.
+type SettingsHost struct {
+	Hostname string `json:"hostname"`
+	Hidden   bool   `json:"hidden"`
+}
+
+func (h *Handlers) settings(ctx context.Context, userID string) (*Settings, error) {
+	visibility, err := h.storage.GetVisibility(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts, err := h.storage.ListHosts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Settings{
+		Visibility: visibility,
+		Hosts:      make([]SettingsHost, 0, len(hosts)),
+	}
+	for _, host := range hosts {
+		result.Hosts = append(result.Hosts, SettingsHost{
+			Hostname: host.Hostname,
+			Hidden:   host.Hidden == 1,
+		})
+	}
+	return result, nil
+}
+
+func (h *Handlers) saveSettings(ctx context.Context, userID string, visibility storage.Visibility, hidden []string) error {
+	if !visibility.Valid() {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("visibility must be public, unlisted or private")}
+	}
+	if err := h.storage.SetVisibility(ctx, userID, visibility); err != nil {
+		return err
+	}
+	return h.storage.SetHiddenHosts(ctx, userID, hidden)
+}
+
+// SettingsPage serves the pulse privacy settings page.
+func (h *Handlers) SettingsPage(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.settingsPage(w, r))
+}
+
+func (h *Handlers) settingsPage(w http.ResponseWriter, r *http.Request) error {
+	type viewData struct {
+		Title    string    `json:"title"`
+		Username string    `json:"username"`
+		Settings *Settings `json:"settings"`
+		Saved    bool      `json:"saved"`
+	}
+
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return nil
+	}
+
+	settings, err := h.settings(ctx, sessionUser.ID)
+	if err != nil {
+		return err
+	}
+
+	data := viewData{
+		Title:    "Pulse settings",
+		Username: sessionUser.Username,
+		Settings: settings,
+		Saved:    r.URL.Query().Has("saved"),
+	}
+
+	settingsPage := vuego.View[viewData](h.vuego, "settings.vuego", data)
+
+	return settingsPage.Render(ctx, w)
+}
+
+// PostSettings saves the pulse privacy settings form.
+func (h *Handlers) PostSettings(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.postSettings(w, r))
+}
+
+func (h *Handlers) postSettings(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusFound)
+		return nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+
+	visibility := storage.Visibility(r.FormValue("visibility"))
+	if err := h.saveSettings(ctx, sessionUser.ID, visibility, r.Form["hidden"]); err != nil {
+		return err
+	}
+
+	http.Redirect(w, r, "/pulse/settings?saved", http.StatusSeeOther)
+	return nil
+}
+
+// GetSettings returns the pulse privacy settings of the authenticated user.
+func (h *Handlers) GetSettings(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.getSettings(w, r))
+}
+
+func (h *Handlers) getSettings(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	settings, err := h.settings(ctx, sessionUser.ID)
+	if err != nil {
+		return err
+	}
+
+	platform.JSON(w, r, http.StatusOK, settings)
+	return nil
+}
+
+// PutSettings replaces the pulse privacy settings of the authenticated user.
+func (h *Handlers) PutSettings(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.putSettings(w, r))
+}
+
+func (h *Handlers) putSettings(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	body := Settings{}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+
+	var hidden []string
+	for _, host := range body.Hosts {
+		if host.Hidden && !slices.Contains(hidden, host.Hostname) {
+			hidden = append(hidden, host.Hostname)
+		}
+	}
+
+	if err := h.saveSettings(ctx, sessionUser.ID, body.Visibility, hidden); err != nil {
+		return err
+	}
+
+	settings, err := h.settings(ctx, sessionUser.ID)
+	if err != nil {
+		return err
+	}
+
+	platform.JSON(w, r, http.StatusOK, settings)
+	return nil
+}
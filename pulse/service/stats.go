@@ -0,0 +1,123 @@
+package service
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+	"github.com/titpetric/platform-app/user"
+)
+
+// StatsDays is the number of days covered by stats.
+const StatsDays = 30
+
+// HostStats holds keystroke totals of a host.
+type HostStats struct {
+	Hostname string `json:"hostname"`
+	Today    int64  `json:"today"`
+	Week     int64  `json:"week"`
+	Total    int64  `json:"total"`
+	// Daily lists the keystrokes of the last StatsDays days, oldest
+	// first.
+	Daily []int64 `json:"daily"`
+}
+
+// Stats holds a user's keystroke totals for today, this week and the
+// last StatsDays days, overall and per host.
+type Stats struct {
+	// From is the first day of Daily, as YYYY-MM-DD.
+	From     string      `json:"from"`
+	Timezone string      `json:"timezone"`
+	Today    int64       `json:"today"`
+	Week     int64       `json:"week"`
+	Total    int64       `json:"total"`
+	Hosts    []HostStats `json:"hosts"`
+}
+
+// summarizeStats totals daily rows into stats for the StatsDays days up to
+// now. Weeks start on Monday. Hosts are ordered by total, highest first.
+func summarizeStats(daily []storage.DailyHostCount, now time.Time) *Stats {
+	first := now.AddDate(0, 0, 1-StatsDays)
+	today := now.Format("2006-01-02")
+	weekStart, _ := storage.PeriodWeek.Bounds(now)
+	week := weekStart.Format("2006-01-02")
+
+	stats := &Stats{
+		From:     first.Format("2006-01-02"),
+		Timezone: now.Location().String(),
+		Hosts:    []HostStats{},
+	}
+
+	days := make(map[string]int, StatsDays)
+	for i := 0; i < StatsDays; i++ {
+		days[first.AddDate(0, 0, i).Format("2006-01-02")] = i
+	}
+
+	hosts := make(map[string]*HostStats)
+	var order []string
+	for _, d := range daily {
+		day, ok := days[d.Stamp]
+		if !ok {
+			continue
+		}
+
+		host, ok := hosts[d.Hostname]
+		if !ok {
+			host = &HostStats{
+				Hostname: d.Hostname,
+				Daily:    make([]int64, StatsDays),
+			}
+			hosts[d.Hostname] = host
+			order = append(order, d.Hostname)
+		}
+
+		host.Daily[day] += d.Count
+		host.Total += d.Count
+		stats.Total += d.Count
+		if d.Stamp >= week {
+			host.Week += d.Count
+			stats.Week += d.Count
+		}
+		if d.Stamp == today {
+			host.Today += d.Count
+			stats.Today += d.Count
+		}
+	}
+
+	for _, hostname := range order {
+		stats.Hosts = append(stats.Hosts, *hosts[hostname])
+	}
+	sort.SliceStable(stats.Hosts, func(i, j int) bool {
+		return stats.Hosts[i].Total > stats.Hosts[j].Total
+	})
+	return stats
+}
+
+// GetStats returns keystroke totals of the authenticated user.
+func (h *Handlers) GetStats(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.getStats(w, r))
+}
+
+func (h *Handlers) getStats(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	sessionUser, ok := user.GetSessionUser(ctx)
+	if !ok {
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: user.ErrLoginRequired}
+	}
+
+	now := time.Now().In(location(r, sessionUser))
+
+	daily, err := h.storage.GetUserDaily(ctx, sessionUser.ID, storage.Range{
+		From: now.AddDate(0, 0, 1-StatsDays),
+		To:   now,
+	})
+	if err != nil {
+		return err
+	}
+
+	platform.JSON(w, r, http.StatusOK, summarizeStats(daily, now))
+	return nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/pulse/storage"
+)
+
+func TestSummarizeStats(t *testing.T) {
+	// Thursday, the week started on Monday 2026-03-09.
+	now := time.Date(2026, 3, 12, 10, 0, 0, 0, time.UTC)
+	stats := summarizeStats([]storage.DailyHostCount{
+		{Hostname: "laptop", Stamp: "2026-02-10", Count: 1000},
+		{Hostname: "laptop", Stamp: "2026-02-11", Count: 100},
+		{Hostname: "laptop", Stamp: "2026-03-12", Count: 10},
+		{Hostname: "desktop", Stamp: "2026-03-08", Count: 500},
+		{Hostname: "desktop", Stamp: "2026-03-09", Count: 20},
+	}, now)
+
+	assert.Equal(t, "2026-02-11", stats.From)
+	assert.Equal(t, "UTC", stats.Timezone)
+	assert.Equal(t, int64(10), stats.Today)
+	assert.Equal(t, int64(30), stats.Week)
+	assert.Equal(t, int64(630), stats.Total)
+
+	require.Len(t, stats.Hosts, 2)
+	assert.Equal(t, HostStats{Hostname: "desktop", Today: 0, Week: 20, Total: 520, Daily: stats.Hosts[0].Daily}, stats.Hosts[0])
+	assert.Equal(t, int64(500), stats.Hosts[0].Daily[StatsDays-5])
+	assert.Equal(t, "laptop", stats.Hosts[1].Hostname)
+	assert.Equal(t, int64(100), stats.Hosts[1].Daily[0])
+	assert.Equal(t, int64(10), stats.Hosts[1].Daily[StatsDays-1])
+
+	assert.Empty(t, summarizeStats(nil, now).Hosts)
+}
@@ -0,0 +1,287 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// IdleGap is the longest pause between active minutes of a typing
+// session. A longer pause starts a new session.
+const IdleGap = 5 * time.Minute
+
+// ActivityDays is the number of days covered by activity metrics.
+const ActivityDays = 30
+
+// MinuteCount holds the keystroke count of a minute.
+type MinuteCount struct {
+	Stamp string `db:"stamp" json:"stamp"`
+	Count int64  `db:"count" json:"count"`
+}
+
+// Session is a burst of typing without pauses longer than IdleGap.
+type Session struct {
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	ActiveMinutes int       `json:"active_minutes"`
+	Count         int64     `json:"count"`
+}
+
+// Duration returns the length of the session.
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// DayActivity holds activity metrics for a day.
+type DayActivity struct {
+	Date          string `json:"date"`
+	Count         int64  `json:"count"`
+	ActiveMinutes int    `json:"active_minutes"`
+	Sessions      int    `json:"sessions"`
+}
+
+// WeekActivity holds activity totals for 7 days.
+type WeekActivity struct {
+	Count         int64 `json:"count"`
+	ActiveMinutes int   `json:"active_minutes"`
+}
+
+// Activity holds metrics derived from a user's keystroke counts.
+type Activity struct {
+	// Days lists the last ActivityDays days, oldest first.
+	Days []DayActivity `json:"days"`
+	// Sessions lists typing sessions of the last ActivityDays days,
+	// newest first.
+	Sessions []Session `json:"sessions"`
+	// CurrentStreak is the number of consecutive active days up to
+	// today, or up to yesterday when there was no activity today yet.
+	CurrentStreak int `json:"current_streak"`
+	// LongestStreak is the longest run of consecutive active days.
+	LongestStreak int `json:"longest_streak"`
+	// ThisWeek covers the last 7 days including today.
+	ThisWeek WeekActivity `json:"this_week"`
+	// LastWeek covers the 7 days before ThisWeek.
+	LastWeek WeekActivity `json:"last_week"`
+	// Change is the week over week change of keystrokes in percent. It
+	// is nil when there were no keystrokes last week.
+	Change *float64 `json:"change"`
+}
+
+// GetUserMinutes returns keystroke counts per minute for a user within the
+// range, summed across hosts. Minutes are stored and returned in UTC.
+func (s *Storage) GetUserMinutes(ctx context.Context, userID string, r Range) ([]MinuteCount, error) {
+	r.From, r.To = r.From.UTC(), r.To.UTC()
+	cond, args := r.where("2006-01-02 15:04:05")
+
+	var counts []MinuteCount
+	query := `
+		SELECT stamp, SUM(count) as count
+		FROM pulse_minutely
+		WHERE user_id = ? AND ` + cond + `
+		GROUP BY stamp
+		ORDER BY stamp`
+	if err := s.db.SelectContext(ctx, &counts, query, append([]any{userID}, args...)...); err != nil {
+		return nil, fmt.Errorf("get user minutes: %w", err)
+	}
+	return counts, nil
+}
+
+// GetLastActive returns the most recent minute with keystrokes of a user,
+// with the host they were typed on, or nil when there is none. Hosts in
+// exclude are left out.
+func (s *Storage) GetLastActive(ctx context.Context, userID string, exclude ...string) (*DailyHostCount, error) {
+	cond, args := excludeHosts(exclude)
+	if cond == "" {
+		cond = "1=1"
+	}
+
+	var last []DailyHostCount
+	query := `
+		SELECT hostname, stamp, count
+		FROM pulse_minutely
+		WHERE user_id = ? AND ` + cond + `
+		ORDER BY stamp DESC, count DESC
+		LIMIT 1`
+	if err := s.db.SelectContext(ctx, &last, query, append([]any{userID}, args...)...); err != nil {
+		return nil, fmt.Errorf("get last active: %w", err)
+	}
+	if len(last) == 0 {
+		return nil, nil
+	}
+	return &last[0], nil
+}
+
+// GetActiveDates returns the dates with keystrokes for a user, oldest
+// first. Hosts in exclude are left out.
+func (s *Storage) GetActiveDates(ctx context.Context, userID string, exclude ...string) ([]string, error) {
+	cond, args := excludeHosts(exclude)
+	if cond == "" {
+		cond = "1=1"
+	}
+
+	var dates []string
+	query := `
+		SELECT DISTINCT date(stamp) as stamp
+		FROM pulse_daily
+		WHERE user_id = ? AND count > 0 AND ` + cond + `
+		ORDER BY stamp`
+	if err := s.db.SelectContext(ctx, &dates, query, append([]any{userID}, args...)...); err != nil {
+		return nil, fmt.Errorf("get active dates: %w", err)
+	}
+	return dates, nil
+}
+
+// GetUserActivity returns activity metrics for a user. Days are taken in
+// the given location. Hosts in exclude are left out.
+func (s *Storage) GetUserActivity(ctx context.Context, userID string, loc *time.Location, exclude ...string) (*Activity, error) {
+	rng := LastDays(ActivityDays, loc)
+	rng.Exclude = exclude
+	now := rng.To
+
+	minutes, err := s.GetUserMinutes(ctx, userID, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := FindSessions(minutes, IdleGap)
+	if err != nil {
+		return nil, fmt.Errorf("get user activity: %w", err)
+	}
+
+	dates, err := s.GetActiveDates(ctx, userID, exclude...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Week over week keystrokes come from daily rows, which go
+	// further back than per-minute rows.
+	weeks := Range{
+		From:    now.AddDate(0, 0, -13),
+		To:      now,
+		Exclude: exclude,
+	}
+	daily, err := s.GetUserDaily(ctx, userID, weeks)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Activity{
+		Days:     make([]DayActivity, ActivityDays),
+		Sessions: make([]Session, 0, len(sessions)),
+	}
+
+	index := make(map[string]int, ActivityDays)
+	for i := range result.Days {
+		date := now.AddDate(0, 0, i-ActivityDays+1).Format("2006-01-02")
+		result.Days[i].Date = date
+		index[date] = i
+	}
+
+	thisWeek := now.AddDate(0, 0, -6).Format("2006-01-02")
+	lastWeek := now.AddDate(0, 0, -13).Format("2006-01-02")
+
+	for _, minute := range minutes {
+		stamp, err := ParseStamp(minute.Stamp)
+		if err != nil {
+			return nil, fmt.Errorf("get user activity: %w", err)
+		}
+		date := stamp.In(loc).Format("2006-01-02")
+		if d, ok := index[date]; ok {
+			result.Days[d].Count += minute.Count
+			result.Days[d].ActiveMinutes++
+		}
+		switch {
+		case date >= thisWeek:
+			result.ThisWeek.ActiveMinutes++
+		case date >= lastWeek:
+			result.LastWeek.ActiveMinutes++
+		}
+	}
+
+	for i := len(sessions) - 1; i >= 0; i-- {
+		session := sessions[i]
+		session.Start, session.End = session.Start.In(loc), session.End.In(loc)
+		if d, ok := index[session.Start.Format("2006-01-02")]; ok {
+			result.Days[d].Sessions++
+		}
+		result.Sessions = append(result.Sessions, session)
+	}
+
+	for _, day := range daily {
+		switch {
+		case day.Stamp >= thisWeek:
+			result.ThisWeek.Count += day.Count
+		case day.Stamp >= lastWeek:
+			result.LastWeek.Count += day.Count
+		}
+	}
+	if result.LastWeek.Count > 0 {
+		change := float64(result.ThisWeek.Count-result.LastWeek.Count) * 100 / float64(result.LastWeek.Count)
+		result.Change = &change
+	}
+
+	result.CurrentStreak, result.LongestStreak = Streaks(dates, now)
+
+	return result, nil
+}
+
+// FindSessions groups active minutes into typing sessions. Minutes must
+// be ordered by stamp. A pause longer than gap starts a new session.
+func FindSessions(minutes []MinuteCount, gap time.Duration) ([]Session, error) {
+	var sessions []Session
+	var last time.Time
+	for _, minute := range minutes {
+		if minute.Count <= 0 {
+			continue
+		}
+
+		stamp, err := ParseStamp(minute.Stamp)
+		if err != nil {
+			return nil, err
+		}
+
+		// The pause is measured from the end of the last active minute.
+		if len(sessions) == 0 || stamp.Sub(last.Add(time.Minute)) > gap {
+			sessions = append(sessions, Session{
+				Start: stamp,
+			})
+		}
+
+		session := &sessions[len(sessions)-1]
+		session.End = stamp.Add(time.Minute)
+		session.ActiveMinutes++
+		session.Count += minute.Count
+		last = stamp
+	}
+	return sessions, nil
+}
+
+// Streaks returns the current and the longest run of consecutive dates.
+// Dates are YYYY-MM-DD strings in ascending order. The current streak
+// ends today, or yesterday when today has no activity yet.
+func Streaks(dates []string, today time.Time) (current, longest int) {
+	var run int
+	var prev time.Time
+	for _, date := range dates {
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+
+		if run > 0 && prev.AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+		prev = day
+	}
+
+	if run > 0 {
+		last := prev.Format("2006-01-02")
+		if last == today.Format("2006-01-02") || last == today.AddDate(0, 0, -1).Format("2006-01-02") {
+			current = run
+		}
+	}
+	return current, longest
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/user"
+	"github.com/titpetric/platform-app/user/model"
+)
+
+func TestFindSessions(t *testing.T) {
+	minutes := []MinuteCount{
+		{Stamp: "2025-01-01 10:00:00", Count: 10},
+		{Stamp: "2025-01-01 10:01:00", Count: 20},
+		{Stamp: "2025-01-01 10:06:00", Count: 5},
+		{Stamp: "2025-01-01 10:14:00", Count: 7},
+		{Stamp: "2025-01-01 10:15:00", Count: 0},
+	}
+
+	sessions, err := FindSessions(minutes, IdleGap)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	assert.Equal(t, time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), sessions[0].Start)
+	assert.Equal(t, 7*time.Minute, sessions[0].Duration())
+	assert.Equal(t, 3, sessions[0].ActiveMinutes)
+	assert.Equal(t, int64(35), sessions[0].Count)
+
+	assert.Equal(t, 1, sessions[1].ActiveMinutes)
+	assert.Equal(t, int64(7), sessions[1].Count)
+
+	_, err = FindSessions([]MinuteCount{{Stamp: "soon", Count: 1}}, IdleGap)
+	assert.Error(t, err)
+}
+
+func TestStreaks(t *testing.T) {
+	today := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	current, longest := Streaks([]string{"2025-03-01", "2025-03-02", "2025-03-03", "2025-03-08", "2025-03-09"}, today)
+	assert.Equal(t, 2, current)
+	assert.Equal(t, 3, longest)
+
+	current, longest = Streaks([]string{"2025-03-01", "2025-03-02"}, today)
+	assert.Equal(t, 0, current)
+	assert.Equal(t, 2, longest)
+
+	current, longest = Streaks(nil, today)
+	assert.Equal(t, 0, current)
+	assert.Equal(t, 0, longest)
+}
+
+func TestGetUserActivity(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER"})
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	start := now.Add(-30 * time.Minute)
+
+	err := s.PulseBatch(ctx, "", []Entry{
+		{Hostname: "lab", Stamp: start, Count: 10},
+		{Hostname: "chronos", Stamp: start, Count: 5},
+		{Hostname: "lab", Stamp: start.Add(2 * time.Minute), Count: 10},
+		{Hostname: "lab", Stamp: start.Add(20 * time.Minute), Count: 3},
+	})
+	require.NoError(t, err)
+
+	seedDaily(t, s, "TESTUSER", []struct {
+		hostname string
+		stamp    string
+		count    int64
+	}{
+		{"lab", now.AddDate(0, 0, -8).Format("2006-01-02"), 14},
+	})
+
+	activity, err := s.GetUserActivity(ctx, "TESTUSER", time.UTC)
+	require.NoError(t, err)
+
+	require.Len(t, activity.Days, ActivityDays)
+	require.Len(t, activity.Sessions, 2)
+	assert.Equal(t, int64(3), activity.Sessions[0].Count)
+	assert.Equal(t, int64(25), activity.Sessions[1].Count)
+	assert.Equal(t, 3, activity.ThisWeek.ActiveMinutes)
+	assert.Equal(t, int64(28), activity.ThisWeek.Count)
+	assert.Equal(t, int64(14), activity.LastWeek.Count)
+	require.NotNil(t, activity.Change)
+	assert.InDelta(t, 100, *activity.Change, 0.01)
+	// Close to midnight the sessions span two days.
+	assert.GreaterOrEqual(t, activity.CurrentStreak, 1)
+	assert.Equal(t, activity.CurrentStreak, activity.LongestStreak)
+
+	// Hidden hosts are left out.
+	activity, err = s.GetUserActivity(ctx, "TESTUSER", time.UTC, "chronos")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), activity.Sessions[1].Count)
+}
@@ -0,0 +1,413 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/pulse/model"
+)
+
+// dataTable is a table holding user data. Dates and Stamps list the DATE
+// and DATETIME columns, which are exported in a portable format.
+type dataTable struct {
+	Name   string
+	Dates  []string
+	Stamps []string
+}
+
+// dataTables are the tables holding user data, keyed by user_id.
+var dataTables = []dataTable{
+	{Name: "pulse_daily", Dates: []string{"stamp"}},
+	{Name: "pulse_daily_category", Dates: []string{"stamp"}},
+	{Name: "pulse_device_key", Stamps: []string{"created_at", "last_used_at", "revoked_at"}},
+	{Name: "pulse_goal", Dates: []string{"settled_on"}, Stamps: []string{"created_at"}},
+	{Name: "pulse_hosts", Stamps: []string{"created_at"}},
+	{Name: "pulse_hourly", Stamps: []string{"stamp"}},
+	{Name: "pulse_hourly_category", Stamps: []string{"stamp"}},
+	{Name: "pulse_ingest", Stamps: []string{"created_at"}},
+	{Name: "pulse_minutely", Stamps: []string{"stamp"}},
+	{Name: "pulse_monthly", Dates: []string{"stamp"}},
+	{Name: "pulse_profile", Stamps: []string{"updated_at"}},
+	{Name: "pulse_weekly", Dates: []string{"stamp"}},
+}
+
+// findDataTable returns the data table with the given name.
+func findDataTable(name string) (*dataTable, bool) {
+	for i := range dataTables {
+		if dataTables[i].Name == name {
+			return &dataTables[i], true
+		}
+	}
+	return nil, false
+}
+
+// ListDataUsers returns the IDs of all users with data in any pulse table.
+func (s *Storage) ListDataUsers(ctx context.Context) ([]string, error) {
+	selects := make([]string, 0, len(dataTables))
+	for _, table := range dataTables {
+		selects = append(selects, `SELECT user_id FROM `+table.Name)
+	}
+
+	var userIDs []string
+	query := `SELECT DISTINCT user_id FROM (` + strings.Join(selects, " UNION ") + `) u ORDER BY user_id`
+	if err := s.db.SelectContext(ctx, &userIDs, query); err != nil {
+		return nil, fmt.Errorf("list data users: %w", err)
+	}
+	return userIDs, nil
+}
+
+// DeleteUserData deletes all pulse data of a user, and returns the
+// number of deleted rows.
+func (s *Storage) DeleteUserData(ctx context.Context, userID string) (int64, error) {
+	var deleted int64
+	err := platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		for _, table := range dataTables {
+			n, err := exec(ctx, tx, `DELETE FROM `+table.Name+` WHERE user_id = ?`, userID)
+			if err != nil {
+				return err
+			}
+			deleted += n
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("delete user data: %w", err)
+	}
+	return deleted, nil
+}
+
+// RebuildDaily recomputes the daily rows of a user from the hourly rows,
+// with days taken in loc, and returns the number of changed daily rows.
+// Only days fully covered by hourly rows are rebuilt, older daily rows
+// are kept. Changes to rolled up days are applied to the weekly and
+// monthly rollups. Daily category rows are rebuilt from the hourly
+// category rows in the same way. Hourly rows are bucketed by their UTC hour, so in
+// timezones offset by a fraction of an hour some keystrokes may move to
+// a neighbouring day.
+func (s *Storage) RebuildDaily(ctx context.Context, userID string, loc *time.Location) (int64, error) {
+	var changed int64
+	err := platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		var hourly []DailyHostCount
+		query := tx.Rebind(`SELECT hostname, stamp, count FROM pulse_hourly WHERE user_id = ? ORDER BY stamp`)
+		if err := tx.SelectContext(ctx, &hourly, query, userID); err != nil {
+			return fmt.Errorf("error in %s: %w", query, err)
+		}
+		if len(hourly) == 0 {
+			return rebuildDailyCategory(ctx, tx, userID, loc)
+		}
+
+		from, err := firstFullDay(hourly[0].Stamp, loc)
+		if err != nil {
+			return err
+		}
+
+		type dayKey struct {
+			hostname string
+			stamp    string
+		}
+		counts := make(map[dayKey]int64)
+		for _, h := range hourly {
+			stamp, err := ParseStamp(h.Stamp)
+			if err != nil {
+				return err
+			}
+			day := stamp.In(loc).Format("2006-01-02")
+			if day >= from {
+				counts[dayKey{h.Hostname, day}] += h.Count
+			}
+		}
+
+		var daily []struct {
+			DailyHostCount
+			RolledUp bool `db:"rolled_up"`
+		}
+		query = tx.Rebind(`SELECT hostname, date(stamp) as stamp, count, rolled_up FROM pulse_daily WHERE user_id = ? AND stamp >= ?`)
+		if err := tx.SelectContext(ctx, &daily, query, userID, from); err != nil {
+			return fmt.Errorf("error in %s: %w", query, err)
+		}
+
+		for _, d := range daily {
+			key := dayKey{d.Hostname, d.Stamp}
+			count, ok := counts[key]
+			delete(counts, key)
+			if count == d.Count {
+				continue
+			}
+
+			if ok {
+				_, err = exec(ctx, tx, `UPDATE pulse_daily SET count = ? WHERE user_id = ? AND hostname = ? AND stamp = ?`, count, userID, d.Hostname, d.Stamp)
+			} else {
+				_, err = exec(ctx, tx, `DELETE FROM pulse_daily WHERE user_id = ? AND hostname = ? AND stamp = ?`, userID, d.Hostname, d.Stamp)
+			}
+			if err != nil {
+				return err
+			}
+			if d.RolledUp {
+				if err := addRollup(ctx, tx, userID, d.Hostname, d.Stamp, count-d.Count); err != nil {
+					return err
+				}
+			}
+			changed++
+		}
+
+		for key, count := range counts {
+			if _, err := exec(ctx, tx, `INSERT INTO pulse_daily (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`, userID, key.hostname, key.stamp, count); err != nil {
+				return err
+			}
+			changed++
+		}
+		return rebuildDailyCategory(ctx, tx, userID, loc)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rebuild daily: %w", err)
+	}
+	return changed, nil
+}
+
+// rebuildDailyCategory recomputes the daily category rows of a user from
+// the hourly category rows, with days taken in loc. Only days fully
+// covered by hourly category rows are rebuilt.
+func rebuildDailyCategory(ctx context.Context, tx *sqlx.Tx, userID string, loc *time.Location) error {
+	var hourly []struct {
+		HostCategoryCount
+		Stamp string `db:"stamp"`
+	}
+	query := tx.Rebind(`SELECT hostname, category, stamp, count FROM pulse_hourly_category WHERE user_id = ? ORDER BY stamp`)
+	if err := tx.SelectContext(ctx, &hourly, query, userID); err != nil {
+		return fmt.Errorf("error in %s: %w", query, err)
+	}
+	if len(hourly) == 0 {
+		return nil
+	}
+
+	from, err := firstFullDay(hourly[0].Stamp, loc)
+	if err != nil {
+		return err
+	}
+
+	type dayKey struct {
+		hostname string
+		stamp    string
+		category model.Category
+	}
+	counts := make(map[dayKey]int64)
+	for _, h := range hourly {
+		stamp, err := ParseStamp(h.Stamp)
+		if err != nil {
+			return err
+		}
+		day := stamp.In(loc).Format("2006-01-02")
+		if day >= from {
+			counts[dayKey{h.Hostname, day, h.Category}] += h.Count
+		}
+	}
+
+	if _, err := exec(ctx, tx, `DELETE FROM pulse_daily_category WHERE user_id = ? AND stamp >= ?`, userID, from); err != nil {
+		return err
+	}
+	for key, count := range counts {
+		if _, err := exec(ctx, tx, `INSERT INTO pulse_daily_category (user_id, hostname, stamp, category, count) VALUES (?, ?, ?, ?, ?)`, userID, key.hostname, key.stamp, key.category, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// firstFullDay returns the first day in loc fully covered by hourly rows
+// starting at stamp. The first day may be partly pruned, unless it starts
+// at midnight.
+func firstFullDay(stamp string, loc *time.Location) (string, error) {
+	first, err := ParseStamp(stamp)
+	if err != nil {
+		return "", err
+	}
+	first = first.In(loc)
+	start := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+	if !start.Equal(first) {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start.Format("2006-01-02"), nil
+}
+
+// DataRow is a table row in a data export.
+type DataRow struct {
+	Table string         `json:"table"`
+	Row   map[string]any `json:"row"`
+}
+
+// exportValue converts a column value to a driver independent JSON
+// value. Dates are written as YYYY-MM-DD and timestamps as UTC
+// YYYY-MM-DD HH:MM:SS, the way ingest stores them.
+func exportValue(table *dataTable, column string, value any) (any, error) {
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+
+	layout := ""
+	switch {
+	case slices.Contains(table.Dates, column):
+		layout = "2006-01-02"
+	case slices.Contains(table.Stamps, column):
+		layout = "2006-01-02 15:04:05"
+	}
+	if layout == "" || value == nil {
+		return value, nil
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		if layout == "2006-01-02" {
+			return v.Format(layout), nil
+		}
+		return v.UTC().Format(layout), nil
+	case string:
+		stamp, err := parseExportStamp(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", table.Name, column, err)
+		}
+		if layout == "2006-01-02" {
+			return stamp.Format(layout), nil
+		}
+		return stamp.UTC().Format(layout), nil
+	}
+	return value, nil
+}
+
+// parseExportStamp parses stamps as stored by ingest, and timestamps as
+// stored by the database drivers.
+func parseExportStamp(value string) (time.Time, error) {
+	for _, layout := range []string{
+		"2006-01-02 15:04:05.999999999-07:00",
+		"2006-01-02 15:04:05.999999999 -0700 MST",
+	} {
+		if stamp, err := time.Parse(layout, value); err == nil {
+			return stamp, nil
+		}
+	}
+	return ParseStamp(value)
+}
+
+// ExportData writes all rows of the data tables to w as newline delimited
+// JSON, one DataRow per line, and returns the number of rows written.
+// Values are database independent, but pulse storage only supports
+// SQLite, so exports can't be imported into MySQL.
+func (s *Storage) ExportData(ctx context.Context, w io.Writer) (int64, error) {
+	var written int64
+	enc := json.NewEncoder(w)
+	for i := range dataTables {
+		table := &dataTables[i]
+
+		rows, err := s.db.QueryxContext(ctx, `SELECT * FROM `+table.Name)
+		if err != nil {
+			return written, fmt.Errorf("export %s: %w", table.Name, err)
+		}
+
+		for rows.Next() {
+			row := make(map[string]any)
+			if err := rows.MapScan(row); err != nil {
+				rows.Close()
+				return written, fmt.Errorf("export %s: %w", table.Name, err)
+			}
+			for column, value := range row {
+				if row[column], err = exportValue(table, column, value); err != nil {
+					rows.Close()
+					return written, fmt.Errorf("export: %w", err)
+				}
+			}
+			if err := enc.Encode(DataRow{Table: table.Name, Row: row}); err != nil {
+				rows.Close()
+				return written, fmt.Errorf("export %s: %w", table.Name, err)
+			}
+			written++
+		}
+		if err := rows.Close(); err != nil {
+			return written, fmt.Errorf("export %s: %w", table.Name, err)
+		}
+		if err := rows.Err(); err != nil {
+			return written, fmt.Errorf("export %s: %w", table.Name, err)
+		}
+	}
+	return written, nil
+}
+
+// columnName matches column names accepted on import.
+var columnName = regexp.MustCompile(`^[a-z_]+$`)
+
+// ImportData inserts rows written by ExportData, in a single transaction,
+// and returns the number of rows inserted. Rows are inserted as is, so
+// the import fails on rows that already exist.
+func (s *Storage) ImportData(ctx context.Context, r io.Reader) (int64, error) {
+	var imported int64
+	err := platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+		line := 0
+		for scanner.Scan() {
+			line++
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+
+			dec := json.NewDecoder(strings.NewReader(scanner.Text()))
+			dec.UseNumber()
+
+			var data DataRow
+			if err := dec.Decode(&data); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if _, ok := findDataTable(data.Table); !ok {
+				return fmt.Errorf("line %d: unknown table %q", line, data.Table)
+			}
+			if len(data.Row) == 0 {
+				return fmt.Errorf("line %d: empty row", line)
+			}
+
+			columns := make([]string, 0, len(data.Row))
+			for column := range data.Row {
+				if !columnName.MatchString(column) {
+					return fmt.Errorf("line %d: invalid column %q", line, column)
+				}
+				columns = append(columns, column)
+			}
+			sort.Strings(columns)
+
+			args := make([]any, len(columns))
+			for i, column := range columns {
+				args[i] = data.Row[column]
+				if n, ok := args[i].(json.Number); ok {
+					if v, err := n.Int64(); err == nil {
+						args[i] = v
+					} else if v, err := n.Float64(); err == nil {
+						args[i] = v
+					}
+				}
+			}
+
+			query := `INSERT INTO ` + data.Table + ` (` + strings.Join(columns, ", ") + `) VALUES (?` + strings.Repeat(", ?", len(columns)-1) + `)`
+			if _, err := exec(ctx, tx, query, args...); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			imported++
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("line %d: %w", line+1, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("import: %w", err)
+	}
+	return imported, nil
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/user"
+	"github.com/titpetric/platform-app/user/model"
+)
+
+func TestDeleteUserData(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	for _, userID := range []string{"TESTUSER", "OTHERUSER"} {
+		sessionCtx := user.SetSessionUser(ctx, &model.User{ID: userID})
+		require.NoError(t, s.Pulse(sessionCtx, 10, "laptop"))
+	}
+	require.NoError(t, s.SetVisibility(ctx, "TESTUSER", VisibilityPrivate))
+
+	userIDs, err := s.ListDataUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OTHERUSER", "TESTUSER"}, userIDs)
+
+	deleted, err := s.DeleteUserData(ctx, "TESTUSER")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), deleted)
+
+	userIDs, err = s.ListDataUsers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OTHERUSER"}, userIDs)
+}
+
+func TestRebuildDaily(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	loc, err := time.LoadLocation("Europe/Ljubljana")
+	require.NoError(t, err)
+
+	// Hourly rows start at 10:00 local time on March 10, so that day is
+	// partly pruned and kept as is.
+	for _, row := range []struct {
+		stamp string
+		count int64
+	}{
+		{"2026-03-10 09:00:00", 5},
+		{"2026-03-10 23:00:00", 10},
+		{"2026-03-11 08:00:00", 20},
+		{"2026-03-12 10:00:00", 30},
+	} {
+		_, err := s.db.Exec(`INSERT INTO pulse_hourly (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`, "TESTUSER", "laptop", row.stamp, row.count)
+		require.NoError(t, err)
+	}
+	for _, row := range []struct {
+		stamp    string
+		count    int64
+		rolledUp int
+	}{
+		{"2026-03-10", 99, 1},
+		{"2026-03-11", 25, 1},
+		{"2026-03-13", 40, 0},
+	} {
+		_, err := s.db.Exec(`INSERT INTO pulse_daily (user_id, hostname, stamp, count, rolled_up) VALUES (?, ?, ?, ?, ?)`, "TESTUSER", "laptop", row.stamp, row.count, row.rolledUp)
+		require.NoError(t, err)
+	}
+	_, err = s.db.Exec(`INSERT INTO pulse_monthly (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`, "TESTUSER", "laptop", "2026-03-01", 124)
+	require.NoError(t, err)
+
+	changed, err := s.RebuildDaily(ctx, "TESTUSER", loc)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), changed)
+
+	daily, err := s.GetUserDaily(ctx, "TESTUSER", Range{
+		From: time.Date(2026, 3, 1, 0, 0, 0, 0, loc),
+		To:   time.Date(2026, 3, 31, 0, 0, 0, 0, loc),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []DailyHostCount{
+		{Hostname: "laptop", Stamp: "2026-03-10", Count: 99},
+		{Hostname: "laptop", Stamp: "2026-03-11", Count: 30},
+		{Hostname: "laptop", Stamp: "2026-03-12", Count: 30},
+	}, daily)
+
+	// The rolled up day changed by 5 keystrokes.
+	var monthly int64
+	require.NoError(t, s.db.Get(&monthly, `SELECT count FROM pulse_monthly WHERE user_id = ?`, "TESTUSER"))
+	assert.Equal(t, int64(129), monthly)
+
+	changed, err = s.RebuildDaily(ctx, "TESTUSER", loc)
+	require.NoError(t, err)
+	assert.Zero(t, changed)
+}
+
+func TestRebuildDailyCategory(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	loc, err := time.LoadLocation("Europe/Ljubljana")
+	require.NoError(t, err)
+
+	// Hourly category rows start at 10:00 local time on March 10, so that
+	// day is partly pruned and kept as is.
+	for _, row := range []struct {
+		stamp    string
+		category string
+		count    int64
+	}{
+		{"2026-03-10 09:00:00", "alphanumeric", 5},
+		{"2026-03-10 23:00:00", "alphanumeric", 10},
+		{"2026-03-10 23:00:00", "delete", 2},
+		{"2026-03-11 08:00:00", "alphanumeric", 20},
+	} {
+		_, err := s.db.Exec(`INSERT INTO pulse_hourly_category (user_id, hostname, stamp, category, count) VALUES (?, ?, ?, ?, ?)`, "TESTUSER", "laptop", row.stamp, row.category, row.count)
+		require.NoError(t, err)
+	}
+	// Daily category rows as built in UTC.
+	for _, row := range []struct {
+		stamp    string
+		category string
+		count    int64
+	}{
+		{"2026-03-10", "alphanumeric", 99},
+		{"2026-03-10", "delete", 2},
+		{"2026-03-11", "alphanumeric", 20},
+	} {
+		_, err := s.db.Exec(`INSERT INTO pulse_daily_category (user_id, hostname, stamp, category, count) VALUES (?, ?, ?, ?, ?)`, "TESTUSER", "laptop", row.stamp, row.category, row.count)
+		require.NoError(t, err)
+	}
+
+	_, err = s.RebuildDaily(ctx, "TESTUSER", loc)
+	require.NoError(t, err)
+
+	type categoryRow struct {
+		Stamp    string `db:"stamp"`
+		Category string `db:"category"`
+		Count    int64  `db:"count"`
+	}
+	var rows []categoryRow
+	require.NoError(t, s.db.Select(&rows, `SELECT date(stamp) as stamp, category, count FROM pulse_daily_category WHERE user_id = ? ORDER BY stamp, category`, "TESTUSER"))
+	assert.Equal(t, []categoryRow{
+		{"2026-03-10", "alphanumeric", 99},
+		{"2026-03-10", "delete", 2},
+		{"2026-03-11", "alphanumeric", 30},
+		{"2026-03-11", "delete", 2},
+	}, rows)
+}
+
+func TestExportImportData(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER"})
+
+	stamp := time.Now().UTC().Add(-time.Hour)
+	require.NoError(t, s.PulseBatch(ctx, "01HZX3K8M6Q0W6R4T2Y9B1C5D7", []Entry{{Hostname: "laptop", Stamp: stamp, Count: 10}}))
+	require.NoError(t, s.CreateGoal(ctx, &Goal{UserID: "TESTUSER", Metric: GoalKeystrokes, Comparison: GoalAtLeast, Threshold: 100, Days: GoalEveryDay, ToHour: 24}))
+
+	var buf bytes.Buffer
+	written, err := s.ExportData(ctx, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), written)
+	assert.Contains(t, buf.String(), `{"table":"pulse_hourly","row":{"count":10,"hostname":"laptop","stamp":"`+stamp.Truncate(time.Hour).Format("2006-01-02 15:04:05")+`","user_id":"TESTUSER"}}`)
+
+	target := newTestStorage(t)
+	imported, err := target.ImportData(ctx, strings.NewReader(buf.String()))
+	require.NoError(t, err)
+	assert.Equal(t, written, imported)
+
+	var exported bytes.Buffer
+	_, err = target.ExportData(ctx, &exported)
+	require.NoError(t, err)
+	assert.Equal(t, buf.String(), exported.String())
+
+	// Ingest keeps adding to imported rows.
+	require.NoError(t, target.PulseBatch(ctx, "", []Entry{{Hostname: "laptop", Stamp: stamp, Count: 5}}))
+	var count int64
+	require.NoError(t, target.db.Get(&count, `SELECT count FROM pulse_hourly WHERE user_id = ?`, "TESTUSER"))
+	assert.Equal(t, int64(15), count)
+
+	_, err = target.ImportData(ctx, strings.NewReader(buf.String()))
+	assert.Error(t, err)
+
+	_, err = target.ImportData(ctx, strings.NewReader(`{"table":"user","row":{"id":"x"}}`))
+	assert.ErrorContains(t, err, "unknown table")
+	_, err = target.ImportData(ctx, strings.NewReader(`{"table":"pulse_daily","row":{"count) --":1}}`))
+	assert.ErrorContains(t, err, "invalid column")
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DayCount holds the keystrokes of a day, summed across hosts.
+type DayCount struct {
+	Stamp string `db:"stamp" json:"stamp"`
+	Count int64  `db:"count" json:"count"`
+}
+
+// Punchcard holds keystrokes per weekday and hour of day. It is indexed
+// by time.Weekday, so Sunday comes first.
+type Punchcard [7][24]int64
+
+// Max returns the highest count on the punchcard.
+func (p *Punchcard) Max() int64 {
+	var result int64
+	for _, hours := range p {
+		for _, count := range hours {
+			result = max(result, count)
+		}
+	}
+	return result
+}
+
+// GetUserCalendar returns keystrokes per day for a user within the range,
+// summed across hosts, oldest first. Days without keystrokes are left out.
+func (s *Storage) GetUserCalendar(ctx context.Context, userID string, r Range) ([]DayCount, error) {
+	cond, args := r.where("2006-01-02")
+
+	var counts []DayCount
+	query := `
+		SELECT date(stamp) as stamp, SUM(count) as count
+		FROM pulse_daily
+		WHERE user_id = ? AND count > 0 AND ` + cond + `
+		GROUP BY date(stamp)
+		ORDER BY stamp`
+	if err := s.db.SelectContext(ctx, &counts, query, append([]any{userID}, args...)...); err != nil {
+		return nil, fmt.Errorf("get user calendar: %w", err)
+	}
+	return counts, nil
+}
+
+// GetUserPunchcard returns keystrokes per weekday and hour for a user
+// within the range, from hourly rows. Weekdays and hours are taken in the
+// given location.
+func (s *Storage) GetUserPunchcard(ctx context.Context, userID string, loc *time.Location, r Range) (*Punchcard, error) {
+	r.From, r.To = r.From.UTC(), r.To.UTC()
+	cond, args := r.where("2006-01-02 15:04:05")
+
+	var rows []DayCount
+	query := `
+		SELECT stamp, SUM(count) as count
+		FROM pulse_hourly
+		WHERE user_id = ? AND ` + cond + `
+		GROUP BY stamp`
+	if err := s.db.SelectContext(ctx, &rows, query, append([]any{userID}, args...)...); err != nil {
+		return nil, fmt.Errorf("get user punchcard: %w", err)
+	}
+
+	// Hourly rows are stored in UTC, as in GetUserHourly they are
+	// bucketed here, so daylight saving changes are respected.
+	result := &Punchcard{}
+	for _, row := range rows {
+		stamp, err := ParseStamp(row.Stamp)
+		if err != nil {
+			return nil, fmt.Errorf("get user punchcard: %w", err)
+		}
+		stamp = stamp.In(loc)
+		result[stamp.Weekday()][stamp.Hour()] += row.Count
+	}
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserCalendar(t *testing.T) {
+	s := newTestStorage(t)
+
+	seedDaily(t, s, "TESTUSER", []struct {
+		hostname string
+		stamp    string
+		count    int64
+	}{
+		{"lab", "2025-03-01", 10},
+		{"chronos", "2025-03-01", 5},
+		{"lab", "2025-03-03", 7},
+		{"lab", "2025-02-01", 3},
+	})
+
+	rng := Range{
+		From: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+	}
+	days, err := s.GetUserCalendar(context.Background(), "TESTUSER", rng)
+	require.NoError(t, err)
+	assert.Equal(t, []DayCount{
+		{Stamp: "2025-03-01", Count: 15},
+		{Stamp: "2025-03-03", Count: 7},
+	}, days)
+
+	// Hidden hosts are left out.
+	rng.Exclude = []string{"chronos"}
+	days, err = s.GetUserCalendar(context.Background(), "TESTUSER", rng)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), days[0].Count)
+}
+
+func TestGetUserPunchcard(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	// Monday 23:00 UTC is Tuesday in Ljubljana.
+	monday := time.Now().UTC().AddDate(0, 0, -14).Truncate(24 * time.Hour)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, 1)
+	}
+	at := monday.Add(23 * time.Hour)
+	stamp := at.Format("2006-01-02 15:04:05")
+
+	for host, count := range map[string]int64{"lab": 10, "chronos": 5} {
+		_, err := s.db.Exec(
+			`INSERT INTO pulse_hourly (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`,
+			"TESTUSER", host, stamp, count,
+		)
+		require.NoError(t, err)
+	}
+
+	rng := LastDays(30, time.UTC)
+	punchcard, err := s.GetUserPunchcard(ctx, "TESTUSER", time.UTC, rng)
+	require.NoError(t, err)
+	assert.Equal(t, int64(15), punchcard[time.Monday][23])
+	assert.Equal(t, int64(15), punchcard.Max())
+
+	loc, err := time.LoadLocation("Europe/Ljubljana")
+	require.NoError(t, err)
+	rng.Exclude = []string{"chronos"}
+	punchcard, err = s.GetUserPunchcard(ctx, "TESTUSER", loc, rng)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), punchcard[time.Tuesday][at.In(loc).Hour()])
+	assert.Equal(t, int64(10), punchcard.Max())
+}
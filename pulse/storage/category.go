@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/titpetric/platform-app/pulse/model"
+)
+
+// HostCategoryCount holds the keystroke count of a key category on a host.
+type HostCategoryCount struct {
+	Hostname string         `db:"hostname" json:"hostname"`
+	Category model.Category `db:"category" json:"category"`
+	Count    int64          `db:"count" json:"count"`
+}
+
+// GetUserCategories returns keystroke counts per host and key category for
+// a user within the range. Days are compared as dates in the location of
+// the range.
+func (s *Storage) GetUserCategories(ctx context.Context, userID string, r Range) ([]HostCategoryCount, error) {
+	cond, args := r.where("2006-01-02")
+
+	var counts []HostCategoryCount
+	query := `
+		SELECT hostname, category, SUM(count) as count
+		FROM pulse_daily_category
+		WHERE user_id = ? AND ` + cond + `
+		GROUP BY hostname, category
+		ORDER BY hostname, category`
+	if err := s.db.SelectContext(ctx, &counts, query, append([]any{userID}, args...)...); err != nil {
+		return nil, fmt.Errorf("get user categories: %w", err)
+	}
+	return counts, nil
+}
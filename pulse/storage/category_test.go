@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/pulse/model"
+	"github.com/titpetric/platform-app/user"
+	usermodel "github.com/titpetric/platform-app/user/model"
+)
+
+func TestGetUserCategories(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := user.SetSessionUser(context.Background(), &usermodel.User{ID: "TESTUSER"})
+
+	now := time.Now().UTC()
+
+	err := s.PulseBatch(ctx, "", []Entry{
+		{Hostname: "lab", Stamp: now, Count: 10, Categories: map[model.Category]int64{
+			model.CategoryAlphanumeric: 7,
+			model.CategoryDelete:       3,
+		}},
+		{Hostname: "lab", Stamp: now, Count: 5, Categories: map[model.Category]int64{
+			model.CategoryAlphanumeric: 5,
+		}},
+		{Hostname: "chronos", Stamp: now, Count: 4},
+	})
+	require.NoError(t, err)
+
+	counts, err := s.GetUserCategories(ctx, "TESTUSER", LastDays(1, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []HostCategoryCount{
+		{Hostname: "lab", Category: model.CategoryAlphanumeric, Count: 12},
+		{Hostname: "lab", Category: model.CategoryDelete, Count: 3},
+	}, counts)
+
+	// Hourly category rows are kept to rebuild the daily ones.
+	var hourly int64
+	require.NoError(t, s.db.Get(&hourly, `SELECT SUM(count) FROM pulse_hourly_category WHERE user_id = ? AND category = ?`, "TESTUSER", model.CategoryAlphanumeric))
+	assert.Equal(t, int64(12), hourly)
+
+	// Unknown categories are rejected.
+	err = s.PulseBatch(ctx, "", []Entry{
+		{Hostname: "lab", Stamp: now, Count: 1, Categories: map[model.Category]int64{"KEY_A": 1}},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/titpetric/platform/pkg/ulid"
+)
+
+// DeviceKeyPrefix starts every device key, telling them apart from
+// user tokens.
+const DeviceKeyPrefix = "pulse_dk_"
+
+// ErrDeviceKeyNotFound is returned for unknown or revoked device keys.
+var ErrDeviceKeyNotFound = errors.New("device key not found")
+
+// DeviceKey is a credential that can only ingest pulses for one host of
+// a user. The key itself is not stored, only its hash.
+type DeviceKey struct {
+	ID         string     `db:"id" json:"id"`
+	UserID     string     `db:"user_id" json:"-"`
+	Hostname   string     `db:"hostname" json:"hostname"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	LastUsedAt *time.Time `db:"last_used_at" json:"last_used_at"`
+}
+
+// IsDeviceKey reports whether token looks like a device key.
+func IsDeviceKey(token string) bool {
+	return strings.HasPrefix(token, DeviceKeyPrefix)
+}
+
+// hashDeviceKey returns the stored form of a device key.
+func hashDeviceKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// newDeviceKey produces a random device key.
+func newDeviceKey() string {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("pulse/storage: crypto/rand failed: " + err.Error())
+	}
+	return DeviceKeyPrefix + base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// CreateDeviceKey creates a device key bound to a host of a user. The
+// returned key is the only copy, it can't be read back later.
+func (s *Storage) CreateDeviceKey(ctx context.Context, userID, hostname string) (*DeviceKey, string, error) {
+	if !ValidHostname(hostname) {
+		return nil, "", ErrInvalidHostname
+	}
+
+	key := newDeviceKey()
+	deviceKey := &DeviceKey{
+		ID:        ulid.String(),
+		UserID:    userID,
+		Hostname:  hostname,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	query := `INSERT INTO pulse_device_key (id, user_id, hostname, key_hash, created_at) VALUES (?, ?, ?, ?, ?)`
+	if _, err := s.db.ExecContext(ctx, query, deviceKey.ID, userID, hostname, hashDeviceKey(key), deviceKey.CreatedAt); err != nil {
+		return nil, "", fmt.Errorf("create device key: %w", err)
+	}
+	return deviceKey, key, nil
+}
+
+// ListDeviceKeys returns the device keys of a user that aren't revoked.
+func (s *Storage) ListDeviceKeys(ctx context.Context, userID string) ([]DeviceKey, error) {
+	var keys []DeviceKey
+	query := `
+		SELECT id, user_id, hostname, created_at, last_used_at
+		FROM pulse_device_key
+		WHERE user_id = ? AND revoked_at IS NULL
+		ORDER BY hostname, created_at`
+	if err := s.db.SelectContext(ctx, &keys, query, userID); err != nil {
+		return nil, fmt.Errorf("list device keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeDeviceKey revokes a device key of a user.
+func (s *Storage) RevokeDeviceKey(ctx context.Context, userID, id string) error {
+	query := `UPDATE pulse_device_key SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL`
+	res, err := s.db.ExecContext(ctx, query, time.Now().UTC(), id, userID)
+	if err != nil {
+		return fmt.Errorf("revoke device key: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("revoke device key: %w", err)
+	} else if n == 0 {
+		return fmt.Errorf("%w: %s", ErrDeviceKeyNotFound, id)
+	}
+	return nil
+}
+
+// AuthenticateDeviceKey returns the device key matching key, and records
+// when it was last used. Revoked keys return ErrDeviceKeyNotFound.
+func (s *Storage) AuthenticateDeviceKey(ctx context.Context, key string) (*DeviceKey, error) {
+	if !IsDeviceKey(key) {
+		return nil, ErrDeviceKeyNotFound
+	}
+
+	var keys []DeviceKey
+	query := `
+		SELECT id, user_id, hostname, created_at, last_used_at
+		FROM pulse_device_key
+		WHERE key_hash = ? AND revoked_at IS NULL`
+	if err := s.db.SelectContext(ctx, &keys, query, hashDeviceKey(key)); err != nil {
+		return nil, fmt.Errorf("authenticate device key: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, ErrDeviceKeyNotFound
+	}
+
+	now := time.Now().UTC()
+	deviceKey := &keys[0]
+	deviceKey.LastUsedAt = &now
+
+	query = `UPDATE pulse_device_key SET last_used_at = ? WHERE id = ?`
+	if _, err := s.db.ExecContext(ctx, query, now, deviceKey.ID); err != nil {
+		return nil, fmt.Errorf("authenticate device key: %w", err)
+	}
+	return deviceKey, nil
+}
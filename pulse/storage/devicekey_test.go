@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/user"
+	"github.com/titpetric/platform-app/user/model"
+)
+
+func TestDeviceKeys(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	_, _, err := s.CreateDeviceKey(ctx, "TESTUSER", "")
+	assert.ErrorIs(t, err, ErrInvalidHostname)
+
+	created, key, err := s.CreateDeviceKey(ctx, "TESTUSER", "laptop")
+	require.NoError(t, err)
+	assert.True(t, IsDeviceKey(key))
+	assert.Equal(t, "laptop", created.Hostname)
+
+	// Only the hash is stored.
+	var stored string
+	require.NoError(t, s.db.Get(&stored, `SELECT key_hash FROM pulse_device_key WHERE id = ?`, created.ID))
+	assert.NotEqual(t, key, stored)
+
+	found, err := s.AuthenticateDeviceKey(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+	assert.Equal(t, "TESTUSER", found.UserID)
+	assert.NotNil(t, found.LastUsedAt)
+
+	_, err = s.AuthenticateDeviceKey(ctx, key+"x")
+	assert.ErrorIs(t, err, ErrDeviceKeyNotFound)
+
+	// Keys can only be revoked by their owner.
+	assert.ErrorIs(t, s.RevokeDeviceKey(ctx, "OTHERUSER", created.ID), ErrDeviceKeyNotFound)
+	require.NoError(t, s.RevokeDeviceKey(ctx, "TESTUSER", created.ID))
+	assert.ErrorIs(t, s.RevokeDeviceKey(ctx, "TESTUSER", created.ID), ErrDeviceKeyNotFound)
+
+	_, err = s.AuthenticateDeviceKey(ctx, key)
+	assert.ErrorIs(t, err, ErrDeviceKeyNotFound)
+
+	keys, err := s.ListDeviceKeys(ctx, "TESTUSER")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestDeviceKeysFollowHosts(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER"})
+
+	now := time.Now().UTC()
+	require.NoError(t, s.PulseBatch(ctx, "", []Entry{
+		{Hostname: "laptop", Stamp: now, Count: 10},
+		{Hostname: "laptop.local", Stamp: now, Count: 5},
+	}))
+
+	_, laptop, err := s.CreateDeviceKey(ctx, "TESTUSER", "laptop")
+	require.NoError(t, err)
+	_, local, err := s.CreateDeviceKey(ctx, "TESTUSER", "laptop.local")
+	require.NoError(t, err)
+
+	require.NoError(t, s.RenameHost(ctx, "TESTUSER", "laptop", "work"))
+	found, err := s.AuthenticateDeviceKey(ctx, laptop)
+	require.NoError(t, err)
+	assert.Equal(t, "work", found.Hostname)
+
+	require.NoError(t, s.MergeHosts(ctx, "TESTUSER", "laptop.local", "work"))
+	found, err = s.AuthenticateDeviceKey(ctx, local)
+	require.NoError(t, err)
+	assert.Equal(t, "work", found.Hostname)
+
+	// Deleting a host revokes its keys.
+	require.NoError(t, s.DeleteHost(ctx, "TESTUSER", "work"))
+	keys, err := s.ListDeviceKeys(ctx, "TESTUSER")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/titpetric/platform/pkg/ulid"
+)
+
+// ErrGoalNotFound is returned when a user has no goal with the given ID.
+var ErrGoalNotFound = errors.New("goal not found")
+
+// GoalMetric is the activity a goal measures per day.
+type GoalMetric string
+
+// Goal metrics.
+const (
+	// GoalKeystrokes counts keystrokes.
+	GoalKeystrokes GoalMetric = "keystrokes"
+	// GoalActiveMinutes counts minutes with at least one keystroke.
+	GoalActiveMinutes GoalMetric = "active_minutes"
+)
+
+// GoalComparison tells if a goal threshold is a limit or a target.
+type GoalComparison string
+
+// Goal comparisons.
+const (
+	// GoalAtMost alerts as soon as the threshold is exceeded.
+	GoalAtMost GoalComparison = "at_most"
+	// GoalAtLeast alerts after a day that fell short of the threshold.
+	GoalAtLeast GoalComparison = "at_least"
+)
+
+// GoalDays selects the days a goal applies to.
+type GoalDays string
+
+// Goal days.
+const (
+	GoalEveryDay GoalDays = "all"
+	GoalWeekdays GoalDays = "weekdays"
+	GoalWeekends GoalDays = "weekends"
+)
+
+// Goal is a daily threshold on a user's activity, counted from FromHour
+// to ToHour in the user timezone.
+type Goal struct {
+	ID         string         `db:"id" json:"id"`
+	UserID     string         `db:"user_id" json:"-"`
+	Metric     GoalMetric     `db:"metric" json:"metric"`
+	Comparison GoalComparison `db:"comparison" json:"comparison"`
+	Threshold  int64          `db:"threshold" json:"threshold"`
+	Days       GoalDays       `db:"days" json:"days"`
+	FromHour   int            `db:"from_hour" json:"from_hour"`
+	ToHour     int            `db:"to_hour" json:"to_hour"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	// SettledOn is the last day the goal was alerted for, or found met.
+	SettledOn *string `db:"settled_on" json:"settled_on"`
+}
+
+// Validate checks the goal settings.
+func (g *Goal) Validate() error {
+	switch g.Metric {
+	case GoalKeystrokes, GoalActiveMinutes:
+	default:
+		return fmt.Errorf("invalid goal metric: %q", g.Metric)
+	}
+	switch g.Comparison {
+	case GoalAtMost, GoalAtLeast:
+	default:
+		return fmt.Errorf("invalid goal comparison: %q", g.Comparison)
+	}
+	switch g.Days {
+	case GoalEveryDay, GoalWeekdays, GoalWeekends:
+	default:
+		return fmt.Errorf("invalid goal days: %q", g.Days)
+	}
+	if g.Threshold <= 0 {
+		return fmt.Errorf("goal threshold must be positive: %d", g.Threshold)
+	}
+	if g.FromHour < 0 || g.ToHour > 24 || g.FromHour >= g.ToHour {
+		return fmt.Errorf("invalid goal hours: %d-%d", g.FromHour, g.ToHour)
+	}
+	return nil
+}
+
+// AppliesTo reports whether the goal is checked on day.
+func (g *Goal) AppliesTo(day time.Time) bool {
+	weekend := day.Weekday() == time.Saturday || day.Weekday() == time.Sunday
+	switch g.Days {
+	case GoalWeekdays:
+		return !weekend
+	case GoalWeekends:
+		return weekend
+	}
+	return true
+}
+
+// Window returns the time span of day the goal counts activity in. Day
+// is taken in its own location.
+func (g *Goal) Window(day time.Time) (start, end time.Time) {
+	start = time.Date(day.Year(), day.Month(), day.Day(), g.FromHour, 0, 0, 0, day.Location())
+	end = time.Date(day.Year(), day.Month(), day.Day(), g.ToHour, 0, 0, 0, day.Location())
+	return start, end
+}
+
+// Met reports whether a day with value activity meets the goal.
+func (g *Goal) Met(value int64) bool {
+	if g.Comparison == GoalAtMost {
+		return value <= g.Threshold
+	}
+	return value >= g.Threshold
+}
+
+// Settled reports whether the goal needs no more checks for day.
+func (g *Goal) Settled(day time.Time) bool {
+	return g.SettledOn != nil && *g.SettledOn >= day.Format("2006-01-02")
+}
+
+// String describes the goal, as in "at most 360 active minutes between
+// 20:00 and 24:00 on weekdays".
+func (g *Goal) String() string {
+	metric := "keystrokes"
+	if g.Metric == GoalActiveMinutes {
+		metric = "active minutes"
+	}
+	comparison := "at least"
+	if g.Comparison == GoalAtMost {
+		comparison = "at most"
+	}
+
+	result := fmt.Sprintf("%s %d %s", comparison, g.Threshold, metric)
+	if g.FromHour != 0 || g.ToHour != 24 {
+		result += fmt.Sprintf(" between %02d:00 and %02d:00", g.FromHour, g.ToHour)
+	}
+	switch g.Days {
+	case GoalWeekdays:
+		return result + " on weekdays"
+	case GoalWeekends:
+		return result + " on weekends"
+	}
+	return result + " every day"
+}
+
+// CreateGoal adds a goal for a user.
+func (s *Storage) CreateGoal(ctx context.Context, goal *Goal) error {
+	if err := goal.Validate(); err != nil {
+		return err
+	}
+
+	goal.ID = ulid.String()
+	goal.CreatedAt = time.Now().UTC()
+	goal.SettledOn = nil
+
+	query := `
+INSERT INTO
+  pulse_goal (id, user_id, metric, comparison, threshold, days, from_hour, to_hour, created_at)
+VALUES
+  (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := s.db.ExecContext(ctx, query, goal.ID, goal.UserID, goal.Metric, goal.Comparison, goal.Threshold, goal.Days, goal.FromHour, goal.ToHour, goal.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create goal: %w", err)
+	}
+	return nil
+}
+
+const selectGoals = `
+		SELECT id, user_id, metric, comparison, threshold, days, from_hour, to_hour, created_at, date(settled_on) as settled_on
+		FROM pulse_goal`
+
+// ListGoals returns the goals of a user.
+func (s *Storage) ListGoals(ctx context.Context, userID string) ([]Goal, error) {
+	var goals []Goal
+	query := selectGoals + ` WHERE user_id = ? ORDER BY created_at`
+	if err := s.db.SelectContext(ctx, &goals, query, userID); err != nil {
+		return nil, fmt.Errorf("list goals: %w", err)
+	}
+	return goals, nil
+}
+
+// ListAllGoals returns the goals of all users, ordered by user.
+func (s *Storage) ListAllGoals(ctx context.Context) ([]Goal, error) {
+	var goals []Goal
+	query := selectGoals + ` ORDER BY user_id, created_at`
+	if err := s.db.SelectContext(ctx, &goals, query); err != nil {
+		return nil, fmt.Errorf("list all goals: %w", err)
+	}
+	return goals, nil
+}
+
+// DeleteGoal removes a goal of a user.
+func (s *Storage) DeleteGoal(ctx context.Context, userID, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM pulse_goal WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete goal: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("delete goal: %w", err)
+	} else if n == 0 {
+		return fmt.Errorf("%w: %s", ErrGoalNotFound, id)
+	}
+	return nil
+}
+
+// SettleGoal records that a goal needs no more checks up to day.
+func (s *Storage) SettleGoal(ctx context.Context, id string, day time.Time) error {
+	query := `UPDATE pulse_goal SET settled_on = ? WHERE id = ?`
+	if _, err := s.db.ExecContext(ctx, query, day.Format("2006-01-02"), id); err != nil {
+		return fmt.Errorf("settle goal: %w", err)
+	}
+	return nil
+}
+
+// GoalValue returns the activity of a user from start up to but not
+// including end, measured by metric.
+func (s *Storage) GoalValue(ctx context.Context, userID string, metric GoalMetric, start, end time.Time) (int64, error) {
+	column := "COALESCE(SUM(count), 0)"
+	if metric == GoalActiveMinutes {
+		column = "COUNT(DISTINCT stamp)"
+	}
+
+	var value int64
+	query := `SELECT ` + column + ` FROM pulse_minutely WHERE user_id = ? AND stamp >= ? AND stamp < ?`
+	args := []any{userID, start.UTC().Format("2006-01-02 15:04:05"), end.UTC().Format("2006-01-02 15:04:05")}
+	if err := s.db.GetContext(ctx, &value, query, args...); err != nil {
+		return 0, fmt.Errorf("goal value: %w", err)
+	}
+	return value, nil
+}
+
+// GoalCheck is the outcome of checking a goal for a day.
+type GoalCheck struct {
+	Day   time.Time `json:"day"`
+	Value int64     `json:"value"`
+	Met   bool      `json:"met"`
+}
+
+// CheckGoal checks a goal at now, which is taken in the user timezone.
+// At most goals are checked for today so far, at least goals for
+// yesterday, once it is over. It returns nil when there is nothing to
+// check: the day doesn't apply, is settled, or started before the goal
+// was created.
+func (s *Storage) CheckGoal(ctx context.Context, goal *Goal, now time.Time) (*GoalCheck, error) {
+	day := now
+	if goal.Comparison == GoalAtLeast {
+		day = now.AddDate(0, 0, -1)
+	}
+	if !goal.AppliesTo(day) || goal.Settled(day) {
+		return nil, nil
+	}
+
+	start, end := goal.Window(day)
+	if goal.Comparison == GoalAtLeast && start.Before(goal.CreatedAt) {
+		return nil, nil
+	}
+
+	value, err := s.GoalValue(ctx, goal.UserID, goal.Metric, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &GoalCheck{
+		Day:   day,
+		Value: value,
+		Met:   goal.Met(value),
+	}, nil
+}
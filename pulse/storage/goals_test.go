@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoalValidate(t *testing.T) {
+	goal := Goal{Metric: GoalKeystrokes, Comparison: GoalAtLeast, Threshold: 5000, Days: GoalWeekdays, ToHour: 24}
+	require.NoError(t, goal.Validate())
+	assert.Equal(t, "at least 5000 keystrokes on weekdays", goal.String())
+
+	goal = Goal{Metric: GoalActiveMinutes, Comparison: GoalAtMost, Threshold: 360, Days: GoalEveryDay, FromHour: 20, ToHour: 24}
+	require.NoError(t, goal.Validate())
+	assert.Equal(t, "at most 360 active minutes between 20:00 and 24:00 every day", goal.String())
+
+	invalid := []Goal{
+		{Metric: "words", Comparison: GoalAtMost, Threshold: 1, Days: GoalEveryDay, ToHour: 24},
+		{Metric: GoalKeystrokes, Comparison: "exactly", Threshold: 1, Days: GoalEveryDay, ToHour: 24},
+		{Metric: GoalKeystrokes, Comparison: GoalAtMost, Threshold: 0, Days: GoalEveryDay, ToHour: 24},
+		{Metric: GoalKeystrokes, Comparison: GoalAtMost, Threshold: 1, Days: "mondays", ToHour: 24},
+		{Metric: GoalKeystrokes, Comparison: GoalAtMost, Threshold: 1, Days: GoalEveryDay, FromHour: 20, ToHour: 20},
+	}
+	for _, goal := range invalid {
+		assert.Error(t, goal.Validate(), goal)
+	}
+}
+
+func TestCheckGoal(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	// Thursday 2026-03-12, typing 19:58-20:03 and the day before.
+	for _, stamp := range []string{"2026-03-11 10:00:00", "2026-03-12 19:58:00", "2026-03-12 20:01:00", "2026-03-12 20:02:00"} {
+		for _, host := range []string{"laptop", "desktop"} {
+			_, err := s.db.Exec(`INSERT INTO pulse_minutely (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`, "TESTUSER", host, stamp, 50)
+			require.NoError(t, err)
+		}
+	}
+
+	now := time.Date(2026, 3, 12, 21, 0, 0, 0, time.UTC)
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	evening := &Goal{UserID: "TESTUSER", Metric: GoalActiveMinutes, Comparison: GoalAtMost, Threshold: 1, Days: GoalEveryDay, FromHour: 20, ToHour: 24, CreatedAt: created}
+	check, err := s.CheckGoal(ctx, evening, now)
+	require.NoError(t, err)
+	require.NotNil(t, check)
+	assert.Equal(t, int64(2), check.Value)
+	assert.False(t, check.Met)
+
+	evening.Metric, evening.Threshold = GoalKeystrokes, 200
+	check, err = s.CheckGoal(ctx, evening, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), check.Value)
+	assert.True(t, check.Met)
+
+	// At least goals check the day before.
+	target := &Goal{UserID: "TESTUSER", Metric: GoalKeystrokes, Comparison: GoalAtLeast, Threshold: 5000, Days: GoalWeekdays, ToHour: 24, CreatedAt: created}
+	check, err = s.CheckGoal(ctx, target, now)
+	require.NoError(t, err)
+	require.NotNil(t, check)
+	assert.Equal(t, "2026-03-11", check.Day.Format("2006-01-02"))
+	assert.Equal(t, int64(100), check.Value)
+	assert.False(t, check.Met)
+
+	settled := "2026-03-11"
+	target.SettledOn = &settled
+	check, err = s.CheckGoal(ctx, target, now)
+	require.NoError(t, err)
+	assert.Nil(t, check)
+
+	// Goals created during the day are checked from the next day.
+	target.SettledOn = nil
+	target.CreatedAt = time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	check, err = s.CheckGoal(ctx, target, now)
+	require.NoError(t, err)
+	assert.Nil(t, check)
+
+	target.CreatedAt, target.Days = created, GoalWeekends
+	check, err = s.CheckGoal(ctx, target, now)
+	require.NoError(t, err)
+	assert.Nil(t, check)
+}
+
+func TestManageGoals(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	goal := &Goal{UserID: "TESTUSER", Metric: GoalKeystrokes, Comparison: GoalAtLeast, Threshold: 5000, Days: GoalWeekdays, ToHour: 24}
+	require.NoError(t, s.CreateGoal(ctx, goal))
+	assert.NotEmpty(t, goal.ID)
+
+	assert.Error(t, s.CreateGoal(ctx, &Goal{UserID: "TESTUSER"}))
+
+	day := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, s.SettleGoal(ctx, goal.ID, day))
+
+	goals, err := s.ListAllGoals(ctx)
+	require.NoError(t, err)
+	require.Len(t, goals, 1)
+	require.NotNil(t, goals[0].SettledOn)
+	assert.Equal(t, "2026-03-11", *goals[0].SettledOn)
+	assert.True(t, goals[0].Settled(day))
+
+	assert.ErrorIs(t, s.DeleteGoal(ctx, "OTHERUSER", goal.ID), ErrGoalNotFound)
+	require.NoError(t, s.DeleteGoal(ctx, "TESTUSER", goal.ID))
+
+	goals, err = s.ListGoals(ctx, "TESTUSER")
+	require.NoError(t, err)
+	assert.Empty(t, goals)
+}
@@ -0,0 +1,219 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/titpetric/platform"
+)
+
+var (
+	// ErrHostNotFound is returned when a user has no host with the given name.
+	ErrHostNotFound = errors.New("host not found")
+	// ErrHostExists is returned when renaming a host to a name already in use.
+	ErrHostExists = errors.New("host already exists")
+	// ErrInvalidHostname is returned for an empty or overly long hostname.
+	ErrInvalidHostname = errors.New("invalid hostname")
+)
+
+// MaxHostnameLength is the longest accepted hostname.
+const MaxHostnameLength = 255
+
+// hostTables are the tables holding per-host counts, with their primary
+// key columns after user_id and hostname.
+var hostTables = []struct {
+	name string
+	keys string
+}{
+	{"pulse_minutely", "stamp"},
+	{"pulse_hourly", "stamp"},
+	{"pulse_hourly_category", "stamp, category"},
+	{"pulse_daily", "stamp"},
+	{"pulse_daily_category", "stamp, category"},
+	{"pulse_weekly", "stamp"},
+	{"pulse_monthly", "stamp"},
+}
+
+// hostTableNames returns the per-host count tables and pulse_hosts.
+func hostTableNames() []string {
+	names := make([]string, 0, len(hostTables)+1)
+	for _, table := range hostTables {
+		names = append(names, table.name)
+	}
+	return append(names, "pulse_hosts")
+}
+
+// HostSummary holds a host with its activity totals.
+type HostSummary struct {
+	Hostname   string     `db:"hostname" json:"hostname"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	Hidden     bool       `db:"hidden" json:"hidden"`
+	Count      int64      `db:"count" json:"count"`
+	LastActive *time.Time `db:"last_active" json:"last_active"`
+}
+
+// ValidHostname reports whether hostname can be stored.
+func ValidHostname(hostname string) bool {
+	return hostname != "" && hostname == strings.TrimSpace(hostname) && len(hostname) <= MaxHostnameLength
+}
+
+// ListHostSummaries returns all hosts for a user with their daily totals.
+func (s *Storage) ListHostSummaries(ctx context.Context, userID string) ([]HostSummary, error) {
+	var rows []struct {
+		Hostname   string    `db:"hostname"`
+		CreatedAt  time.Time `db:"created_at"`
+		Hidden     bool      `db:"hidden"`
+		Count      int64     `db:"count"`
+		LastActive *string   `db:"last_active"`
+	}
+	query := `
+		SELECT h.hostname, h.created_at, h.hidden, COALESCE(SUM(d.count), 0) as count, MAX(date(d.stamp)) as last_active
+		FROM pulse_hosts h
+		LEFT JOIN pulse_daily d ON d.user_id = h.user_id AND d.hostname = h.hostname
+		WHERE h.user_id = ?
+		GROUP BY h.hostname, h.created_at, h.hidden
+		ORDER BY h.hostname`
+	if err := s.db.SelectContext(ctx, &rows, query, userID); err != nil {
+		return nil, fmt.Errorf("list host summaries: %w", err)
+	}
+
+	result := make([]HostSummary, 0, len(rows))
+	for _, row := range rows {
+		summary := HostSummary{
+			Hostname:  row.Hostname,
+			CreatedAt: row.CreatedAt,
+			Hidden:    row.Hidden,
+			Count:     row.Count,
+		}
+		if row.LastActive != nil {
+			if stamp, err := ParseStamp(*row.LastActive); err == nil {
+				summary.LastActive = &stamp
+			}
+		}
+		result = append(result, summary)
+	}
+	return result, nil
+}
+
+// RenameHost renames a host of a user, with all of its history.
+func (s *Storage) RenameHost(ctx context.Context, userID, from, to string) error {
+	if !ValidHostname(to) {
+		return ErrInvalidHostname
+	}
+	if from == to {
+		return nil
+	}
+
+	return platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := hostExists(ctx, tx, userID, from); err != nil {
+			return err
+		}
+		if err := hostExists(ctx, tx, userID, to); err == nil {
+			return ErrHostExists
+		} else if !errors.Is(err, ErrHostNotFound) {
+			return err
+		}
+
+		// Device keys follow the host, so recorders keep working.
+		for _, table := range append(hostTableNames(), "pulse_device_key") {
+			query := `UPDATE ` + table + ` SET hostname = ? WHERE user_id = ? AND hostname = ?`
+			if _, err := exec(ctx, tx, query, to, userID, from); err != nil {
+				return fmt.Errorf("rename host: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// MergeHosts moves the history of a host into another host of the same
+// user, adding up counts, and removes the merged host.
+func (s *Storage) MergeHosts(ctx context.Context, userID, from, into string) error {
+	if from == into {
+		return fmt.Errorf("merge hosts: can't merge %q into itself", from)
+	}
+
+	return platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := hostExists(ctx, tx, userID, from); err != nil {
+			return err
+		}
+		if err := hostExists(ctx, tx, userID, into); err != nil {
+			return err
+		}
+
+		for _, table := range hostTables {
+			columns := "user_id, hostname, " + table.keys + ", count"
+			if table.name == "pulse_daily" {
+				// Keep the rollup state of daily rows moved without a conflict.
+				columns += ", rolled_up"
+			}
+			selected := strings.Replace(columns, "hostname", "?", 1)
+
+			query := `
+INSERT INTO
+  ` + table.name + ` (` + columns + `)
+SELECT
+  ` + selected + `
+FROM
+  ` + table.name + `
+WHERE
+  user_id = ? AND hostname = ?
+ON
+  CONFLICT(user_id, hostname, ` + table.keys + `)
+DO
+  UPDATE SET count = count + excluded.count`
+			if _, err := exec(ctx, tx, query, into, userID, from); err != nil {
+				return fmt.Errorf("merge hosts: %w", err)
+			}
+		}
+
+		query := `UPDATE pulse_device_key SET hostname = ? WHERE user_id = ? AND hostname = ?`
+		if _, err := exec(ctx, tx, query, into, userID, from); err != nil {
+			return fmt.Errorf("merge hosts: %w", err)
+		}
+
+		return deleteHost(ctx, tx, userID, from)
+	})
+}
+
+// DeleteHost removes a host of a user, with all of its history, and
+// revokes its device keys.
+func (s *Storage) DeleteHost(ctx context.Context, userID, hostname string) error {
+	return platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		if err := hostExists(ctx, tx, userID, hostname); err != nil {
+			return err
+		}
+		return deleteHost(ctx, tx, userID, hostname)
+	})
+}
+
+func deleteHost(ctx context.Context, tx *sqlx.Tx, userID, hostname string) error {
+	for _, table := range hostTableNames() {
+		query := `DELETE FROM ` + table + ` WHERE user_id = ? AND hostname = ?`
+		if _, err := exec(ctx, tx, query, userID, hostname); err != nil {
+			return fmt.Errorf("delete host: %w", err)
+		}
+	}
+
+	query := `UPDATE pulse_device_key SET revoked_at = ? WHERE user_id = ? AND hostname = ? AND revoked_at IS NULL`
+	if _, err := exec(ctx, tx, query, time.Now().UTC(), userID, hostname); err != nil {
+		return fmt.Errorf("delete host: %w", err)
+	}
+	return nil
+}
+
+// hostExists returns ErrHostNotFound when the user has no such host.
+func hostExists(ctx context.Context, tx *sqlx.Tx, userID, hostname string) error {
+	var count int
+	query := tx.Rebind(`SELECT COUNT(*) FROM pulse_hosts WHERE user_id = ? AND hostname = ?`)
+	if err := tx.GetContext(ctx, &count, query, userID, hostname); err != nil {
+		return fmt.Errorf("error in %s: %w", query, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %s", ErrHostNotFound, hostname)
+	}
+	return nil
+}
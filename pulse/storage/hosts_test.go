@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/user"
+	"github.com/titpetric/platform-app/user/model"
+)
+
+func TestManageHosts(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER"})
+
+	now := time.Now().UTC()
+	err := s.PulseBatch(ctx, "", []Entry{
+		{Hostname: "laptop", Stamp: now, Count: 10},
+		{Hostname: "laptop.local", Stamp: now, Count: 5},
+		{Hostname: "old", Stamp: now.Add(-48 * time.Hour), Count: 7},
+	})
+	require.NoError(t, err)
+
+	hostCount := func(table, hostname string) int64 {
+		var count int64
+		require.NoError(t, s.db.Get(&count, `SELECT COALESCE(SUM(count), 0) FROM `+table+` WHERE user_id = ? AND hostname = ?`, "TESTUSER", hostname))
+		return count
+	}
+
+	// Renaming to a name in use is refused, merging adds up counts.
+	assert.ErrorIs(t, s.RenameHost(ctx, "TESTUSER", "laptop.local", "laptop"), ErrHostExists)
+	require.NoError(t, s.MergeHosts(ctx, "TESTUSER", "laptop.local", "laptop"))
+	for _, table := range []string{"pulse_minutely", "pulse_hourly", "pulse_daily"} {
+		assert.Equal(t, int64(15), hostCount(table, "laptop"), table)
+		assert.Equal(t, int64(0), hostCount(table, "laptop.local"), table)
+	}
+
+	require.NoError(t, s.RenameHost(ctx, "TESTUSER", "laptop", "work"))
+	assert.Equal(t, int64(15), hostCount("pulse_hourly", "work"))
+	assert.ErrorIs(t, s.RenameHost(ctx, "TESTUSER", "laptop", "home"), ErrHostNotFound)
+	assert.ErrorIs(t, s.RenameHost(ctx, "TESTUSER", "work", " "), ErrInvalidHostname)
+
+	require.NoError(t, s.DeleteHost(ctx, "TESTUSER", "old"))
+	assert.Equal(t, int64(0), hostCount("pulse_daily", "old"))
+	assert.ErrorIs(t, s.DeleteHost(ctx, "TESTUSER", "old"), ErrHostNotFound)
+
+	hosts, err := s.ListHostSummaries(ctx, "TESTUSER")
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	assert.Equal(t, "work", hosts[0].Hostname)
+	assert.Equal(t, int64(15), hosts[0].Count)
+	assert.False(t, hosts[0].Hidden)
+	assert.NotNil(t, hosts[0].LastActive)
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"sync"
+	"time"
+)
+
+// HubBuffer is the number of events a subscriber can fall behind before
+// further events are dropped for it.
+const HubBuffer = 64
+
+// Event is an ingested entry, as published to live subscribers.
+type Event struct {
+	UserID   string    `json:"-"`
+	Hostname string    `json:"hostname"`
+	Stamp    time.Time `json:"stamp"`
+	Count    int64     `json:"count"`
+}
+
+// Hub passes ingest events to the subscribers of a user. It is in
+// process, so subscribers only see events ingested by the same server.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan Event]struct{}
+}
+
+// NewHub creates an empty hub.
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[string]map[chan Event]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving the events of a user, and a
+// function that unsubscribes and closes the channel. Events are dropped
+// for a subscriber that doesn't keep up, rather than blocking ingest.
+func (h *Hub) Subscribe(userID string) (<-chan Event, func()) {
+	events := make(chan Event, HubBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan Event]struct{})
+	}
+	h.subscribers[userID][events] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			delete(h.subscribers[userID], events)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+			close(events)
+		})
+	}
+}
+
+// Subscribers returns the number of subscribers of a user.
+func (h *Hub) Subscribers(userID string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subscribers[userID])
+}
+
+// Publish sends events to the subscribers of their user.
+func (h *Hub) Publish(events ...Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, event := range events {
+		for subscriber := range h.subscribers[event.UserID] {
+			select {
+			case subscriber <- event:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/user"
+	"github.com/titpetric/platform-app/user/model"
+)
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+
+	events, unsubscribe := hub.Subscribe("TESTUSER")
+	other, unsubscribeOther := hub.Subscribe("OTHERUSER")
+	defer unsubscribeOther()
+	assert.Equal(t, 1, hub.Subscribers("TESTUSER"))
+
+	hub.Publish(Event{UserID: "TESTUSER", Hostname: "laptop", Count: 10})
+	assert.Equal(t, Event{UserID: "TESTUSER", Hostname: "laptop", Count: 10}, <-events)
+	assert.Empty(t, other)
+
+	// Slow subscribers lose events instead of blocking.
+	for i := 0; i < HubBuffer+10; i++ {
+		hub.Publish(Event{UserID: "TESTUSER", Hostname: "laptop", Count: 1})
+	}
+	assert.Len(t, events, HubBuffer)
+
+	unsubscribe()
+	unsubscribe()
+	assert.Equal(t, 0, hub.Subscribers("TESTUSER"))
+	hub.Publish(Event{UserID: "TESTUSER", Hostname: "laptop", Count: 1})
+}
+
+func TestPulseBatchPublishes(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER"})
+
+	events, unsubscribe := s.Subscribe("TESTUSER")
+	defer unsubscribe()
+
+	stamp := time.Now().UTC().Add(-time.Minute)
+	entries := []Entry{
+		{Hostname: "laptop", Stamp: stamp, Count: 10},
+		{Hostname: "desktop", Stamp: stamp, Count: 20},
+	}
+
+	batchID := "01HZX3K8M6Q0W6R4T2Y9B1C5D7"
+	require.NoError(t, s.PulseBatch(ctx, batchID, entries))
+	require.Len(t, events, 2)
+	assert.Equal(t, Event{UserID: "TESTUSER", Hostname: "laptop", Stamp: stamp, Count: 10}, <-events)
+	assert.Equal(t, "desktop", (<-events).Hostname)
+
+	// Replays and failed batches publish nothing.
+	require.NoError(t, s.PulseBatch(ctx, batchID, entries))
+	assert.Error(t, s.PulseBatch(ctx, "", []Entry{{Hostname: "laptop", Count: -1}}))
+	assert.Empty(t, events)
+}
+
+func TestGetLastActive(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	last, err := s.GetLastActive(ctx, "TESTUSER")
+	require.NoError(t, err)
+	assert.Nil(t, last)
+
+	for _, row := range []struct {
+		hostname string
+		stamp    string
+	}{
+		{"laptop", "2026-03-12 10:00:00"},
+		{"desktop", "2026-03-12 10:05:00"},
+	} {
+		_, err := s.db.Exec(`INSERT INTO pulse_minutely (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`, "TESTUSER", row.hostname, row.stamp, 10)
+		require.NoError(t, err)
+	}
+
+	last, err = s.GetLastActive(ctx, "TESTUSER")
+	require.NoError(t, err)
+	require.NotNil(t, last)
+	assert.Equal(t, "desktop", last.Hostname)
+
+	last, err = s.GetLastActive(ctx, "TESTUSER", "desktop")
+	require.NoError(t, err)
+	require.NotNil(t, last)
+	assert.Equal(t, "laptop", last.Hostname)
+}
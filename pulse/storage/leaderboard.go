@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Period selects the time span a leaderboard is ranked over.
+type Period string
+
+// Leaderboard periods.
+const (
+	PeriodToday Period = "today"
+	PeriodWeek  Period = "week"
+	PeriodMonth Period = "month"
+	PeriodAll   Period = "all"
+)
+
+// Periods returns all leaderboard periods.
+func Periods() []Period {
+	return []Period{PeriodToday, PeriodWeek, PeriodMonth, PeriodAll}
+}
+
+// Valid reports whether p is a known period.
+func (p Period) Valid() bool {
+	switch p {
+	case PeriodToday, PeriodWeek, PeriodMonth, PeriodAll:
+		return true
+	}
+	return false
+}
+
+// Bounds returns the first day of the period containing now, and the
+// first day of the period before it. Weeks start on Monday. The all-time
+// period compares with the standings before today, so its start is today
+// and its previous start is zero.
+func (p Period) Bounds(now time.Time) (start, previous time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch p {
+	case PeriodWeek:
+		start = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, -7)
+	case PeriodMonth:
+		start = today.AddDate(0, 0, 1-today.Day())
+		return start, start.AddDate(0, -1, 0)
+	case PeriodAll:
+		return today, time.Time{}
+	}
+	return today, today.AddDate(0, 0, -1)
+}
+
+// Standing is a user's place on a leaderboard.
+type Standing struct {
+	UserID string `json:"user_id"`
+	Count  int64  `json:"count"`
+	Rank   int    `json:"rank"`
+	// PreviousRank is the rank in the previous period, zero when the
+	// user had no keystrokes then.
+	PreviousRank int `json:"previous_rank"`
+	// Change is how many places the user moved up since the previous
+	// period, negative when they moved down.
+	Change int `json:"change"`
+}
+
+// Leaderboard ranks the given users by keystrokes in the period containing
+// now, with their rank change since the previous period. Days are taken
+// in the location of now. Hidden hosts are not counted. Users without
+// keystrokes are ranked last.
+func (s *Storage) Leaderboard(ctx context.Context, userIDs []string, period Period, now time.Time) ([]Standing, error) {
+	if !period.Valid() {
+		return nil, fmt.Errorf("invalid period: %q", period)
+	}
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	// The current period runs up to now, as stamps are never in the
+	// future. The all-time period has no start, and compares with the
+	// totals before today.
+	start, previous := period.Bounds(now)
+	if period == PeriodAll {
+		start, previous = time.Time{}, start
+	}
+
+	current, err := s.userTotals(ctx, userIDs, start, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	var before map[string]int64
+	if period == PeriodAll {
+		before, err = s.userTotals(ctx, userIDs, time.Time{}, previous)
+	} else {
+		before, err = s.userTotals(ctx, userIDs, previous, start)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return Rank(userIDs, current, before), nil
+}
+
+// Rank orders users by their current counts, highest first, and compares
+// with their rank by previous counts. Users with equal counts share a
+// rank.
+func Rank(userIDs []string, current, previous map[string]int64) []Standing {
+	standings := make([]Standing, 0, len(userIDs))
+	for _, userID := range userIDs {
+		standings = append(standings, Standing{UserID: userID, Count: current[userID]})
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Count > standings[j].Count
+	})
+
+	previousRanks := ranks(userIDs, previous)
+	for i := range standings {
+		standing := &standings[i]
+		standing.Rank = i + 1
+		if i > 0 && standings[i-1].Count == standing.Count {
+			standing.Rank = standings[i-1].Rank
+		}
+		if rank, ok := previousRanks[standing.UserID]; ok {
+			standing.PreviousRank = rank
+			standing.Change = rank - standing.Rank
+		}
+	}
+	return standings
+}
+
+// ranks returns the competition rank of users with a positive count.
+func ranks(userIDs []string, counts map[string]int64) map[string]int {
+	var ranked []string
+	for _, userID := range userIDs {
+		if counts[userID] > 0 {
+			ranked = append(ranked, userID)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return counts[ranked[i]] > counts[ranked[j]]
+	})
+
+	result := make(map[string]int, len(ranked))
+	for i, userID := range ranked {
+		result[userID] = i + 1
+		if i > 0 && counts[ranked[i-1]] == counts[userID] {
+			result[userID] = result[ranked[i-1]]
+		}
+	}
+	return result
+}
+
+// userTotals returns the keystrokes per user for days from start up to
+// but not including end. A zero start or end leaves that side open. With
+// an open start, the monthly rollups are added so totals include rows
+// that retention already pruned.
+func (s *Storage) userTotals(ctx context.Context, userIDs []string, start, end time.Time) (map[string]int64, error) {
+	users := "?" + strings.Repeat(", ?", len(userIDs)-1)
+	args := make([]any, 0, len(userIDs)+2)
+	for _, userID := range userIDs {
+		args = append(args, userID)
+	}
+
+	cond := "1=1"
+	if !start.IsZero() {
+		cond += " AND d.stamp >= ?"
+		args = append(args, start.Format("2006-01-02"))
+	}
+	if !end.IsZero() {
+		cond += " AND d.stamp < ?"
+		args = append(args, end.Format("2006-01-02"))
+	}
+
+	// Rolled up daily rows are counted from the monthly rollup instead.
+	table := "pulse_daily"
+	if start.IsZero() {
+		table = `(
+			SELECT user_id, hostname, stamp, count FROM pulse_daily WHERE rolled_up = 0
+			UNION ALL
+			SELECT user_id, hostname, stamp, count FROM pulse_monthly
+		)`
+	}
+
+	var rows []UserCount
+	query := `
+		SELECT d.user_id, SUM(d.count) as count
+		FROM ` + table + ` d
+		JOIN pulse_hosts h ON h.user_id = d.user_id AND h.hostname = d.hostname
+		WHERE d.user_id IN (` + users + `) AND h.hidden = 0 AND ` + cond + `
+		GROUP BY d.user_id`
+	if err := s.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("user totals: %w", err)
+	}
+
+	result := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		result[row.UserID] = row.Count
+	}
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	// Thursday
+	now := time.Date(2026, 3, 12, 15, 0, 0, 0, time.UTC)
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		require.NoError(t, err)
+		return d
+	}
+
+	tests := []struct {
+		period          Period
+		start, previous time.Time
+	}{
+		{PeriodToday, day("2026-03-12"), day("2026-03-11")},
+		{PeriodWeek, day("2026-03-09"), day("2026-03-02")},
+		{PeriodMonth, day("2026-03-01"), day("2026-02-01")},
+		{PeriodAll, day("2026-03-12"), time.Time{}},
+	}
+	for _, tt := range tests {
+		start, previous := tt.period.Bounds(now)
+		assert.Equal(t, tt.start, start, tt.period)
+		assert.Equal(t, tt.previous, previous, tt.period)
+	}
+}
+
+func TestRank(t *testing.T) {
+	users := []string{"a", "b", "c", "d"}
+	current := map[string]int64{"a": 10, "b": 30, "c": 10}
+	previous := map[string]int64{"a": 50, "b": 20}
+
+	standings := Rank(users, current, previous)
+	require.Len(t, standings, 4)
+
+	assert.Equal(t, Standing{UserID: "b", Count: 30, Rank: 1, PreviousRank: 2, Change: 1}, standings[0])
+	assert.Equal(t, Standing{UserID: "a", Count: 10, Rank: 2, PreviousRank: 1, Change: -1}, standings[1])
+	assert.Equal(t, Standing{UserID: "c", Count: 10, Rank: 2}, standings[2])
+	assert.Equal(t, Standing{UserID: "d", Count: 0, Rank: 4}, standings[3])
+}
+
+func TestLeaderboard(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+
+	type row = struct {
+		hostname string
+		stamp    string
+		count    int64
+	}
+	seedDaily(t, s, "USER1", []row{{"laptop", today, 10}, {"laptop", yesterday, 100}})
+	seedDaily(t, s, "USER2", []row{{"desktop", today, 20}, {"desktop", yesterday, 5}})
+
+	standings, err := s.Leaderboard(ctx, []string{"USER1", "USER2"}, PeriodToday, now)
+	require.NoError(t, err)
+	require.Len(t, standings, 2)
+	assert.Equal(t, "USER2", standings[0].UserID)
+	assert.Equal(t, int64(20), standings[0].Count)
+	assert.Equal(t, 1, standings[0].Change)
+	assert.Equal(t, -1, standings[1].Change)
+
+	// All-time totals include monthly rollups of pruned daily rows.
+	_, err = s.db.Exec(`INSERT INTO pulse_monthly (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`, "USER2", "desktop", "2020-01-01", 1000)
+	require.NoError(t, err)
+
+	standings, err = s.Leaderboard(ctx, []string{"USER1", "USER2"}, PeriodAll, now)
+	require.NoError(t, err)
+	require.Len(t, standings, 2)
+	assert.Equal(t, "USER2", standings[0].UserID)
+	assert.Equal(t, int64(1025), standings[0].Count)
+	assert.Equal(t, int64(110), standings[1].Count)
+
+	// Hidden hosts aren't counted.
+	require.NoError(t, s.SetHiddenHosts(ctx, "USER2", []string{"desktop"}))
+	standings, err = s.Leaderboard(ctx, []string{"USER1", "USER2"}, PeriodAll, now)
+	require.NoError(t, err)
+	assert.Equal(t, "USER1", standings[0].UserID)
+	assert.Equal(t, int64(0), standings[1].Count)
+
+	_, err = s.Leaderboard(ctx, []string{"USER1"}, Period("year"), now)
+	assert.Error(t, err)
+}
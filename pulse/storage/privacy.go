@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/pulse/model"
+)
+
+// Visibility controls who can see a user's pulse profile.
+type Visibility string
+
+// Profile visibility values.
+const (
+	// VisibilityPublic profiles are listed on the index and readable by anyone.
+	VisibilityPublic Visibility = "public"
+	// VisibilityUnlisted profiles are readable by link, but not listed.
+	VisibilityUnlisted Visibility = "unlisted"
+	// VisibilityPrivate profiles are only readable by their owner.
+	VisibilityPrivate Visibility = "private"
+)
+
+// Valid reports whether v is a known visibility value.
+func (v Visibility) Valid() bool {
+	switch v {
+	case VisibilityPublic, VisibilityUnlisted, VisibilityPrivate:
+		return true
+	}
+	return false
+}
+
+// GetVisibility returns the profile visibility for a user. Users without
+// privacy settings are public.
+func (s *Storage) GetVisibility(ctx context.Context, userID string) (Visibility, error) {
+	var visibility []Visibility
+	query := `SELECT visibility FROM pulse_profile WHERE user_id = ?`
+	if err := s.db.SelectContext(ctx, &visibility, query, userID); err != nil {
+		return "", fmt.Errorf("get visibility: %w", err)
+	}
+	if len(visibility) == 0 {
+		return VisibilityPublic, nil
+	}
+	return visibility[0], nil
+}
+
+// ListVisibility returns the profile visibility of users that changed it
+// from the default, keyed by user ID.
+func (s *Storage) ListVisibility(ctx context.Context) (map[string]Visibility, error) {
+	var rows []model.PulseProfile
+	query := `SELECT user_id, visibility FROM pulse_profile`
+	if err := s.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("list visibility: %w", err)
+	}
+
+	result := make(map[string]Visibility, len(rows))
+	for _, row := range rows {
+		result[row.UserID] = Visibility(row.Visibility)
+	}
+	return result, nil
+}
+
+// SetVisibility sets the profile visibility for a user.
+func (s *Storage) SetVisibility(ctx context.Context, userID string, visibility Visibility) error {
+	if !visibility.Valid() {
+		return fmt.Errorf("invalid visibility: %q", visibility)
+	}
+
+	query := `
+INSERT INTO
+  pulse_profile (user_id, visibility, updated_at)
+VALUES
+  (?, ?, ?)
+ON
+  CONFLICT(user_id)
+DO
+  UPDATE SET visibility = excluded.visibility, updated_at = excluded.updated_at`
+
+	if _, err := s.db.ExecContext(ctx, s.db.Rebind(query), userID, visibility, time.Now()); err != nil {
+		return fmt.Errorf("set visibility: %w", err)
+	}
+	return nil
+}
+
+// ListHosts returns all hosts for a user, including hidden hosts.
+func (s *Storage) ListHosts(ctx context.Context, userID string) ([]model.PulseHosts, error) {
+	var hosts []model.PulseHosts
+	query := `SELECT * FROM pulse_hosts WHERE user_id = ? ORDER BY hostname`
+	if err := s.db.SelectContext(ctx, &hosts, query, userID); err != nil {
+		return nil, fmt.Errorf("list hosts: %w", err)
+	}
+	return hosts, nil
+}
+
+// GetHiddenHosts returns the hostnames a user has hidden from others.
+func (s *Storage) GetHiddenHosts(ctx context.Context, userID string) ([]string, error) {
+	var hosts []string
+	query := `SELECT hostname FROM pulse_hosts WHERE user_id = ? AND hidden = 1 ORDER BY hostname`
+	if err := s.db.SelectContext(ctx, &hosts, query, userID); err != nil {
+		return nil, fmt.Errorf("get hidden hosts: %w", err)
+	}
+	return hosts, nil
+}
+
+// SetHiddenHosts hides the given hosts of a user from others, and shows
+// all other hosts.
+func (s *Storage) SetHiddenHosts(ctx context.Context, userID string, hidden []string) error {
+	return platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		query := tx.Rebind(`UPDATE pulse_hosts SET hidden = 0 WHERE user_id = ?`)
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			return fmt.Errorf("set hidden hosts: %w", err)
+		}
+
+		query = tx.Rebind(`UPDATE pulse_hosts SET hidden = 1 WHERE user_id = ? AND hostname = ?`)
+		for _, hostname := range hidden {
+			if _, err := tx.ExecContext(ctx, query, userID, hostname); err != nil {
+				return fmt.Errorf("set hidden hosts: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// SetHostHidden hides or shows a single host of a user.
+func (s *Storage) SetHostHidden(ctx context.Context, userID, hostname string, hidden bool) error {
+	value := 0
+	if hidden {
+		value = 1
+	}
+
+	query := s.db.Rebind(`UPDATE pulse_hosts SET hidden = ? WHERE user_id = ? AND hostname = ?`)
+	res, err := s.db.ExecContext(ctx, query, value, userID, hostname)
+	if err != nil {
+		return fmt.Errorf("set host hidden: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", ErrHostNotFound, hostname)
+	}
+	return nil
+}
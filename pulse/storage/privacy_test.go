@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/user"
+	"github.com/titpetric/platform-app/user/model"
+)
+
+func TestVisibility(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	v, err := s.GetVisibility(ctx, "TESTUSER")
+	require.NoError(t, err)
+	assert.Equal(t, VisibilityPublic, v)
+
+	require.NoError(t, s.SetVisibility(ctx, "TESTUSER", VisibilityPrivate))
+	require.NoError(t, s.SetVisibility(ctx, "OTHER", VisibilityUnlisted))
+	require.NoError(t, s.SetVisibility(ctx, "OTHER", VisibilityPublic))
+	assert.Error(t, s.SetVisibility(ctx, "TESTUSER", "secret"))
+
+	v, err = s.GetVisibility(ctx, "TESTUSER")
+	require.NoError(t, err)
+	assert.Equal(t, VisibilityPrivate, v)
+
+	all, err := s.ListVisibility(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Visibility{"TESTUSER": VisibilityPrivate, "OTHER": VisibilityPublic}, all)
+}
+
+func TestHiddenHosts(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER"})
+
+	now := time.Now().UTC()
+	require.NoError(t, s.PulseBatch(ctx, "", []Entry{
+		{Hostname: "lab", Stamp: now, Count: 10},
+		{Hostname: "work", Stamp: now, Count: 5},
+	}))
+
+	require.NoError(t, s.SetHiddenHosts(ctx, "TESTUSER", []string{"work"}))
+
+	hidden, err := s.GetHiddenHosts(ctx, "TESTUSER")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work"}, hidden)
+
+	rng := LastDays(1, time.UTC)
+	rng.Exclude = hidden
+
+	hourly, err := s.GetUserHourly(ctx, "TESTUSER", time.UTC, rng)
+	require.NoError(t, err)
+	require.Len(t, hourly, 1)
+	assert.Equal(t, int64(10), hourly[0].Count)
+	daily, err := s.GetUserDaily(ctx, "TESTUSER", rng)
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+	assert.Equal(t, "lab", daily[0].Hostname)
+
+	standings, err := s.Leaderboard(ctx, []string{"TESTUSER"}, PeriodToday, now)
+	require.NoError(t, err)
+	require.Len(t, standings, 1)
+	assert.Equal(t, int64(10), standings[0].Count)
+
+	require.NoError(t, s.SetHiddenHosts(ctx, "TESTUSER", nil))
+	hidden, err = s.GetHiddenHosts(ctx, "TESTUSER")
+	require.NoError(t, err)
+	assert.Empty(t, hidden)
+}
@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/titpetric/platform"
+)
+
+// RollupDelay is how old a daily row must be before it is added to the
+// weekly and monthly rollups. Ingest may still change younger days, as
+// stamps are accepted up to MaxBackfill in the past, in any timezone.
+const RollupDelay = MaxBackfill + 48*time.Hour
+
+// RetentionPolicy sets how many days of each table are kept. Zero keeps
+// rows forever.
+type RetentionPolicy struct {
+	MinutelyDays int `json:"minutely_days"`
+	HourlyDays   int `json:"hourly_days"`
+	DailyDays    int `json:"daily_days"`
+	WeeklyDays   int `json:"weekly_days"`
+	MonthlyDays  int `json:"monthly_days"`
+}
+
+// RetentionReport holds the number of rows rolled up and pruned.
+type RetentionReport struct {
+	// Backfilled counts daily rows created from hourly rows.
+	Backfilled int64 `json:"backfilled"`
+	// RolledUp counts daily rows added to the weekly and monthly rollups.
+	RolledUp int64 `json:"rolled_up"`
+
+	// Pruned rows per table. Hourly includes the hourly key category
+	// rows, Categories counts the daily ones.
+	Minutely   int64 `json:"minutely"`
+	Hourly     int64 `json:"hourly"`
+	Daily      int64 `json:"daily"`
+	Categories int64 `json:"categories"`
+	Weekly     int64 `json:"weekly"`
+	Monthly    int64 `json:"monthly"`
+}
+
+// Pruned returns the total number of deleted rows.
+func (r *RetentionReport) Pruned() int64 {
+	return r.Minutely + r.Hourly + r.Daily + r.Categories + r.Weekly + r.Monthly
+}
+
+const backfillPulseDaily = `
+INSERT INTO
+  pulse_daily (user_id, hostname, stamp, count)
+VALUES
+  (?, ?, ?, ?)
+ON
+  CONFLICT(user_id, hostname, stamp)
+DO NOTHING`
+
+const updatePulseWeekly = `
+INSERT INTO
+  pulse_weekly (user_id, hostname, stamp, count)
+VALUES
+  (?, ?, ?, ?)
+ON
+  CONFLICT(user_id, hostname, stamp)
+DO
+  UPDATE SET count = count + excluded.count`
+
+const updatePulseMonthly = `
+INSERT INTO
+  pulse_monthly (user_id, hostname, stamp, count)
+VALUES
+  (?, ?, ?, ?)
+ON
+  CONFLICT(user_id, hostname, stamp)
+DO
+  UPDATE SET count = count + excluded.count`
+
+// Prune rolls up and deletes rows that are older than the policy allows.
+// Hourly rows are rolled into missing daily rows before they are deleted,
+// and daily rows are only deleted once they are in the weekly and monthly
+// rollups, so totals are never lost.
+func (s *Storage) Prune(ctx context.Context, policy RetentionPolicy, now time.Time) (*RetentionReport, error) {
+	report := &RetentionReport{}
+	err := platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		now := now.UTC()
+
+		if policy.HourlyDays > 0 {
+			cutoff := now.AddDate(0, 0, -policy.HourlyDays).Format("2006-01-02 15:04:05")
+			n, err := backfill(ctx, tx, cutoff)
+			if err != nil {
+				return err
+			}
+			report.Backfilled = n
+		}
+
+		n, err := rollup(ctx, tx, now.Add(-RollupDelay).Format("2006-01-02"))
+		if err != nil {
+			return err
+		}
+		report.RolledUp = n
+
+		prune := []struct {
+			days   int
+			query  string
+			layout string
+			result *int64
+		}{
+			{policy.MinutelyDays, `DELETE FROM pulse_minutely WHERE stamp < ?`, "2006-01-02 15:04:05", &report.Minutely},
+			{policy.HourlyDays, `DELETE FROM pulse_hourly WHERE stamp < ?`, "2006-01-02 15:04:05", &report.Hourly},
+			{policy.HourlyDays, `DELETE FROM pulse_hourly_category WHERE stamp < ?`, "2006-01-02 15:04:05", &report.Hourly},
+			{policy.DailyDays, `DELETE FROM pulse_daily WHERE stamp < ? AND rolled_up = 1`, "2006-01-02", &report.Daily},
+			{policy.DailyDays, `DELETE FROM pulse_daily_category WHERE stamp < ?`, "2006-01-02", &report.Categories},
+			{policy.WeeklyDays, `DELETE FROM pulse_weekly WHERE stamp < ?`, "2006-01-02", &report.Weekly},
+			{policy.MonthlyDays, `DELETE FROM pulse_monthly WHERE stamp < ?`, "2006-01-02", &report.Monthly},
+		}
+		for _, p := range prune {
+			if p.days <= 0 {
+				continue
+			}
+			n, err := exec(ctx, tx, p.query, now.AddDate(0, 0, -p.days).Format(p.layout))
+			if err != nil {
+				return err
+			}
+			*p.result += n
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("prune: %w", err)
+	}
+	return report, nil
+}
+
+// backfill creates missing daily rows from hourly rows older than
+// cutoff, and returns how many were created. Days are taken in the
+// timezone the user's daily rows were built in, as on ingest.
+func backfill(ctx context.Context, tx *sqlx.Tx, cutoff string) (int64, error) {
+	var hourly []struct {
+		UserID string `db:"user_id"`
+		DailyHostCount
+	}
+	query := tx.Rebind(`SELECT user_id, hostname, stamp, count FROM pulse_hourly WHERE stamp < ?`)
+	if err := tx.SelectContext(ctx, &hourly, query, cutoff); err != nil {
+		return 0, fmt.Errorf("error in %s: %w", query, err)
+	}
+	if len(hourly) == 0 {
+		return 0, nil
+	}
+
+	var profiles []struct {
+		UserID   string `db:"user_id"`
+		Timezone string `db:"timezone"`
+	}
+	query = `SELECT user_id, timezone FROM pulse_profile WHERE timezone <> ''`
+	if err := tx.SelectContext(ctx, &profiles, query); err != nil {
+		return 0, fmt.Errorf("error in %s: %w", query, err)
+	}
+	locations := make(map[string]*time.Location, len(profiles))
+	for _, profile := range profiles {
+		if loc, err := time.LoadLocation(profile.Timezone); err == nil {
+			locations[profile.UserID] = loc
+		}
+	}
+
+	type dayKey struct {
+		userID   string
+		hostname string
+		stamp    string
+	}
+	counts := make(map[dayKey]int64)
+	for _, h := range hourly {
+		stamp, err := ParseStamp(h.Stamp)
+		if err != nil {
+			return 0, err
+		}
+		loc, ok := locations[h.UserID]
+		if !ok {
+			loc = time.UTC
+		}
+		counts[dayKey{h.UserID, h.Hostname, stamp.In(loc).Format("2006-01-02")}] += h.Count
+	}
+
+	var created int64
+	for key, count := range counts {
+		n, err := exec(ctx, tx, backfillPulseDaily, key.userID, key.hostname, key.stamp, count)
+		if err != nil {
+			return 0, err
+		}
+		created += n
+	}
+	return created, nil
+}
+
+// rollup adds daily rows older than cutoff to the weekly and monthly
+// rollups, and marks them as rolled up.
+func rollup(ctx context.Context, tx *sqlx.Tx, cutoff string) (int64, error) {
+	query := tx.Rebind(`
+		SELECT user_id, hostname, date(stamp) as stamp, count
+		FROM pulse_daily
+		WHERE rolled_up = 0 AND stamp < ?`)
+
+	var rows []struct {
+		UserID string `db:"user_id"`
+		DailyHostCount
+	}
+	if err := tx.SelectContext(ctx, &rows, query, cutoff); err != nil {
+		return 0, fmt.Errorf("error in %s: %w", query, err)
+	}
+
+	for _, row := range rows {
+		if err := addRollup(ctx, tx, row.UserID, row.Hostname, row.Stamp, row.Count); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := exec(ctx, tx, `UPDATE pulse_daily SET rolled_up = 1 WHERE rolled_up = 0 AND stamp < ?`, cutoff); err != nil {
+		return 0, err
+	}
+	return int64(len(rows)), nil
+}
+
+// addRollup adds count keystrokes on a day, given as YYYY-MM-DD, to the
+// weekly and monthly rollups. A negative count subtracts them.
+func addRollup(ctx context.Context, tx *sqlx.Tx, userID, hostname, stamp string, count int64) error {
+	day, err := time.Parse("2006-01-02", stamp)
+	if err != nil {
+		return fmt.Errorf("rollup: %w", err)
+	}
+
+	// Weeks start on Monday.
+	weekday := (int(day.Weekday()) + 6) % 7
+	week := day.AddDate(0, 0, -weekday).Format("2006-01-02")
+	month := day.AddDate(0, 0, 1-day.Day()).Format("2006-01-02")
+
+	if _, err := exec(ctx, tx, updatePulseWeekly, userID, hostname, week, count); err != nil {
+		return err
+	}
+	if _, err := exec(ctx, tx, updatePulseMonthly, userID, hostname, month, count); err != nil {
+		return err
+	}
+	return nil
+}
+
+// exec runs a statement and returns the number of affected rows.
+func exec(ctx context.Context, tx *sqlx.Tx, query string, args ...any) (int64, error) {
+	query = tx.Rebind(query)
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("error in %s: %w", query, err)
+	}
+	return res.RowsAffected()
+}
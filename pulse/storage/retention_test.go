@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrune(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	// Daily rows in two weeks and two months, one recent day.
+	seedDaily(t, s, "TESTUSER", []struct {
+		hostname string
+		stamp    string
+		count    int64
+	}{
+		{"lab", "2025-04-29", 10}, // Tuesday
+		{"lab", "2025-04-30", 20}, // Wednesday
+		{"lab", "2025-05-01", 30}, // Thursday, next month
+		{"lab", "2025-05-06", 40}, // Tuesday, next week
+		{"lab", "2025-06-29", 50}, // too recent to roll up
+	})
+
+	for _, row := range []struct {
+		query string
+		args  []any
+	}{
+		{`INSERT INTO pulse_hourly (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`, []any{"TESTUSER", "lab", "2025-04-29 10:00:00", 10}},
+		{`INSERT INTO pulse_hourly (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`, []any{"TESTUSER", "lab", "2025-04-20 10:00:00", 7}},
+		{`INSERT INTO pulse_hourly (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`, []any{"TESTUSER", "lab", "2025-06-30 10:00:00", 5}},
+		{`INSERT INTO pulse_minutely (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`, []any{"TESTUSER", "lab", "2025-05-01 10:00:00", 3}},
+		{`INSERT INTO pulse_minutely (user_id, hostname, stamp, count) VALUES (?, ?, ?, ?)`, []any{"TESTUSER", "lab", "2025-06-30 10:00:00", 3}},
+	} {
+		_, err := s.db.Exec(row.query, row.args...)
+		require.NoError(t, err)
+	}
+
+	policy := RetentionPolicy{
+		MinutelyDays: 30,
+		HourlyDays:   30,
+		DailyDays:    30,
+	}
+
+	report, err := s.Prune(ctx, policy, now)
+	require.NoError(t, err)
+
+	// The hourly row from 2025-04-20 had no daily row.
+	assert.Equal(t, int64(1), report.Backfilled)
+	assert.Equal(t, int64(5), report.RolledUp)
+	assert.Equal(t, int64(1), report.Minutely)
+	assert.Equal(t, int64(2), report.Hourly)
+	assert.Equal(t, int64(5), report.Daily)
+	assert.Equal(t, int64(8), report.Pruned())
+
+	var weekly []DailyHostCount
+	require.NoError(t, s.db.Select(&weekly, `SELECT hostname, date(stamp) as stamp, count FROM pulse_weekly ORDER BY stamp`))
+	assert.Equal(t, []DailyHostCount{
+		{Hostname: "lab", Stamp: "2025-04-14", Count: 7},
+		{Hostname: "lab", Stamp: "2025-04-28", Count: 60},
+		{Hostname: "lab", Stamp: "2025-05-05", Count: 40},
+	}, weekly)
+
+	var monthly []DailyHostCount
+	require.NoError(t, s.db.Select(&monthly, `SELECT hostname, date(stamp) as stamp, count FROM pulse_monthly ORDER BY stamp`))
+	assert.Equal(t, []DailyHostCount{
+		{Hostname: "lab", Stamp: "2025-04-01", Count: 37},
+		{Hostname: "lab", Stamp: "2025-05-01", Count: 70},
+	}, monthly)
+
+	// A second run doesn't roll up the same rows again.
+	report, err = s.Prune(ctx, policy, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), report.RolledUp)
+	assert.Equal(t, int64(0), report.Pruned())
+
+	var daily int64
+	require.NoError(t, s.db.Get(&daily, `SELECT SUM(count) FROM pulse_daily`))
+	assert.Equal(t, int64(50), daily)
+}
+
+func TestPruneBackfillTimezone(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	// 20:00 UTC is the next morning in Tokyo.
+	for _, query := range []string{
+		`INSERT INTO pulse_profile (user_id, timezone, updated_at) VALUES ('TOKYO', 'Asia/Tokyo', CURRENT_TIMESTAMP)`,
+		`INSERT INTO pulse_hourly (user_id, hostname, stamp, count) VALUES ('TOKYO', 'lab', '2025-04-20 20:00:00', 7)`,
+		`INSERT INTO pulse_hourly (user_id, hostname, stamp, count) VALUES ('UTCUSER', 'lab', '2025-04-20 20:00:00', 3)`,
+	} {
+		_, err := s.db.Exec(query)
+		require.NoError(t, err)
+	}
+
+	report, err := s.Prune(ctx, RetentionPolicy{HourlyDays: 30}, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.Backfilled)
+
+	var daily []struct {
+		UserID string `db:"user_id"`
+		DailyHostCount
+	}
+	require.NoError(t, s.db.Select(&daily, `SELECT user_id, hostname, date(stamp) as stamp, count FROM pulse_daily ORDER BY user_id`))
+	require.Len(t, daily, 2)
+	assert.Equal(t, "TOKYO", daily[0].UserID)
+	assert.Equal(t, DailyHostCount{Hostname: "lab", Stamp: "2025-04-21", Count: 7}, daily[0].DailyHostCount)
+	assert.Equal(t, "UTCUSER", daily[1].UserID)
+	assert.Equal(t, DailyHostCount{Hostname: "lab", Stamp: "2025-04-20", Count: 3}, daily[1].DailyHostCount)
+}
@@ -6,33 +6,161 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/titpetric/platform"
 
+	"github.com/titpetric/platform-app/pulse/model"
 	"github.com/titpetric/platform-app/user"
 )
 
 // Storage provides pulse data persistence.
 type Storage struct {
-	db *sqlx.DB
+	db  *sqlx.DB
+	hub *Hub
 }
 
 // NewStorage creates a new storage backed by the given database.
 func NewStorage(db *sqlx.DB) *Storage {
 	return &Storage{
-		db: db,
+		db:  db,
+		hub: NewHub(),
 	}
 }
 
-// Pulse records keystroke activity for the authenticated user.
+// Subscribe returns a channel receiving the entries ingested for a user
+// from now on, and a function to unsubscribe. See Hub.Subscribe.
+func (s *Storage) Subscribe(userID string) (<-chan Event, func()) {
+	return s.hub.Subscribe(userID)
+}
+
+// MaxBatchSize is the largest number of entries accepted by PulseBatch.
+const MaxBatchSize = 1000
+
+// ErrInvalidBatch is returned by PulseBatch for a batch that fails
+// validation. Sending it again fails the same way.
+var ErrInvalidBatch = errors.New("invalid batch")
+
+// MaxBackfill is how far in the past an entry stamp may be. Older
+// stamps are clamped to this boundary.
+const MaxBackfill = 7 * 24 * time.Hour
+
+// BatchTTL is how long ingested batch IDs are remembered. Replays of a
+// batch within this window are acknowledged without being applied.
+const BatchTTL = 2 * MaxBackfill
+
+// Entry holds a keystroke count for a host at a point in time. The
+// optional Categories break the count down by key category.
+type Entry struct {
+	Hostname   string                   `json:"hostname"`
+	Stamp      time.Time                `json:"stamp"`
+	Count      int64                    `json:"count"`
+	Categories map[model.Category]int64 `json:"categories,omitempty"`
+}
+
+// Pulse records keystroke activity for the authenticated user at the current time.
 func (s *Storage) Pulse(ctx context.Context, count int64, hostname string) error {
+	return s.PulseBatch(ctx, "", []Entry{
+		{
+			Hostname: hostname,
+			Stamp:    time.Now(),
+			Count:    count,
+		},
+	})
+}
+
+// PulseBatch records timestamped keystroke activity for the authenticated
+// user. All entries are applied in a single transaction. A non-empty
+// batchID makes the call idempotent: a batch that was already applied
+// is acknowledged without counting it twice.
+func (s *Storage) PulseBatch(ctx context.Context, batchID string, entries []Entry) error {
 	user, active := user.GetSessionUser(ctx)
 	if !active {
 		return errors.New("invalid user auth")
 	}
 
-	return platform.Transaction(ctx, s.db, s.pulseFn(user.ID, count, hostname))
+	if batchID != "" && !ValidBatchID(batchID) {
+		return fmt.Errorf("%w: invalid batch id: %q", ErrInvalidBatch, batchID)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("%w: no entries to ingest", ErrInvalidBatch)
+	}
+	if len(entries) > MaxBatchSize {
+		return fmt.Errorf("%w: too many entries: %d > %d", ErrInvalidBatch, len(entries), MaxBatchSize)
+	}
+
+	now := time.Now()
+	clamped := make([]Entry, len(entries))
+	for i, entry := range entries {
+		if entry.Count <= 0 {
+			return fmt.Errorf("%w: count must be positive: %d", ErrInvalidBatch, entry.Count)
+		}
+		if entry.Hostname == "" {
+			return fmt.Errorf("%w: hostname is required", ErrInvalidBatch)
+		}
+		for category, n := range entry.Categories {
+			if !category.Valid() {
+				return fmt.Errorf("%w: invalid category: %q", ErrInvalidBatch, category)
+			}
+			if n < 0 {
+				return fmt.Errorf("%w: category count must not be negative: %d", ErrInvalidBatch, n)
+			}
+		}
+		entry.Stamp = ClampStamp(entry.Stamp, now)
+		clamped[i] = entry
+	}
+
+	// Daily rows follow the user's timezone, rebuild them if it changed.
+	if err := s.syncTimezone(ctx, user.ID, user.Location()); err != nil {
+		return err
+	}
+
+	var applied bool
+	if err := platform.Transaction(ctx, s.db, s.pulseFn(user.ID, user.Location(), batchID, clamped, &applied)); err != nil {
+		return err
+	}
+	if !applied {
+		return nil
+	}
+
+	events := make([]Event, len(clamped))
+	for i, entry := range clamped {
+		events[i] = Event{
+			UserID:   user.ID,
+			Hostname: entry.Hostname,
+			Stamp:    entry.Stamp,
+			Count:    entry.Count,
+		}
+	}
+	s.hub.Publish(events...)
+	return nil
+}
+
+// ValidBatchID reports whether id is a ULID string.
+func ValidBatchID(id string) bool {
+	if len(id) != 26 {
+		return false
+	}
+	for _, c := range strings.ToUpper(id) {
+		if !strings.ContainsRune("0123456789ABCDEFGHJKMNPQRSTVWXYZ", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// ClampStamp bounds an entry stamp to the accepted ingest window. A zero
+// or future stamp becomes now, and stamps older than MaxBackfill are
+// moved up to the backfill boundary.
+func ClampStamp(stamp, now time.Time) time.Time {
+	if stamp.IsZero() || stamp.After(now) {
+		return now
+	}
+	if oldest := now.Add(-MaxBackfill); stamp.Before(oldest) {
+		return oldest
+	}
+	return stamp
 }
 
 // UserCount holds a user's total keystroke count.
@@ -41,39 +169,66 @@ type UserCount struct {
 	Count  int64  `db:"count"`
 }
 
-// ListUserCounts returns the total keystroke count per user for the last 30 days.
-func (s *Storage) ListUserCounts(ctx context.Context) ([]UserCount, error) {
-	var counts []UserCount
-	query := `SELECT user_id, SUM(count) as count FROM pulse_daily WHERE stamp >= date('now', '-30 days') GROUP BY user_id ORDER BY count DESC`
-	if err := s.db.SelectContext(ctx, &counts, query); err != nil {
-		return nil, fmt.Errorf("list user counts: %w", err)
-	}
-	return counts, nil
-}
-
 // HourlyCount holds hourly keystroke data.
 type HourlyCount struct {
 	Hour  int   `db:"hour" json:"hour"`
 	Count int64 `db:"count" json:"count"`
 }
 
-// GetUserHourly returns hourly keystroke distribution for a user (aggregated across all days).
-func (s *Storage) GetUserHourly(ctx context.Context, userID string) ([]HourlyCount, error) {
-	var counts []HourlyCount
+// GetUserHourly returns the hourly keystroke distribution for a user,
+// aggregated across the days in the range. Hours are computed in the
+// given location.
+func (s *Storage) GetUserHourly(ctx context.Context, userID string, loc *time.Location, r Range) ([]HourlyCount, error) {
+	var rows []struct {
+		Stamp string `db:"stamp"`
+		Count int64  `db:"count"`
+	}
+	r.From, r.To = r.From.UTC(), r.To.UTC()
+	cond, args := r.where("2006-01-02 15:04:05")
 	query := `
-		SELECT
-			CAST(strftime('%H', stamp) AS INTEGER) as hour,
-			SUM(count) as count
+		SELECT stamp, SUM(count) as count
 		FROM pulse_hourly
-		WHERE user_id = ?
-		GROUP BY hour
-		ORDER BY hour`
-	if err := s.db.SelectContext(ctx, &counts, query, userID); err != nil {
+		WHERE user_id = ? AND ` + cond + `
+		GROUP BY stamp`
+	if err := s.db.SelectContext(ctx, &rows, query, append([]any{userID}, args...)...); err != nil {
 		return nil, fmt.Errorf("get user hourly: %w", err)
 	}
+
+	// Hourly rows are stored in UTC. Bucketing by hour happens here
+	// rather than in SQL, so daylight saving changes are respected.
+	var byHour [24]int64
+	for _, row := range rows {
+		stamp, err := ParseStamp(row.Stamp)
+		if err != nil {
+			return nil, fmt.Errorf("get user hourly: %w", err)
+		}
+		byHour[stamp.In(loc).Hour()] += row.Count
+	}
+
+	var counts []HourlyCount
+	for hour, count := range byHour {
+		if count > 0 {
+			counts = append(counts, HourlyCount{Hour: hour, Count: count})
+		}
+	}
 	return counts, nil
 }
 
+// ParseStamp parses a stamp as returned by the database driver.
+func ParseStamp(value string) (time.Time, error) {
+	layouts := []string{
+		"2006-01-02 15:04:05",
+		time.RFC3339Nano,
+		"2006-01-02",
+	}
+	for _, layout := range layouts {
+		if stamp, err := time.Parse(layout, value); err == nil {
+			return stamp, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid stamp: %q", value)
+}
+
 // DailyHostCount holds daily keystroke data per host.
 type DailyHostCount struct {
 	Hostname string `db:"hostname" json:"hostname"`
@@ -81,15 +236,65 @@ type DailyHostCount struct {
 	Count    int64  `db:"count" json:"count"`
 }
 
-// GetUserDaily returns daily keystroke counts per host for a user over the last 30 days.
-func (s *Storage) GetUserDaily(ctx context.Context, userID string) ([]DailyHostCount, error) {
+// Range selects pulse data between From and To (inclusive), optionally
+// limited to a single host or excluding some hosts.
+type Range struct {
+	From     time.Time
+	To       time.Time
+	Hostname string
+	Exclude  []string
+}
+
+// LastDays returns a range covering the last n days up to now, where
+// days are taken in the given location.
+func LastDays(n int, loc *time.Location) Range {
+	now := time.Now().In(loc)
+	return Range{
+		From: now.AddDate(0, 0, -n),
+		To:   now,
+	}
+}
+
+// where returns the SQL condition and arguments for the range. Stamps
+// are formatted with layout, in the location of the range bounds.
+func (r Range) where(layout string) (string, []any) {
+	query := "stamp >= ? AND stamp <= ?"
+	args := []any{r.From.Format(layout), r.To.Format(layout)}
+	if r.Hostname != "" {
+		query += " AND hostname = ?"
+		args = append(args, r.Hostname)
+	}
+	if cond, excludeArgs := excludeHosts(r.Exclude); cond != "" {
+		query += " AND " + cond
+		args = append(args, excludeArgs...)
+	}
+	return query, args
+}
+
+// excludeHosts returns an SQL condition leaving out the given hosts.
+func excludeHosts(hosts []string) (string, []any) {
+	if len(hosts) == 0 {
+		return "", nil
+	}
+	args := make([]any, len(hosts))
+	for i, host := range hosts {
+		args[i] = host
+	}
+	return "hostname NOT IN (?" + strings.Repeat(", ?", len(hosts)-1) + ")", args
+}
+
+// GetUserDaily returns daily keystroke counts per host for a user within
+// the range. Days are compared as dates in the location of the range.
+func (s *Storage) GetUserDaily(ctx context.Context, userID string, r Range) ([]DailyHostCount, error) {
+	cond, args := r.where("2006-01-02")
+
 	var counts []DailyHostCount
 	query := `
 		SELECT hostname, date(stamp) as stamp, count
 		FROM pulse_daily
-		WHERE user_id = ? AND stamp >= date('now', '-30 days')
+		WHERE user_id = ? AND ` + cond + `
 		ORDER BY hostname, stamp`
-	if err := s.db.SelectContext(ctx, &counts, query, userID); err != nil {
+	if err := s.db.SelectContext(ctx, &counts, query, append([]any{userID}, args...)...); err != nil {
 		return nil, fmt.Errorf("get user daily: %w", err)
 	}
 	return counts, nil
@@ -109,15 +314,19 @@ func (s *Storage) GetUserHourlyByHost(ctx context.Context, userID string, hostna
 	return counts, nil
 }
 
-// GetUserHourlyAll returns hourly keystroke counts per host for a user over the last 30 days.
-func (s *Storage) GetUserHourlyAll(ctx context.Context, userID string) ([]DailyHostCount, error) {
+// GetUserHourlyAll returns hourly keystroke counts per host for a user
+// within the range. Hourly stamps are stored and returned in UTC.
+func (s *Storage) GetUserHourlyAll(ctx context.Context, userID string, r Range) ([]DailyHostCount, error) {
+	r.From, r.To = r.From.UTC(), r.To.UTC()
+	cond, args := r.where("2006-01-02 15:04:05")
+
 	var counts []DailyHostCount
 	query := `
 		SELECT hostname, stamp, count
 		FROM pulse_hourly
-		WHERE user_id = ? AND stamp >= datetime('now', '-30 days')
+		WHERE user_id = ? AND ` + cond + `
 		ORDER BY hostname, stamp`
-	if err := s.db.SelectContext(ctx, &counts, query, userID); err != nil {
+	if err := s.db.SelectContext(ctx, &counts, query, append([]any{userID}, args...)...); err != nil {
 		return nil, fmt.Errorf("get user hourly all: %w", err)
 	}
 	return counts, nil
@@ -133,53 +342,140 @@ func (s *Storage) GetUserHosts(ctx context.Context, userID string) ([]string, er
 	return hosts, nil
 }
 
-// https://github.com/jmoiron/sqlx/issues/368
-// - uses :: to escape non-named params
-// - replaces :count to literal int
 const updatePulseHourly = `
 INSERT INTO
   pulse_hourly (user_id, hostname, stamp, count)
 VALUES
-  (:user_id, :hostname, strftime('%Y-%m-%d %H::00::00', 'now'), :count)
+  (?, ?, ?, ?)
+ON
+  CONFLICT(user_id, hostname, stamp)
+DO
+  UPDATE SET count = count + excluded.count`
+
+const updatePulseMinutely = `
+INSERT INTO
+  pulse_minutely (user_id, hostname, stamp, count)
+VALUES
+  (?, ?, ?, ?)
 ON
   CONFLICT(user_id, hostname, stamp)
 DO
-  UPDATE SET count = count + :count`
+  UPDATE SET count = count + excluded.count`
 
 const updatePulseDaily = `
 INSERT INTO
   pulse_daily (user_id, hostname, stamp, count)
 VALUES
-  (:user_id, :hostname, strftime('%Y-%m-%d', 'now'), :count)
+  (?, ?, ?, ?)
 ON
   CONFLICT(user_id, hostname, stamp)
 DO
-  UPDATE SET count = count + :count`
+  UPDATE SET count = count + excluded.count`
+
+const updatePulseHourlyCategory = `
+INSERT INTO
+  pulse_hourly_category (user_id, hostname, stamp, category, count)
+VALUES
+  (?, ?, ?, ?, ?)
+ON
+  CONFLICT(user_id, hostname, stamp, category)
+DO
+  UPDATE SET count = count + excluded.count`
 
-const updatePulseHosts = `INSERT OR IGNORE INTO pulse_hosts (user_id, hostname, created_at) VALUES (:user_id, :hostname, CURRENT_TIMESTAMP)`
+const updatePulseDailyCategory = `
+INSERT INTO
+  pulse_daily_category (user_id, hostname, stamp, category, count)
+VALUES
+  (?, ?, ?, ?, ?)
+ON
+  CONFLICT(user_id, hostname, stamp, category)
+DO
+  UPDATE SET count = count + excluded.count`
 
-func (s *Storage) pulseFn(userID string, count int64, hostname string) func(context.Context, *sqlx.Tx) error {
-	params := map[string]any{
-		"user_id":  userID,
-		"hostname": hostname,
-	}
+const updatePulseHosts = `INSERT OR IGNORE INTO pulse_hosts (user_id, hostname, created_at) VALUES (?, ?, CURRENT_TIMESTAMP)`
 
+const purgePulseIngest = `DELETE FROM pulse_ingest WHERE user_id = ? AND created_at < ?`
+
+const insertPulseIngest = `
+INSERT INTO
+  pulse_ingest (user_id, batch_id, created_at)
+VALUES
+  (?, ?, ?)
+ON
+  CONFLICT(user_id, batch_id)
+DO NOTHING`
+
+// pulseFn returns the ingest transaction. It sets applied when the
+// entries were counted, that is unless the batch is a replay.
+func (s *Storage) pulseFn(userID string, loc *time.Location, batchID string, entries []Entry, applied *bool) func(context.Context, *sqlx.Tx) error {
 	return func(ctx context.Context, tx *sqlx.Tx) error {
-		query := strings.ReplaceAll(updatePulseHourly, ":count", fmt.Sprint(count))
-		if _, err := tx.NamedExecContext(ctx, query, params); err != nil {
-			return fmt.Errorf("error in %s: %w", query, err)
+		if batchID != "" {
+			now := time.Now().UTC()
+
+			query := tx.Rebind(purgePulseIngest)
+			if _, err := tx.ExecContext(ctx, query, userID, now.Add(-BatchTTL)); err != nil {
+				return fmt.Errorf("error in %s: %w", query, err)
+			}
+
+			query = tx.Rebind(insertPulseIngest)
+			res, err := tx.ExecContext(ctx, query, userID, batchID, now)
+			if err != nil {
+				return fmt.Errorf("error in %s: %w", query, err)
+			}
+			if n, err := res.RowsAffected(); err == nil && n == 0 {
+				// Replay of an already applied batch.
+				return nil
+			}
 		}
 
-		query = strings.ReplaceAll(updatePulseDaily, ":count", fmt.Sprint(count))
-		if _, err := tx.NamedExecContext(ctx, query, params); err != nil {
-			return fmt.Errorf("error in %s: %w", query, err)
+		hosts := make(map[string]bool)
+		for _, entry := range entries {
+			// Minutely and hourly rows are kept in UTC, daily rows
+			// follow the user's timezone so a day means their local day.
+			minutely := entry.Stamp.UTC().Truncate(time.Minute).Format("2006-01-02 15:04:05")
+			hourly := entry.Stamp.UTC().Truncate(time.Hour).Format("2006-01-02 15:04:05")
+			daily := entry.Stamp.In(loc).Format("2006-01-02")
+
+			query := tx.Rebind(updatePulseMinutely)
+			if _, err := tx.ExecContext(ctx, query, userID, entry.Hostname, minutely, entry.Count); err != nil {
+				return fmt.Errorf("error in %s: %w", query, err)
+			}
+
+			query = tx.Rebind(updatePulseHourly)
+			if _, err := tx.ExecContext(ctx, query, userID, entry.Hostname, hourly, entry.Count); err != nil {
+				return fmt.Errorf("error in %s: %w", query, err)
+			}
+
+			query = tx.Rebind(updatePulseDaily)
+			if _, err := tx.ExecContext(ctx, query, userID, entry.Hostname, daily, entry.Count); err != nil {
+				return fmt.Errorf("error in %s: %w", query, err)
+			}
+
+			for category, n := range entry.Categories {
+				if n == 0 {
+					continue
+				}
+				query = tx.Rebind(updatePulseHourlyCategory)
+				if _, err := tx.ExecContext(ctx, query, userID, entry.Hostname, hourly, category, n); err != nil {
+					return fmt.Errorf("error in %s: %w", query, err)
+				}
+				query = tx.Rebind(updatePulseDailyCategory)
+				if _, err := tx.ExecContext(ctx, query, userID, entry.Hostname, daily, category, n); err != nil {
+					return fmt.Errorf("error in %s: %w", query, err)
+				}
+			}
+
+			hosts[entry.Hostname] = true
 		}
 
-		query = updatePulseHosts
-		if _, err := tx.NamedExecContext(ctx, query, params); err != nil {
-			return fmt.Errorf("error in %s: %w", query, err)
+		for hostname := range hosts {
+			query := tx.Rebind(updatePulseHosts)
+			if _, err := tx.ExecContext(ctx, query, userID, hostname); err != nil {
+				return fmt.Errorf("error in %s: %w", query, err)
+			}
 		}
 
+		*applied = true
 		return nil
 	}
 }
@@ -13,6 +13,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/titpetric/platform-app/pulse/schema"
+	"github.com/titpetric/platform-app/user"
+	"github.com/titpetric/platform-app/user/model"
 )
 
 func newTestStorage(t *testing.T) *Storage {
@@ -68,7 +70,7 @@ func TestGetUserDaily(t *testing.T) {
 		{"chronos", today, 200},
 	})
 
-	results, err := s.GetUserDaily(ctx, userID)
+	results, err := s.GetUserDaily(ctx, userID, LastDays(30, time.UTC))
 	require.NoError(t, err)
 	assert.Len(t, results, 3)
 
@@ -95,7 +97,7 @@ func TestDailyBarChartLogic(t *testing.T) {
 		{"chronos", today, 200},
 	})
 
-	dailyData, err := s.GetUserDaily(ctx, userID)
+	dailyData, err := s.GetUserDaily(ctx, userID, LastDays(30, time.UTC))
 	require.NoError(t, err)
 
 	hosts, err := s.GetUserHosts(ctx, userID)
@@ -144,3 +146,157 @@ func TestDailyBarChartLogic(t *testing.T) {
 	assert.Equal(t, "height: 100%", fmt.Sprintf("height: %d%%", labCounts[today]*100/maxCount))
 	assert.Equal(t, "height: 50%", fmt.Sprintf("height: %d%%", labCounts[yesterday]*100/maxCount))
 }
+
+func TestPulseBatch(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER"})
+
+	now := time.Now().UTC()
+	hour := now.Truncate(time.Hour).Add(-2 * time.Hour)
+
+	err := s.PulseBatch(ctx, "", []Entry{
+		{Hostname: "lab", Stamp: hour.Add(5 * time.Minute), Count: 100},
+		{Hostname: "lab", Stamp: hour.Add(50 * time.Minute), Count: 50},
+		{Hostname: "chronos", Stamp: now.Add(time.Hour), Count: 10},
+	})
+	require.NoError(t, err)
+
+	var count int64
+	err = s.db.Get(&count, `SELECT count FROM pulse_hourly WHERE user_id = ? AND hostname = ? AND stamp = ?`, "TESTUSER", "lab", hour.Format("2006-01-02 15:04:05"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(150), count)
+
+	// Future stamps are clamped to now.
+	err = s.db.Get(&count, `SELECT count FROM pulse_hourly WHERE user_id = ? AND hostname = ? AND stamp = ?`, "TESTUSER", "chronos", now.Truncate(time.Hour).Format("2006-01-02 15:04:05"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), count)
+
+	hosts, err := s.GetUserHosts(ctx, "TESTUSER")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chronos", "lab"}, hosts)
+
+	// Invalid entries reject the whole batch before anything is written.
+	err = s.PulseBatch(ctx, "", []Entry{
+		{Hostname: "lab", Stamp: now, Count: 1},
+		{Hostname: "lab", Stamp: now, Count: 0},
+	})
+	assert.ErrorIs(t, err, ErrInvalidBatch)
+
+	// A write failing partway through the transaction rolls back the
+	// entries written before it.
+	_, err = s.db.Exec(`CREATE TRIGGER fail_pulse_hourly BEFORE INSERT ON pulse_hourly WHEN NEW.hostname = 'broken' BEGIN SELECT RAISE(ABORT, 'write failed'); END`)
+	require.NoError(t, err)
+
+	err = s.PulseBatch(ctx, "", []Entry{
+		{Hostname: "rollback", Stamp: now, Count: 1},
+		{Hostname: "broken", Stamp: now, Count: 1},
+	})
+	assert.ErrorContains(t, err, "write failed")
+
+	for _, table := range []string{"pulse_minutely", "pulse_hourly", "pulse_daily", "pulse_hosts"} {
+		err = s.db.Get(&count, `SELECT COUNT(*) FROM `+table+` WHERE user_id = ? AND hostname IN ('rollback', 'broken')`, "TESTUSER")
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count, table)
+	}
+
+	// Ingest requires an authenticated user.
+	assert.Error(t, s.PulseBatch(context.Background(), "", []Entry{{Hostname: "lab", Count: 1}}))
+}
+
+func TestClampStamp(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, now, ClampStamp(time.Time{}, now))
+	assert.Equal(t, now, ClampStamp(now.Add(time.Hour), now))
+	assert.Equal(t, now.Add(-time.Hour), ClampStamp(now.Add(-time.Hour), now))
+	assert.Equal(t, now.Add(-MaxBackfill), ClampStamp(now.AddDate(-1, 0, 0), now))
+}
+
+func TestPulseBatchIdempotent(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER"})
+
+	stamp := time.Now().UTC().Truncate(time.Hour)
+	entries := []Entry{
+		{Hostname: "lab", Stamp: stamp, Count: 100},
+	}
+
+	batchID := "01HZX3K8M6Q0W6R4T2Y9B1C5D7"
+	require.NoError(t, s.PulseBatch(ctx, batchID, entries))
+	require.NoError(t, s.PulseBatch(ctx, batchID, entries))
+
+	var count int64
+	err := s.db.Get(&count, `SELECT count FROM pulse_hourly WHERE user_id = ? AND hostname = ?`, "TESTUSER", "lab")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), count)
+
+	// Batches without an ID are always applied.
+	require.NoError(t, s.PulseBatch(ctx, "", entries))
+	err = s.db.Get(&count, `SELECT count FROM pulse_hourly WHERE user_id = ? AND hostname = ?`, "TESTUSER", "lab")
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), count)
+
+	assert.ErrorIs(t, s.PulseBatch(ctx, "not-a-ulid", entries), ErrInvalidBatch)
+}
+
+func TestUserTimezone(t *testing.T) {
+	s := newTestStorage(t)
+
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	ctx := user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER", Timezone: loc.String()})
+
+	// 02:30 UTC is the previous evening in New York.
+	stamp := time.Now().UTC().Truncate(24 * time.Hour).Add(150 * time.Minute)
+	if stamp.After(time.Now()) {
+		stamp = stamp.AddDate(0, 0, -1)
+	}
+	require.NoError(t, s.PulseBatch(ctx, "", []Entry{{Hostname: "lab", Stamp: stamp, Count: 10}}))
+
+	hourly, err := s.GetUserHourly(ctx, "TESTUSER", loc, LastDays(30, loc))
+	require.NoError(t, err)
+	require.Len(t, hourly, 1)
+	assert.Equal(t, stamp.In(loc).Hour(), hourly[0].Hour)
+
+	hourly, err = s.GetUserHourly(ctx, "TESTUSER", time.UTC, LastDays(30, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, hourly, 1)
+	assert.Equal(t, 2, hourly[0].Hour)
+
+	// Hours outside the range are left out.
+	hourly, err = s.GetUserHourly(ctx, "TESTUSER", time.UTC, Range{From: stamp.Add(time.Hour), To: time.Now()})
+	require.NoError(t, err)
+	assert.Empty(t, hourly)
+
+	daily, err := s.GetUserDaily(ctx, "TESTUSER", LastDays(30, loc))
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+	assert.Equal(t, stamp.In(loc).Format("2006-01-02"), daily[0].Stamp)
+	assert.NotEqual(t, stamp.Format("2006-01-02"), daily[0].Stamp)
+}
+
+func TestGetUserRange(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER"})
+
+	now := time.Now().UTC().Truncate(time.Hour)
+	require.NoError(t, s.PulseBatch(ctx, "", []Entry{
+		{Hostname: "lab", Stamp: now.Add(-72 * time.Hour), Count: 1},
+		{Hostname: "lab", Stamp: now.Add(-2 * time.Hour), Count: 2},
+		{Hostname: "chronos", Stamp: now.Add(-2 * time.Hour), Count: 3},
+	}))
+
+	hourly, err := s.GetUserHourlyAll(ctx, "TESTUSER", Range{From: now.Add(-24 * time.Hour), To: now})
+	require.NoError(t, err)
+	assert.Len(t, hourly, 2)
+
+	hourly, err = s.GetUserHourlyAll(ctx, "TESTUSER", Range{From: now.Add(-96 * time.Hour), To: now, Hostname: "lab"})
+	require.NoError(t, err)
+	assert.Len(t, hourly, 2)
+
+	daily, err := s.GetUserDaily(ctx, "TESTUSER", Range{From: now.Add(-96 * time.Hour), To: now, Hostname: "chronos"})
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+	assert.Equal(t, int64(3), daily[0].Count)
+}
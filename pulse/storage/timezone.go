@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// syncTimezone rebuilds the daily rows of a user when their timezone
+// changed since the rows were built, see RebuildDaily. Days older than
+// the hourly rows keep the timezone they were ingested in. The timezone
+// is recorded on the first ingest of a user.
+func (s *Storage) syncTimezone(ctx context.Context, userID string, loc *time.Location) error {
+	var timezones []string
+	query := `SELECT timezone FROM pulse_profile WHERE user_id = ?`
+	if err := s.db.SelectContext(ctx, &timezones, query, userID); err != nil {
+		return fmt.Errorf("get timezone: %w", err)
+	}
+	if len(timezones) > 0 && timezones[0] == loc.String() {
+		return nil
+	}
+
+	if len(timezones) > 0 && timezones[0] != "" {
+		if _, err := s.RebuildDaily(ctx, userID, loc); err != nil {
+			return err
+		}
+	}
+
+	query = `
+INSERT INTO
+  pulse_profile (user_id, timezone, updated_at)
+VALUES
+  (?, ?, ?)
+ON
+  CONFLICT(user_id)
+DO
+  UPDATE SET timezone = excluded.timezone, updated_at = excluded.updated_at`
+
+	if _, err := s.db.ExecContext(ctx, s.db.Rebind(query), userID, loc.String(), time.Now()); err != nil {
+		return fmt.Errorf("set timezone: %w", err)
+	}
+	return nil
+}
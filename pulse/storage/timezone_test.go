@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/user"
+	"github.com/titpetric/platform-app/user/model"
+)
+
+func TestTimezoneChange(t *testing.T) {
+	s := newTestStorage(t)
+
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Midnight in New York three days ago, and 02:30 UTC the day after,
+	// which is the same evening in New York.
+	now := time.Now().In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day()-3, 0, 0, 0, 0, loc)
+	evening := time.Date(now.Year(), now.Month(), now.Day()-2, 2, 30, 0, 0, time.UTC)
+
+	ctx := user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER"})
+	require.NoError(t, s.PulseBatch(ctx, "", []Entry{
+		{Hostname: "lab", Stamp: midnight, Count: 1},
+		{Hostname: "lab", Stamp: evening, Count: 10},
+	}))
+
+	daily, err := s.GetUserDaily(ctx, "TESTUSER", Range{From: midnight.AddDate(0, 0, -1), To: time.Now()})
+	require.NoError(t, err)
+	require.Len(t, daily, 2)
+
+	// The next ingest after the timezone change rebuilds the daily rows.
+	ctx = user.SetSessionUser(context.Background(), &model.User{ID: "TESTUSER", Timezone: loc.String()})
+	require.NoError(t, s.PulseBatch(ctx, "", []Entry{{Hostname: "work", Stamp: time.Now(), Count: 1}}))
+
+	rng := Range{From: midnight.AddDate(0, 0, -1), To: time.Now().In(loc), Hostname: "lab"}
+	daily, err = s.GetUserDaily(ctx, "TESTUSER", rng)
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+	assert.Equal(t, midnight.Format("2006-01-02"), daily[0].Stamp)
+	assert.Equal(t, int64(11), daily[0].Count)
+}
@@ -0,0 +1,95 @@
+// Package admin implements the user account maintenance commands. Apps
+// add them to their CLI, pulse has them as `pulse user`.
+package admin
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/titpetric/cli"
+
+	"github.com/titpetric/platform-app/user/storage"
+)
+
+// Name is the command title.
+const Name = "Maintain user accounts (exempt-user)"
+
+// usage lists the admin commands.
+const usage = `usage: user <command>
+
+commands:
+  exempt-user [--clear] USER  exempt a user from password expiry and email
+                              re-verification, by username or ID`
+
+// Options holds admin command configuration.
+type Options struct {
+	Clear bool
+}
+
+// Bind registers admin flags with the flag set.
+func (o *Options) Bind(flag *cli.FlagSet) {
+	flag.BoolVar(&o.Clear, "clear", false, "Clear the exemption of a user")
+}
+
+// NewCommand creates a new user admin command.
+func NewCommand() *cli.Command {
+	var opts Options
+
+	return &cli.Command{
+		Name:  "user",
+		Title: Name,
+		Bind:  opts.Bind,
+		Run: func(ctx context.Context, args []string) error {
+			return Run(ctx, opts, args)
+		},
+	}
+}
+
+// Run runs the admin command given in args against the user database,
+// PLATFORM_DB_USER.
+func Run(ctx context.Context, opts Options, args []string) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	switch args[0] {
+	case "exempt-user":
+		if len(args) != 2 {
+			return errors.New("usage: user exempt-user [--clear] USER")
+		}
+		return exemptUser(ctx, opts, args[1])
+	}
+	return fmt.Errorf("unknown command: %s\n\n%s", args[0], usage)
+}
+
+func exemptUser(ctx context.Context, opts Options, name string) error {
+	db, err := storage.DB(ctx)
+	if err != nil {
+		return err
+	}
+	userStorage := storage.NewUserStorage(db)
+
+	userID := name
+	u, err := userStorage.GetByUsername(ctx, name)
+	switch {
+	case err == nil:
+		userID = u.ID
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+
+	if err := userStorage.SetPolicyExempt(ctx, userID, !opts.Clear); errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("user not found: %s", name)
+	} else if err != nil {
+		return err
+	}
+
+	if opts.Clear {
+		fmt.Printf("%s: policies enforced\n", userID)
+	} else {
+		fmt.Printf("%s: exempt from policies\n", userID)
+	}
+	return nil
+}
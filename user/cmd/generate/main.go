@@ -2,6 +2,8 @@ package main
 
 import (
 	"log"
+
+	"github.com/titpetric/platform-app/user/flow"
 )
 
 func main() {
@@ -20,6 +22,11 @@ func start() error {
 		return err
 	}
 
+	// Fail on configurations the runtime flow engine would reject.
+	if _, err := flow.New(cfg); err != nil {
+		return err
+	}
+
 	if err := GenerateRego(cfg, "opa/flows.rego"); err != nil {
 		return err
 	}
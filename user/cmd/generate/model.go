@@ -1,34 +1,16 @@
 package main
 
 import (
-	"io/ioutil"
-
-	yaml "gopkg.in/yaml.v3"
+	"github.com/titpetric/platform-app/user/flow"
 )
 
-type Config struct {
-	Features map[string]interface{} `yaml:"features"`
-	Flows    map[string]Flow        `yaml:"flows"`
-}
-
-type Flow struct {
-	Steps []Step `yaml:"steps"`
-}
-
-type Step struct {
-	Name      string            `yaml:"name"`
-	View      string            `yaml:"view"`
-	Link      string            `yaml:"link"`
-	EnabledIf string            `yaml:"enabled_if"`
-	Next      map[string]string `yaml:"next"`
-}
+// The generator shares the flows.yml model with the runtime flow engine.
+type (
+	Config = flow.Config
+	Flow   = flow.Flow
+	Step   = flow.Step
+)
 
 func LoadConfig(path string) (*Config, error) {
-	data, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-	cfg := &Config{}
-	err = yaml.Unmarshal(data, cfg)
-	return cfg, err
+	return flow.Load(path)
 }
@@ -0,0 +1,146 @@
+package flow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// operators are the comparisons supported in enabled_if, longest first
+// so ">=" isn't read as ">".
+var operators = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// condition is a parsed enabled_if expression. It is either a feature
+// name, true when the feature is set and not false or zero, or a
+// comparison of a feature against a literal, e.g.
+// "features.password_expiry_days > 0".
+type condition struct {
+	feature  string
+	operator string
+	value    interface{}
+}
+
+// parseCondition parses an enabled_if expression.
+func parseCondition(expr string) (*condition, error) {
+	expr = strings.TrimSpace(expr)
+
+	left, operator, right := expr, "", ""
+	for _, op := range operators {
+		if i := strings.Index(expr, op); i >= 0 {
+			left, operator, right = strings.TrimSpace(expr[:i]), op, strings.TrimSpace(expr[i+len(op):])
+			break
+		}
+	}
+
+	feature, ok := strings.CutPrefix(left, "features.")
+	if !ok || feature == "" || strings.ContainsAny(feature, " .") {
+		return nil, fmt.Errorf("condition %q: expected features.<name>", expr)
+	}
+
+	c := &condition{feature: feature, operator: operator}
+	if operator == "" {
+		return c, nil
+	}
+
+	value, err := parseLiteral(right)
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", expr, err)
+	}
+	if _, isNumber := value.(float64); !isNumber && operator != "==" && operator != "!=" {
+		return nil, fmt.Errorf("condition %q: %s needs a number", expr, operator)
+	}
+	c.value = value
+	return c, nil
+}
+
+// parseLiteral parses the right hand side of a comparison: a number,
+// true, false, or a quoted string.
+func parseLiteral(s string) (interface{}, error) {
+	switch s {
+	case "":
+		return nil, fmt.Errorf("missing value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		return unquoted, nil
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return n, nil
+	}
+	return nil, fmt.Errorf("invalid value %q", s)
+}
+
+// eval evaluates the condition against the features. Missing features
+// are false.
+func (c *condition) eval(features map[string]interface{}) bool {
+	value, ok := features[c.feature]
+	if !ok {
+		return false
+	}
+
+	if c.operator == "" {
+		return truthy(value)
+	}
+
+	if want, ok := c.value.(float64); ok {
+		got, ok := number(value)
+		if !ok {
+			return false
+		}
+		switch c.operator {
+		case ">=":
+			return got >= want
+		case "<=":
+			return got <= want
+		case ">":
+			return got > want
+		case "<":
+			return got < want
+		case "==":
+			return got == want
+		default:
+			return got != want
+		}
+	}
+
+	equal := value == c.value
+	if c.operator == "!=" {
+		return !equal
+	}
+	return equal
+}
+
+// truthy reports whether a feature value is set: not false, zero or
+// empty.
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+	if n, ok := number(value); ok {
+		return n != 0
+	}
+	return true
+}
+
+// number converts the numeric types YAML decodes into to float64.
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
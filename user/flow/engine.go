@@ -0,0 +1,170 @@
+package flow
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Engine walks the flows of a Config. Steps whose enabled_if condition
+// doesn't hold for the configured features are skipped.
+type Engine struct {
+	cfg        *Config
+	conditions map[string]*condition
+}
+
+// New checks the configuration and returns an Engine for it. Every flow
+// needs steps with unique names and a link, every enabled_if must parse,
+// and "ok" transitions must name a step of the same flow. Other results,
+// like "error", may name steps that don't exist; they keep the user on
+// the current step.
+func New(cfg *Config) (*Engine, error) {
+	e := &Engine{
+		cfg:        cfg,
+		conditions: make(map[string]*condition),
+	}
+
+	for _, name := range e.Flows() {
+		f := cfg.Flows[name]
+		if len(f.Steps) == 0 {
+			return nil, fmt.Errorf("flow %s: no steps", name)
+		}
+
+		seen := make(map[string]bool, len(f.Steps))
+		for _, step := range f.Steps {
+			if step.Name == "" {
+				return nil, fmt.Errorf("flow %s: step without a name", name)
+			}
+			if seen[step.Name] {
+				return nil, fmt.Errorf("flow %s: duplicate step %s", name, step.Name)
+			}
+			seen[step.Name] = true
+
+			if step.Link == "" {
+				return nil, fmt.Errorf("flow %s: step %s: no link", name, step.Name)
+			}
+			if step.EnabledIf != "" {
+				c, err := parseCondition(step.EnabledIf)
+				if err != nil {
+					return nil, fmt.Errorf("flow %s: step %s: %w", name, step.Name, err)
+				}
+				e.conditions[step.EnabledIf] = c
+			}
+		}
+
+		for _, step := range f.Steps {
+			if next, ok := step.Next["ok"]; ok && !seen[next] {
+				return nil, fmt.Errorf("flow %s: step %s: unknown ok step %s", name, step.Name, next)
+			}
+		}
+	}
+
+	return e, nil
+}
+
+// Must is a helper that wraps a call returning an Engine and panics if
+// the error is non-nil, like template.Must.
+func Must(e *Engine, err error) *Engine {
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Flows returns the flow names in sorted order.
+func (e *Engine) Flows() []string {
+	names := make([]string, 0, len(e.cfg.Flows))
+	for name := range e.cfg.Flows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Steps returns the steps of a flow in order, including disabled ones.
+func (e *Engine) Steps(flow string) []Step {
+	return e.cfg.Flows[flow].Steps
+}
+
+// Feature returns the value of a feature toggle.
+func (e *Engine) Feature(name string) interface{} {
+	return e.cfg.Features[name]
+}
+
+// FeatureEnabled reports whether a feature toggle is set, that is not
+// missing, false, zero or empty.
+func (e *Engine) FeatureEnabled(name string) bool {
+	return truthy(e.cfg.Features[name])
+}
+
+// FeatureInt returns a numeric feature toggle, or zero if it is missing
+// or not a number.
+func (e *Engine) FeatureInt(name string) int {
+	n, _ := number(e.cfg.Features[name])
+	return int(n)
+}
+
+// Step returns a step of a flow.
+func (e *Engine) Step(flow, name string) (Step, bool) {
+	f, ok := e.cfg.Flows[flow]
+	if !ok {
+		return Step{}, false
+	}
+	return f.step(name)
+}
+
+// Enabled reports whether the enabled_if condition of a step holds. Steps
+// without a condition are always enabled, steps with an invalid one never.
+func (e *Engine) Enabled(step Step) bool {
+	if step.EnabledIf == "" {
+		return true
+	}
+	c, ok := e.conditions[step.EnabledIf]
+	if !ok {
+		var err error
+		if c, err = parseCondition(step.EnabledIf); err != nil {
+			return false
+		}
+	}
+	return c.eval(e.cfg.Features)
+}
+
+// Start returns the first enabled step of a flow.
+func (e *Engine) Start(flow string) (Step, bool) {
+	steps := e.Steps(flow)
+	if len(steps) == 0 {
+		return Step{}, false
+	}
+	return e.resolve(flow, steps[0])
+}
+
+// Next returns the step following from after the given result. Disabled
+// steps are passed through by their "ok" transition. It returns false if
+// the result has no transition, or names a step outside the flow.
+func (e *Engine) Next(flow, from, result string) (Step, bool) {
+	current, ok := e.Step(flow, from)
+	if !ok {
+		return Step{}, false
+	}
+	next, ok := e.Step(flow, current.Next[result])
+	if !ok {
+		return Step{}, false
+	}
+	return e.resolve(flow, next)
+}
+
+// resolve follows "ok" transitions from step until an enabled step.
+func (e *Engine) resolve(flow string, step Step) (Step, bool) {
+	// Each step is visited once at most, so a cycle of disabled
+	// steps can't loop forever.
+	for range e.Steps(flow) {
+		if e.Enabled(step) {
+			return step, true
+		}
+		next, ok := e.Step(flow, step.Next["ok"])
+		if !ok {
+			return Step{}, false
+		}
+		step = next
+	}
+	return Step{}, false
+}
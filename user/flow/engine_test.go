@@ -0,0 +1,174 @@
+package flow_test
+
+import (
+	"testing"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/flow"
+)
+
+const testFlows = `
+features:
+  mfa: false
+  expiry_days: 30
+  mode: strict
+
+flows:
+  login:
+    steps:
+      - name: login
+        view: login.vuego
+        link: /login
+        next:
+          ok: check_mfa
+          error: login_error
+      - name: check_mfa
+        enabled_if: features.mfa
+        view: mfa_challenge.vuego
+        link: /mfa
+        next:
+          ok: expired
+      - name: expired
+        enabled_if: features.expiry_days > 0
+        view: expired.vuego
+        link: /expired
+        next:
+          ok: success
+      - name: success
+        link: /login/success
+`
+
+func newEngine(t *testing.T, yaml string) *flow.Engine {
+	t.Helper()
+
+	cfg, err := flow.Parse([]byte(yaml))
+	require.NoError(t, err)
+	engine, err := flow.New(cfg)
+	require.NoError(t, err)
+	return engine
+}
+
+func TestEngine(t *testing.T) {
+	engine := newEngine(t, testFlows)
+
+	t.Run("start", func(t *testing.T) {
+		step, ok := engine.Start("login")
+		require.True(t, ok)
+		require.Equal(t, "/login", step.Link)
+
+		_, ok = engine.Start("unknown")
+		require.False(t, ok)
+	})
+
+	t.Run("next skips disabled steps", func(t *testing.T) {
+		step, ok := engine.Next("login", "login", "ok")
+		require.True(t, ok)
+		require.Equal(t, "expired", step.Name)
+		require.Equal(t, "/expired", step.Link)
+
+		step, ok = engine.Next("login", "expired", "ok")
+		require.True(t, ok)
+		require.Equal(t, "success", step.Name)
+	})
+
+	t.Run("transitions outside the flow", func(t *testing.T) {
+		_, ok := engine.Next("login", "login", "error")
+		require.False(t, ok)
+
+		_, ok = engine.Next("login", "success", "ok")
+		require.False(t, ok)
+	})
+
+	t.Run("features", func(t *testing.T) {
+		require.False(t, engine.FeatureEnabled("mfa"))
+		require.True(t, engine.FeatureEnabled("expiry_days"))
+		require.False(t, engine.FeatureEnabled("missing"))
+		require.Equal(t, 30, engine.Feature("expiry_days"))
+		require.Equal(t, 30, engine.FeatureInt("expiry_days"))
+		require.Equal(t, 0, engine.FeatureInt("mode"))
+	})
+}
+
+func TestEnabledIf(t *testing.T) {
+	engine := newEngine(t, testFlows)
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"features.mfa", false},
+		{"features.expiry_days", true},
+		{"features.expiry_days > 0", true},
+		{"features.expiry_days >= 30", true},
+		{"features.expiry_days < 30", false},
+		{"features.expiry_days == 30", true},
+		{"features.expiry_days != 30", false},
+		{"features.mfa == false", true},
+		{`features.mode == "strict"`, true},
+		{`features.mode != "strict"`, false},
+		{"features.missing", false},
+		{"features.missing > 0", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			require.Equal(t, tc.want, engine.Enabled(flow.Step{EnabledIf: tc.expr}))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := map[string]string{
+		"invalid condition": `
+flows:
+  login:
+    steps:
+      - name: login
+        link: /login
+        enabled_if: mfa
+`,
+		"comparison needs a number": `
+flows:
+  login:
+    steps:
+      - name: login
+        link: /login
+        enabled_if: features.mode > "strict"
+`,
+		"duplicate step": `
+flows:
+  login:
+    steps:
+      - name: login
+        link: /login
+      - name: login
+        link: /login
+`,
+		"missing link": `
+flows:
+  login:
+    steps:
+      - name: login
+`,
+		"unknown ok step": `
+flows:
+  login:
+    steps:
+      - name: login
+        link: /login
+        next:
+          ok: missing
+`,
+	}
+
+	for name, yaml := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := flow.Parse([]byte(yaml))
+			require.NoError(t, err)
+
+			_, err = flow.New(cfg)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package flow
+
+import (
+	"fmt"
+	"os"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Config is the flows.yml configuration: feature toggles and the steps
+// of each user flow.
+type Config struct {
+	Features map[string]interface{} `yaml:"features"`
+	Flows    map[string]Flow        `yaml:"flows"`
+}
+
+// Flow is an ordered list of steps. The first step is where the flow
+// starts.
+type Flow struct {
+	Steps []Step `yaml:"steps"`
+}
+
+// Step is a page of a flow. Next maps a result, usually "ok" or "error",
+// to the name of the following step. A step without Next ends the flow.
+type Step struct {
+	Name      string            `yaml:"name"`
+	View      string            `yaml:"view"`
+	Link      string            `yaml:"link"`
+	EnabledIf string            `yaml:"enabled_if"`
+	Next      map[string]string `yaml:"next"`
+}
+
+// Parse decodes a flows.yml configuration.
+func Parse(data []byte) (*Config, error) {
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse flows: %w", err)
+	}
+	return cfg, nil
+}
+
+// Load reads and decodes a flows.yml configuration from path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// step returns the named step of the flow.
+func (f Flow) step(name string) (Step, bool) {
+	for _, step := range f.Steps {
+		if step.Name == name {
+			return step, true
+		}
+	}
+	return Step{}, false
+}
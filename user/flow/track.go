@@ -0,0 +1,53 @@
+package flow
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/titpetric/platform/pkg/ulid"
+)
+
+// CookieName holds the token of the current flow step of the user. The
+// step itself is kept in flow storage.
+const CookieName = "flow_token"
+
+// TTL is how long the current flow step is kept. It covers the lifetime
+// of the emailed reset and verification links.
+const TTL = 24 * time.Hour
+
+// Tracker stores the current step of a flow token. The flow storage
+// implements it.
+type Tracker interface {
+	Set(ctx context.Context, token, flow, step string, ttl time.Duration) error
+}
+
+// Token returns the token from the flow cookie, if any.
+func Token(r *http.Request) string {
+	cookie, err := r.Cookie(CookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// Track stores step of flow as the current step of the user, and sets
+// the flow cookie. A new token is issued if the request has none.
+func Track(w http.ResponseWriter, r *http.Request, tracker Tracker, flow string, step Step) error {
+	token := Token(r)
+	if token == "" {
+		token = ulid.String()
+	}
+	err := tracker.Set(r.Context(), token, flow, step.Name, TTL)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     CookieName,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   int(TTL / time.Second),
+	})
+	return err
+}
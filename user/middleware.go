@@ -2,13 +2,19 @@ package user
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/titpetric/oida"
+	"github.com/titpetric/platform"
 
+	"github.com/titpetric/platform-app/user/flow"
 	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/opa"
 	"github.com/titpetric/platform-app/user/service/auth"
+	"github.com/titpetric/platform-app/user/service/policy"
 	"github.com/titpetric/platform-app/user/storage"
 )
 
@@ -26,6 +32,21 @@ type Middleware struct {
 	userStorage    *storage.UserStorage
 	sessionStorage *storage.SessionStorage
 	revokedStorage *storage.RevokedTokenStorage
+	tokenStorage   *storage.TokenStorage
+	flowStorage    flow.Tracker
+	policy         *policy.Service
+}
+
+// policyError is returned for an authenticated user with a pending
+// policy step, like an expired password. Cookie clients are redirected
+// to the step, token clients get an error code.
+type policyError struct {
+	step     flow.Step
+	redirect bool
+}
+
+func (e *policyError) Error() string {
+	return policy.Error(e.step).Error()
 }
 
 // ServeHTTP authenticates the request and passes it to the next handler.
@@ -33,6 +54,11 @@ func (m *Middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	m.init(r.Context())
 
 	if err := m.serveHTTP(w, r); err != nil {
+		var perr *policyError
+		if errors.As(err, &perr) {
+			m.enforce(w, r, perr)
+			return
+		}
 		if !m.options.Optional {
 			oida.RecordError(r.Context(), err)
 			return
@@ -45,8 +71,8 @@ func (m *Middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (m *Middleware) serveHTTP(w http.ResponseWriter, r *http.Request) error {
 	if m.options.Header {
 		err := m.authorizeJWT(w, r)
-		if err == nil {
-			return nil
+		if err == nil || isPolicyError(err) {
+			return err
 		}
 		if !m.options.Cookie && !m.options.Query {
 			return err
@@ -55,8 +81,8 @@ func (m *Middleware) serveHTTP(w http.ResponseWriter, r *http.Request) error {
 
 	if m.options.Query {
 		err := m.authorizeQuery(w, r)
-		if err == nil {
-			return nil
+		if err == nil || isPolicyError(err) {
+			return err
 		}
 		if !m.options.Cookie {
 			return err
@@ -100,6 +126,27 @@ func (m *Middleware) authorizeCookie(w http.ResponseWriter, r *http.Request) err
 		return ErrLoginRequired
 	}
 
+	if err := m.checkPolicy(r, user.ID, true); err != nil {
+		return err
+	}
+
+	// Slide the session expiry forward. Best-effort, the session is
+	// valid either way. The cookie is issued again with the new expiry,
+	// so the browser doesn't drop it before the session ends.
+	expiresAt := *session.ExpiresAt
+	if err := m.sessionStorage.Touch(ctx, session); err != nil {
+		oida.RecordError(ctx, err)
+	} else if !session.ExpiresAt.Equal(expiresAt) {
+		http.SetCookie(w, &http.Cookie{
+			Name:     m.options.CookieName,
+			Value:    cookie.Value,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   true,
+			Expires:  *session.ExpiresAt,
+		})
+	}
+
 	sessionIDContext.Set(r, cookie.Value)
 	sessionContext.Set(r, session)
 	return nil
@@ -140,12 +187,76 @@ func (m *Middleware) authorizeToken(w http.ResponseWriter, r *http.Request, toke
 		}
 	}
 
+	// Reject tokens issued before the user revoked all of their
+	// tokens, e.g. by resetting their password.
+	if m.revokedStorage != nil {
+		revoked, rerr := m.revokedStorage.IsUserRevoked(r.Context(), claims.UserID, claims.JTI, time.Unix(claims.IssuedAt, 0))
+		if rerr != nil {
+			return rerr
+		}
+		if revoked {
+			return ErrLoginRequired
+		}
+	}
+
 	if _, err := m.authorizeUser(w, r, claims.UserID); err != nil {
 		return err
 	}
+	if err := m.checkPolicy(r, claims.UserID, false); err != nil {
+		return err
+	}
+
+	// Best-effort, the last use of a token is informational.
+	if err := m.tokenStorage.Touch(r.Context(), claims.JTI); err != nil {
+		oida.RecordError(r.Context(), err)
+	}
 	return nil
 }
 
+// checkPolicy returns a policyError if the user has a pending policy
+// step. Redirect is set for cookie clients.
+func (m *Middleware) checkPolicy(r *http.Request, userID string, redirect bool) error {
+	if m.policy == nil {
+		return nil
+	}
+
+	step, ok, err := m.policy.Check(r.Context(), userID)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return &policyError{step: step, redirect: redirect}
+	}
+	return nil
+}
+
+// enforce redirects cookie clients to the pending policy step, and
+// responds with 403 and an error code naming the step to token clients.
+// The step is tracked as the current flow step, so the flow pages
+// serve it.
+func (m *Middleware) enforce(w http.ResponseWriter, r *http.Request, perr *policyError) {
+	if perr.redirect {
+		if m.flowStorage != nil {
+			if err := flow.Track(w, r, m.flowStorage, policy.Flow, perr.step); err != nil {
+				oida.RecordError(r.Context(), err)
+			}
+		}
+		http.Redirect(w, r, perr.step.Link, http.StatusSeeOther)
+		return
+	}
+	platform.JSON(w, r, http.StatusForbidden, map[string]string{
+		"error": perr.Error(),
+		"code":  perr.step.Name,
+		"link":  perr.step.Link,
+	})
+}
+
+// isPolicyError reports whether err is a policyError.
+func isPolicyError(err error) bool {
+	var perr *policyError
+	return errors.As(err, &perr)
+}
+
 func (m *Middleware) authorizeUser(w http.ResponseWriter, r *http.Request, userID string) (*model.User, error) {
 	ctx := r.Context()
 	user, err := m.userStorage.Get(ctx, userID)
@@ -173,6 +284,18 @@ func (m *Middleware) init(ctx context.Context) error {
 		m.userStorage = storage.NewUserStorage(db)
 		m.sessionStorage = storage.NewSessionStorage(db)
 		m.revokedStorage = storage.NewRevokedTokenStorage(db)
+		m.tokenStorage = storage.NewTokenStorage(db)
+		m.flowStorage = storage.NewFlowStorage(db)
+
+		if !PoliciesEnabled() {
+			return
+		}
+		flows, err := opa.Flows()
+		if err != nil {
+			resultErr = err
+			return
+		}
+		m.policy = policy.New(m.userStorage, flows, policy.Options{})
 	})
 	return resultErr
 }
@@ -0,0 +1,82 @@
+//go:build integration
+
+package user
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/titpetric/platform/pkg/drivers"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/schema"
+	"github.com/titpetric/platform-app/user/storage"
+)
+
+func TestMiddlewareSlidesSessionCookie_integration(t *testing.T) {
+	ctx := t.Context()
+
+	db, err := sqlx.Connect("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	require.NoError(t, storage.Migrate(ctx, db, schema.Migrations()))
+
+	userStorage := storage.NewUserStorage(db)
+	sessionStorage := storage.NewSessionStorage(db)
+	sessionStorage.SetTTL(time.Hour)
+
+	user, err := userStorage.Create(ctx, &model.UserCreateRequest{
+		FullName: "John Doe",
+		Username: "johndoe",
+		Email:    "john@example.com",
+		Password: "secret123",
+	})
+	require.NoError(t, err)
+
+	session, err := sessionStorage.Create(ctx, &model.UserSession{UserID: user.ID})
+	require.NoError(t, err)
+
+	mw := NewMiddleware(AuthCookie())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})).(*Middleware)
+	mw.once.Do(func() {})
+	mw.userStorage = userStorage
+	mw.sessionStorage = sessionStorage
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(&http.Cookie{Name: "session_id", Value: session.ID})
+		w := httptest.NewRecorder()
+		mw.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("recent use keeps the cookie", func(t *testing.T) {
+		w := serve()
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("touch issues the cookie again", func(t *testing.T) {
+		lastSeen := time.Now().Add(-10 * time.Minute)
+		_, err := db.ExecContext(ctx, `UPDATE user_session SET last_seen_at = ?, expires_at = ? WHERE id = ?`, lastSeen, lastSeen.Add(time.Hour), session.ID)
+		require.NoError(t, err)
+
+		w := serve()
+		require.Equal(t, http.StatusNoContent, w.Code)
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Equal(t, "session_id", cookies[0].Name)
+		require.Equal(t, session.ID, cookies[0].Value)
+		require.True(t, cookies[0].HttpOnly)
+		require.True(t, cookies[0].Expires.After(time.Now().Add(55*time.Minute)))
+	})
+}
@@ -0,0 +1,48 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/flow"
+	"github.com/titpetric/platform-app/user/service/policy"
+)
+
+type flowSteps map[string][2]string
+
+func (s flowSteps) Set(_ context.Context, token, flow, step string, _ time.Duration) error {
+	s[token] = [2]string{flow, step}
+	return nil
+}
+
+func TestMiddlewareEnforceTracksStep(t *testing.T) {
+	steps := flowSteps{}
+	m := &Middleware{flowStorage: steps}
+	step := flow.Step{Name: policy.EmailReverify, Link: "/verify-email"}
+
+	t.Run("cookie clients are redirected to the tracked step", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		m.enforce(w, httptest.NewRequest(http.MethodGet, "/pulse", nil), &policyError{step: step, redirect: true})
+
+		require.Equal(t, http.StatusSeeOther, w.Code)
+		require.Equal(t, "/verify-email", w.Header().Get("Location"))
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Equal(t, flow.CookieName, cookies[0].Name)
+		require.Equal(t, [2]string{policy.Flow, policy.EmailReverify}, steps[cookies[0].Value])
+	})
+
+	t.Run("token clients get an error code", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		m.enforce(w, httptest.NewRequest(http.MethodGet, "/api/pulse/stats", nil), &policyError{step: step})
+
+		require.Equal(t, http.StatusForbidden, w.Code)
+		require.Empty(t, w.Result().Cookies())
+	})
+}
@@ -10,6 +10,7 @@ var (
 	ErrUsernameInvalid   = errors.New("username must contain only lowercase letters, numbers, underscores and dashes, and must not begin or end with underscore or dash")
 	ErrUsernameMaxLength = errors.New("username must be 20 characters or less")
 	ErrUsernameTaken     = errors.New("username is already taken")
+	ErrUsernameReserved  = errors.New("username is reserved")
 
 	// ErrInvalidActivationToken is returned by UserStorage.Activate when
 	// the supplied token does not match any pending activation row.
@@ -23,4 +24,59 @@ var (
 	// EmailActivationEnabled is on and a user attempts to log in or
 	// otherwise act on an account that has not been activated yet.
 	ErrUserNotActivated = errors.New("user has not activated their account")
+
+	// ErrInvalidResetToken is returned by UserStorage.ResetPassword when
+	// the supplied token is unknown, expired or already used.
+	ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+	// ErrPasswordMissing is returned when a new password is empty.
+	ErrPasswordMissing = errors.New("password is required")
+
+	// ErrEmailMissing is returned when a password reset is requested
+	// without an email.
+	ErrEmailMissing = errors.New("email is required")
+
+	// ErrMFAAlreadyEnabled is returned when enrolling a user that has
+	// MFA enabled already.
+	ErrMFAAlreadyEnabled = errors.New("multi-factor authentication is already enabled")
+
+	// ErrMFANotEnrolled is returned when enabling MFA without a pending
+	// enrollment.
+	ErrMFANotEnrolled = errors.New("multi-factor authentication is not enrolled")
+
+	// ErrInvalidMFACode is returned when a TOTP or recovery code is
+	// wrong or was already used.
+	ErrInvalidMFACode = errors.New("invalid authentication code")
+
+	// ErrMFAPendingExpired is returned when the second login step is
+	// attempted with an unknown or expired MFA pending token.
+	ErrMFAPendingExpired = errors.New("login expired, please login again")
+
+	// ErrPasswordIncorrect is returned when changing a password with a
+	// wrong current password.
+	ErrPasswordIncorrect = errors.New("current password is incorrect")
+
+	// ErrInvalidVerifyToken is returned by UserStorage.VerifyEmail when
+	// the supplied token is unknown, expired or already used.
+	ErrInvalidVerifyToken = errors.New("invalid or expired email verification token")
+
+	// ErrPasswordExpired is returned for users whose password is older
+	// than the password_expiry_days feature allows.
+	ErrPasswordExpired = errors.New("password expired")
+
+	// ErrEmailReverifyRequired is returned for users who didn't verify
+	// their email within the email_reverify_days feature.
+	ErrEmailReverifyRequired = errors.New("email verification required")
+
+	// ErrInvalidTimezone is returned when a profile timezone is not a
+	// known IANA timezone name.
+	ErrInvalidTimezone = errors.New("invalid timezone")
+
+	// ErrSessionNotFound is returned when naming or revoking a session
+	// or token the user doesn't have.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrSessionNameTooLong is returned when naming a session or token
+	// with more than 64 characters.
+	ErrSessionNameTooLong = errors.New("session name is too long")
 )
@@ -2,13 +2,31 @@ package model
 
 import (
 	"context"
+	"time"
 )
 
 // SessionStorage defines the storage operations for user sessions.
 type SessionStorage interface {
-	Create(ctx context.Context, userID string) (*UserSession, error)
+	Create(ctx context.Context, session *UserSession) (*UserSession, error)
 	Get(ctx context.Context, sessionID string) (*UserSession, error)
+	Touch(ctx context.Context, session *UserSession) error
 	Delete(ctx context.Context, sessionID string) error
+
+	List(ctx context.Context, userID string) ([]UserSession, error)
+	Rename(ctx context.Context, userID, sessionID, name string) error
+	Revoke(ctx context.Context, userID, sessionID string) error
+	RevokeAll(ctx context.Context, userID, except string) (int64, error)
+}
+
+// TokenStorage defines the storage operations for issued JWTs.
+type TokenStorage interface {
+	Create(ctx context.Context, token *UserToken) (*UserToken, error)
+	Touch(ctx context.Context, jti string) error
+
+	List(ctx context.Context, userID string) ([]UserToken, error)
+	Rename(ctx context.Context, userID, jti, name string) error
+	Revoke(ctx context.Context, userID, jti string) error
+	RevokeAll(ctx context.Context, userID, except string) (int64, error)
 }
 
 // UserStorage defines the storage operations for users.
@@ -30,6 +48,59 @@ type UserStorage interface {
 	ResetActivation(ctx context.Context, email string) error
 }
 
+// PasswordResetStorage defines the storage operations for password resets.
+type PasswordResetStorage interface {
+	CreatePasswordReset(ctx context.Context, email string, ttl time.Duration) (token string, userID string, err error)
+	CheckPasswordReset(ctx context.Context, token string) error
+	ResetPassword(ctx context.Context, token, password string) (*User, error)
+}
+
+// PolicyStorage defines the storage operations for password expiry and
+// email re-verification.
+type PolicyStorage interface {
+	GetPolicy(ctx context.Context, userID string) (*UserAuth, error)
+	ChangePassword(ctx context.Context, userID, current, password string) error
+	CreateEmailVerification(ctx context.Context, userID string, ttl time.Duration) (token string, email string, err error)
+	CheckEmailVerification(ctx context.Context, token string) error
+	VerifyEmail(ctx context.Context, token string) (*User, error)
+}
+
+// MFAStorage defines the storage operations for TOTP multi-factor
+// authentication.
+type MFAStorage interface {
+	Get(ctx context.Context, userID string) (*UserMFA, error)
+	Enroll(ctx context.Context, userID, secret string) error
+	Enable(ctx context.Context, userID string, step int64, recoveryCodes []string) error
+	Disable(ctx context.Context, userID string) error
+	UseStep(ctx context.Context, userID string, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID, code string) (bool, error)
+	CountRecoveryCodes(ctx context.Context, userID string) (int, error)
+
+	CreatePending(ctx context.Context, token, userID string, ttl time.Duration) error
+	GetPending(ctx context.Context, token string) (*UserMFAPending, error)
+	AttemptPending(ctx context.Context, token string, maxAttempts int) (*UserMFAPending, error)
+	DeletePending(ctx context.Context, token string) (bool, error)
+}
+
+// FlowStorage defines the storage operations for the current flow step
+// of users.
+type FlowStorage interface {
+	Get(ctx context.Context, token string) (*UserFlow, error)
+	Set(ctx context.Context, token, flow, step string, ttl time.Duration) error
+	Delete(ctx context.Context, token string) error
+}
+
+// GroupStorage defines the storage operations for user groups.
+type GroupStorage interface {
+	Create(ctx context.Context, title string) (*UserGroup, error)
+	Get(ctx context.Context, id string) (*UserGroup, error)
+	List(ctx context.Context) ([]UserGroup, error)
+
+	AddMember(ctx context.Context, groupID, userID string) error
+	RemoveMember(ctx context.Context, groupID, userID string) error
+	ListMembers(ctx context.Context, groupID string) ([]string, error)
+}
+
 // PasskeyStorage defines the storage operations for WebAuthn passkeys.
 type PasskeyStorage interface {
 	Create(ctx context.Context, passkey *UserPasskey) (*UserPasskey, error)
@@ -194,6 +194,9 @@ type User struct {
 
 	// Updated At
 	UpdatedAt *time.Time `db:"updated_at" json:"updated_at"`
+
+	// Timezone
+	Timezone string `db:"timezone" json:"timezone"`
 }
 
 // GetID will return the value of ID.
@@ -238,11 +241,17 @@ func (u *User) GetUpdatedAt() *time.Time { return u.UpdatedAt }
 // SetUpdatedAt sets UpdatedAt to the provided value.
 func (u *User) SetUpdatedAt(stamp time.Time) { u.UpdatedAt = &stamp }
 
+// GetTimezone will return the value of Timezone.
+func (u *User) GetTimezone() string { return u.Timezone }
+
+// SetTimezone sets Timezone to the provided value.
+func (u *User) SetTimezone(val string) { u.Timezone = val }
+
 // UserTable is the name of the table in the DB.
 const UserTable = "`user`"
 
 // UserFields is a list of all columns in the DB table.
-var UserFields = []string{"id", "full_name", "username", "slug", "deleted_at", "created_at", "updated_at"}
+var UserFields = []string{"id", "full_name", "username", "slug", "deleted_at", "created_at", "updated_at", "timezone"}
 
 // UserPrimaryFields are the primary key fields in the DB table.
 var UserPrimaryFields = []string{"id"}
@@ -274,6 +283,27 @@ type UserAuth struct {
 
 	// Activation Sent At
 	ActivationSentAt *time.Time `db:"activation_sent_at" json:"activation_sent_at"`
+
+	// Tokens Revoked At
+	TokensRevokedAt *time.Time `db:"tokens_revoked_at" json:"tokens_revoked_at"`
+
+	// Password Changed At
+	PasswordChangedAt *time.Time `db:"password_changed_at" json:"password_changed_at"`
+
+	// Email Verified At
+	EmailVerifiedAt *time.Time `db:"email_verified_at" json:"email_verified_at"`
+
+	// Email Verify Token
+	EmailVerifyToken string `db:"email_verify_token" json:"email_verify_token"`
+
+	// Email Verify Expires At
+	EmailVerifyExpiresAt *time.Time `db:"email_verify_expires_at" json:"email_verify_expires_at"`
+
+	// Policy Exempt
+	PolicyExempt int64 `db:"policy_exempt" json:"policy_exempt"`
+
+	// Tokens Revoked Except
+	TokensRevokedExcept string `db:"tokens_revoked_except" json:"tokens_revoked_except"`
 }
 
 // GetUserID will return the value of UserID.
@@ -324,15 +354,116 @@ func (u *UserAuth) GetActivationSentAt() *time.Time { return u.ActivationSentAt
 // SetActivationSentAt sets ActivationSentAt to the provided value.
 func (u *UserAuth) SetActivationSentAt(stamp time.Time) { u.ActivationSentAt = &stamp }
 
+// GetTokensRevokedAt will return the value of TokensRevokedAt.
+func (u *UserAuth) GetTokensRevokedAt() *time.Time { return u.TokensRevokedAt }
+
+// SetTokensRevokedAt sets TokensRevokedAt to the provided value.
+func (u *UserAuth) SetTokensRevokedAt(stamp time.Time) { u.TokensRevokedAt = &stamp }
+
+// GetPasswordChangedAt will return the value of PasswordChangedAt.
+func (u *UserAuth) GetPasswordChangedAt() *time.Time { return u.PasswordChangedAt }
+
+// SetPasswordChangedAt sets PasswordChangedAt to the provided value.
+func (u *UserAuth) SetPasswordChangedAt(stamp time.Time) { u.PasswordChangedAt = &stamp }
+
+// GetEmailVerifiedAt will return the value of EmailVerifiedAt.
+func (u *UserAuth) GetEmailVerifiedAt() *time.Time { return u.EmailVerifiedAt }
+
+// SetEmailVerifiedAt sets EmailVerifiedAt to the provided value.
+func (u *UserAuth) SetEmailVerifiedAt(stamp time.Time) { u.EmailVerifiedAt = &stamp }
+
+// GetEmailVerifyToken will return the value of EmailVerifyToken.
+func (u *UserAuth) GetEmailVerifyToken() string { return u.EmailVerifyToken }
+
+// SetEmailVerifyToken sets EmailVerifyToken to the provided value.
+func (u *UserAuth) SetEmailVerifyToken(val string) { u.EmailVerifyToken = val }
+
+// GetEmailVerifyExpiresAt will return the value of EmailVerifyExpiresAt.
+func (u *UserAuth) GetEmailVerifyExpiresAt() *time.Time { return u.EmailVerifyExpiresAt }
+
+// SetEmailVerifyExpiresAt sets EmailVerifyExpiresAt to the provided value.
+func (u *UserAuth) SetEmailVerifyExpiresAt(stamp time.Time) { u.EmailVerifyExpiresAt = &stamp }
+
+// GetPolicyExempt will return the value of PolicyExempt.
+func (u *UserAuth) GetPolicyExempt() int64 { return u.PolicyExempt }
+
+// SetPolicyExempt sets PolicyExempt to the provided value.
+func (u *UserAuth) SetPolicyExempt(val int64) { u.PolicyExempt = val }
+
+// GetTokensRevokedExcept will return the value of TokensRevokedExcept.
+func (u *UserAuth) GetTokensRevokedExcept() string { return u.TokensRevokedExcept }
+
+// SetTokensRevokedExcept sets TokensRevokedExcept to the provided value.
+func (u *UserAuth) SetTokensRevokedExcept(val string) { u.TokensRevokedExcept = val }
+
 // UserAuthTable is the name of the table in the DB.
 const UserAuthTable = "`user_auth`"
 
 // UserAuthFields is a list of all columns in the DB table.
-var UserAuthFields = []string{"user_id", "email", "password", "created_at", "updated_at", "activated_at", "activation_token", "activation_sent_at"}
+var UserAuthFields = []string{"user_id", "email", "password", "created_at", "updated_at", "activated_at", "activation_token", "activation_sent_at", "tokens_revoked_at", "password_changed_at", "email_verified_at", "email_verify_token", "email_verify_expires_at", "policy_exempt", "tokens_revoked_except"}
 
 // UserAuthPrimaryFields are the primary key fields in the DB table.
 var UserAuthPrimaryFields = []string{"user_id"}
 
+// UserFlow generated for db table `user_flow`.
+//
+// User Flow.
+type UserFlow struct {
+	// Token Hash
+	TokenHash string `db:"token_hash" json:"token_hash"`
+
+	// Flow
+	Flow string `db:"flow" json:"flow"`
+
+	// Step
+	Step string `db:"step" json:"step"`
+
+	// Expires At
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at"`
+
+	// Updated At
+	UpdatedAt *time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// GetTokenHash will return the value of TokenHash.
+func (u *UserFlow) GetTokenHash() string { return u.TokenHash }
+
+// SetTokenHash sets TokenHash to the provided value.
+func (u *UserFlow) SetTokenHash(val string) { u.TokenHash = val }
+
+// GetFlow will return the value of Flow.
+func (u *UserFlow) GetFlow() string { return u.Flow }
+
+// SetFlow sets Flow to the provided value.
+func (u *UserFlow) SetFlow(val string) { u.Flow = val }
+
+// GetStep will return the value of Step.
+func (u *UserFlow) GetStep() string { return u.Step }
+
+// SetStep sets Step to the provided value.
+func (u *UserFlow) SetStep(val string) { u.Step = val }
+
+// GetExpiresAt will return the value of ExpiresAt.
+func (u *UserFlow) GetExpiresAt() *time.Time { return u.ExpiresAt }
+
+// SetExpiresAt sets ExpiresAt to the provided value.
+func (u *UserFlow) SetExpiresAt(stamp time.Time) { u.ExpiresAt = &stamp }
+
+// GetUpdatedAt will return the value of UpdatedAt.
+func (u *UserFlow) GetUpdatedAt() *time.Time { return u.UpdatedAt }
+
+// SetUpdatedAt sets UpdatedAt to the provided value.
+func (u *UserFlow) SetUpdatedAt(stamp time.Time) { u.UpdatedAt = &stamp }
+
+// UserFlowTable is the name of the table in the DB.
+const UserFlowTable = "`user_flow`"
+
+// UserFlowFields is a list of all columns in the DB table.
+var UserFlowFields = []string{"token_hash", "flow", "step", "expires_at", "updated_at"}
+
+// UserFlowPrimaryFields are the primary key fields in the DB table.
+var UserFlowPrimaryFields = []string{"token_hash"}
+
 // UserGroup generated for db table `user_group`.
 //
 // User Group.
@@ -424,6 +555,174 @@ var UserGroupMemberFields = []string{"user_group_id", "user_id", "joined_at"}
 // UserGroupMemberPrimaryFields are the primary key fields in the DB table.
 var UserGroupMemberPrimaryFields = []string{"user_group_id", "user_id"}
 
+// UserMFA generated for db table `user_mfa`.
+//
+// User Mfa.
+type UserMFA struct {
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Secret
+	Secret string `db:"secret" json:"secret"`
+
+	// Last Step
+	LastStep int64 `db:"last_step" json:"last_step"`
+
+	// Enabled At
+	EnabledAt *time.Time `db:"enabled_at" json:"enabled_at"`
+
+	// Created At
+	CreatedAt *time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetUserID will return the value of UserID.
+func (u *UserMFA) GetUserID() string { return u.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (u *UserMFA) SetUserID(val string) { u.UserID = val }
+
+// GetSecret will return the value of Secret.
+func (u *UserMFA) GetSecret() string { return u.Secret }
+
+// SetSecret sets Secret to the provided value.
+func (u *UserMFA) SetSecret(val string) { u.Secret = val }
+
+// GetLastStep will return the value of LastStep.
+func (u *UserMFA) GetLastStep() int64 { return u.LastStep }
+
+// SetLastStep sets LastStep to the provided value.
+func (u *UserMFA) SetLastStep(val int64) { u.LastStep = val }
+
+// GetEnabledAt will return the value of EnabledAt.
+func (u *UserMFA) GetEnabledAt() *time.Time { return u.EnabledAt }
+
+// SetEnabledAt sets EnabledAt to the provided value.
+func (u *UserMFA) SetEnabledAt(stamp time.Time) { u.EnabledAt = &stamp }
+
+// GetCreatedAt will return the value of CreatedAt.
+func (u *UserMFA) GetCreatedAt() *time.Time { return u.CreatedAt }
+
+// SetCreatedAt sets CreatedAt to the provided value.
+func (u *UserMFA) SetCreatedAt(stamp time.Time) { u.CreatedAt = &stamp }
+
+// UserMFATable is the name of the table in the DB.
+const UserMFATable = "`user_mfa`"
+
+// UserMFAFields is a list of all columns in the DB table.
+var UserMFAFields = []string{"user_id", "secret", "last_step", "enabled_at", "created_at"}
+
+// UserMFAPrimaryFields are the primary key fields in the DB table.
+var UserMFAPrimaryFields = []string{"user_id"}
+
+// UserMFAPending generated for db table `user_mfa_pending`.
+//
+// User Mfa Pending.
+type UserMFAPending struct {
+	// Token Hash
+	TokenHash string `db:"token_hash" json:"token_hash"`
+
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Attempts
+	Attempts int64 `db:"attempts" json:"attempts"`
+
+	// Expires At
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at"`
+
+	// Created At
+	CreatedAt *time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetTokenHash will return the value of TokenHash.
+func (u *UserMFAPending) GetTokenHash() string { return u.TokenHash }
+
+// SetTokenHash sets TokenHash to the provided value.
+func (u *UserMFAPending) SetTokenHash(val string) { u.TokenHash = val }
+
+// GetUserID will return the value of UserID.
+func (u *UserMFAPending) GetUserID() string { return u.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (u *UserMFAPending) SetUserID(val string) { u.UserID = val }
+
+// GetAttempts will return the value of Attempts.
+func (u *UserMFAPending) GetAttempts() int64 { return u.Attempts }
+
+// SetAttempts sets Attempts to the provided value.
+func (u *UserMFAPending) SetAttempts(val int64) { u.Attempts = val }
+
+// GetExpiresAt will return the value of ExpiresAt.
+func (u *UserMFAPending) GetExpiresAt() *time.Time { return u.ExpiresAt }
+
+// SetExpiresAt sets ExpiresAt to the provided value.
+func (u *UserMFAPending) SetExpiresAt(stamp time.Time) { u.ExpiresAt = &stamp }
+
+// GetCreatedAt will return the value of CreatedAt.
+func (u *UserMFAPending) GetCreatedAt() *time.Time { return u.CreatedAt }
+
+// SetCreatedAt sets CreatedAt to the provided value.
+func (u *UserMFAPending) SetCreatedAt(stamp time.Time) { u.CreatedAt = &stamp }
+
+// UserMFAPendingTable is the name of the table in the DB.
+const UserMFAPendingTable = "`user_mfa_pending`"
+
+// UserMFAPendingFields is a list of all columns in the DB table.
+var UserMFAPendingFields = []string{"token_hash", "user_id", "attempts", "expires_at", "created_at"}
+
+// UserMFAPendingPrimaryFields are the primary key fields in the DB table.
+var UserMFAPendingPrimaryFields = []string{"token_hash"}
+
+// UserMFARecovery generated for db table `user_mfa_recovery`.
+//
+// User Mfa Recovery.
+type UserMFARecovery struct {
+	// Code Hash
+	CodeHash string `db:"code_hash" json:"code_hash"`
+
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Used At
+	UsedAt *time.Time `db:"used_at" json:"used_at"`
+
+	// Created At
+	CreatedAt *time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetCodeHash will return the value of CodeHash.
+func (u *UserMFARecovery) GetCodeHash() string { return u.CodeHash }
+
+// SetCodeHash sets CodeHash to the provided value.
+func (u *UserMFARecovery) SetCodeHash(val string) { u.CodeHash = val }
+
+// GetUserID will return the value of UserID.
+func (u *UserMFARecovery) GetUserID() string { return u.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (u *UserMFARecovery) SetUserID(val string) { u.UserID = val }
+
+// GetUsedAt will return the value of UsedAt.
+func (u *UserMFARecovery) GetUsedAt() *time.Time { return u.UsedAt }
+
+// SetUsedAt sets UsedAt to the provided value.
+func (u *UserMFARecovery) SetUsedAt(stamp time.Time) { u.UsedAt = &stamp }
+
+// GetCreatedAt will return the value of CreatedAt.
+func (u *UserMFARecovery) GetCreatedAt() *time.Time { return u.CreatedAt }
+
+// SetCreatedAt sets CreatedAt to the provided value.
+func (u *UserMFARecovery) SetCreatedAt(stamp time.Time) { u.CreatedAt = &stamp }
+
+// UserMFARecoveryTable is the name of the table in the DB.
+const UserMFARecoveryTable = "`user_mfa_recovery`"
+
+// UserMFARecoveryFields is a list of all columns in the DB table.
+var UserMFARecoveryFields = []string{"code_hash", "user_id", "used_at", "created_at"}
+
+// UserMFARecoveryPrimaryFields are the primary key fields in the DB table.
+var UserMFARecoveryPrimaryFields = []string{"code_hash"}
+
 // UserPasskey generated for db table `user_passkey`.
 //
 // User Passkey.
@@ -510,6 +809,65 @@ var UserPasskeyFields = []string{"id", "user_id", "credential_id", "public_key",
 // UserPasskeyPrimaryFields are the primary key fields in the DB table.
 var UserPasskeyPrimaryFields = []string{"id"}
 
+// UserPasswordReset generated for db table `user_password_reset`.
+//
+// User Password Reset.
+type UserPasswordReset struct {
+	// Token Hash
+	TokenHash string `db:"token_hash" json:"token_hash"`
+
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Expires At
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at"`
+
+	// Used At
+	UsedAt *time.Time `db:"used_at" json:"used_at"`
+
+	// Created At
+	CreatedAt *time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetTokenHash will return the value of TokenHash.
+func (u *UserPasswordReset) GetTokenHash() string { return u.TokenHash }
+
+// SetTokenHash sets TokenHash to the provided value.
+func (u *UserPasswordReset) SetTokenHash(val string) { u.TokenHash = val }
+
+// GetUserID will return the value of UserID.
+func (u *UserPasswordReset) GetUserID() string { return u.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (u *UserPasswordReset) SetUserID(val string) { u.UserID = val }
+
+// GetExpiresAt will return the value of ExpiresAt.
+func (u *UserPasswordReset) GetExpiresAt() *time.Time { return u.ExpiresAt }
+
+// SetExpiresAt sets ExpiresAt to the provided value.
+func (u *UserPasswordReset) SetExpiresAt(stamp time.Time) { u.ExpiresAt = &stamp }
+
+// GetUsedAt will return the value of UsedAt.
+func (u *UserPasswordReset) GetUsedAt() *time.Time { return u.UsedAt }
+
+// SetUsedAt sets UsedAt to the provided value.
+func (u *UserPasswordReset) SetUsedAt(stamp time.Time) { u.UsedAt = &stamp }
+
+// GetCreatedAt will return the value of CreatedAt.
+func (u *UserPasswordReset) GetCreatedAt() *time.Time { return u.CreatedAt }
+
+// SetCreatedAt sets CreatedAt to the provided value.
+func (u *UserPasswordReset) SetCreatedAt(stamp time.Time) { u.CreatedAt = &stamp }
+
+// UserPasswordResetTable is the name of the table in the DB.
+const UserPasswordResetTable = "`user_password_reset`"
+
+// UserPasswordResetFields is a list of all columns in the DB table.
+var UserPasswordResetFields = []string{"token_hash", "user_id", "expires_at", "used_at", "created_at"}
+
+// UserPasswordResetPrimaryFields are the primary key fields in the DB table.
+var UserPasswordResetPrimaryFields = []string{"token_hash"}
+
 // UserSession generated for db table `user_session`.
 //
 // User Session.
@@ -525,6 +883,18 @@ type UserSession struct {
 
 	// Created At
 	CreatedAt *time.Time `db:"created_at" json:"created_at"`
+
+	// Name
+	Name string `db:"name" json:"name"`
+
+	// User Agent
+	UserAgent string `db:"user_agent" json:"user_agent"`
+
+	// IP
+	IP string `db:"ip" json:"ip"`
+
+	// Last Seen At
+	LastSeenAt *time.Time `db:"last_seen_at" json:"last_seen_at"`
 }
 
 // GetID will return the value of ID.
@@ -551,15 +921,125 @@ func (u *UserSession) GetCreatedAt() *time.Time { return u.CreatedAt }
 // SetCreatedAt sets CreatedAt to the provided value.
 func (u *UserSession) SetCreatedAt(stamp time.Time) { u.CreatedAt = &stamp }
 
+// GetName will return the value of Name.
+func (u *UserSession) GetName() string { return u.Name }
+
+// SetName sets Name to the provided value.
+func (u *UserSession) SetName(val string) { u.Name = val }
+
+// GetUserAgent will return the value of UserAgent.
+func (u *UserSession) GetUserAgent() string { return u.UserAgent }
+
+// SetUserAgent sets UserAgent to the provided value.
+func (u *UserSession) SetUserAgent(val string) { u.UserAgent = val }
+
+// GetIP will return the value of IP.
+func (u *UserSession) GetIP() string { return u.IP }
+
+// SetIP sets IP to the provided value.
+func (u *UserSession) SetIP(val string) { u.IP = val }
+
+// GetLastSeenAt will return the value of LastSeenAt.
+func (u *UserSession) GetLastSeenAt() *time.Time { return u.LastSeenAt }
+
+// SetLastSeenAt sets LastSeenAt to the provided value.
+func (u *UserSession) SetLastSeenAt(stamp time.Time) { u.LastSeenAt = &stamp }
+
 // UserSessionTable is the name of the table in the DB.
 const UserSessionTable = "`user_session`"
 
 // UserSessionFields is a list of all columns in the DB table.
-var UserSessionFields = []string{"id", "user_id", "expires_at", "created_at"}
+var UserSessionFields = []string{"id", "user_id", "expires_at", "created_at", "name", "user_agent", "ip", "last_seen_at"}
 
 // UserSessionPrimaryFields are the primary key fields in the DB table.
 var UserSessionPrimaryFields = []string{"id"}
 
+// UserToken generated for db table `user_token`.
+//
+// User Token.
+type UserToken struct {
+	// Jti
+	Jti string `db:"jti" json:"jti"`
+
+	// User ID
+	UserID string `db:"user_id" json:"user_id"`
+
+	// Name
+	Name string `db:"name" json:"name"`
+
+	// User Agent
+	UserAgent string `db:"user_agent" json:"user_agent"`
+
+	// IP
+	IP string `db:"ip" json:"ip"`
+
+	// Expires At
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at"`
+
+	// Last Seen At
+	LastSeenAt *time.Time `db:"last_seen_at" json:"last_seen_at"`
+
+	// Created At
+	CreatedAt *time.Time `db:"created_at" json:"created_at"`
+}
+
+// GetJti will return the value of Jti.
+func (u *UserToken) GetJti() string { return u.Jti }
+
+// SetJti sets Jti to the provided value.
+func (u *UserToken) SetJti(val string) { u.Jti = val }
+
+// GetUserID will return the value of UserID.
+func (u *UserToken) GetUserID() string { return u.UserID }
+
+// SetUserID sets UserID to the provided value.
+func (u *UserToken) SetUserID(val string) { u.UserID = val }
+
+// GetName will return the value of Name.
+func (u *UserToken) GetName() string { return u.Name }
+
+// SetName sets Name to the provided value.
+func (u *UserToken) SetName(val string) { u.Name = val }
+
+// GetUserAgent will return the value of UserAgent.
+func (u *UserToken) GetUserAgent() string { return u.UserAgent }
+
+// SetUserAgent sets UserAgent to the provided value.
+func (u *UserToken) SetUserAgent(val string) { u.UserAgent = val }
+
+// GetIP will return the value of IP.
+func (u *UserToken) GetIP() string { return u.IP }
+
+// SetIP sets IP to the provided value.
+func (u *UserToken) SetIP(val string) { u.IP = val }
+
+// GetExpiresAt will return the value of ExpiresAt.
+func (u *UserToken) GetExpiresAt() *time.Time { return u.ExpiresAt }
+
+// SetExpiresAt sets ExpiresAt to the provided value.
+func (u *UserToken) SetExpiresAt(stamp time.Time) { u.ExpiresAt = &stamp }
+
+// GetLastSeenAt will return the value of LastSeenAt.
+func (u *UserToken) GetLastSeenAt() *time.Time { return u.LastSeenAt }
+
+// SetLastSeenAt sets LastSeenAt to the provided value.
+func (u *UserToken) SetLastSeenAt(stamp time.Time) { u.LastSeenAt = &stamp }
+
+// GetCreatedAt will return the value of CreatedAt.
+func (u *UserToken) GetCreatedAt() *time.Time { return u.CreatedAt }
+
+// SetCreatedAt sets CreatedAt to the provided value.
+func (u *UserToken) SetCreatedAt(stamp time.Time) { u.CreatedAt = &stamp }
+
+// UserTokenTable is the name of the table in the DB.
+const UserTokenTable = "`user_token`"
+
+// UserTokenFields is a list of all columns in the DB table.
+var UserTokenFields = []string{"jti", "user_id", "name", "user_agent", "ip", "expires_at", "last_seen_at", "created_at"}
+
+// UserTokenPrimaryFields are the primary key fields in the DB table.
+var UserTokenPrimaryFields = []string{"jti"}
+
 // UserTokenRevoked generated for db table `user_token_revoked`.
 //
 // User Token Revoked.
@@ -794,9 +1274,9 @@ func (u *UserAuth) Delete(opts ...QueryOption) string {
 }
 
 // Insert starts building an INSERT INTO query.
-func (u *UserGroup) Insert(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: UserGroupTable, Statement: "INSERT INTO"}).Apply(opts...)
-	cols := UserGroupFields
+func (u *UserFlow) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserFlowTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := UserFlowFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -804,8 +1284,8 @@ func (u *UserGroup) Insert(opts ...QueryOption) string {
 }
 
 // Select starts building a SELECT query.
-func (u *UserGroup) Select(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: UserGroupTable}).Apply(opts...)
+func (u *UserFlow) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserFlowTable}).Apply(opts...)
 	cols := "*"
 	if len(cfg.Columns) > 0 {
 		cols = strings.Join(cfg.Columns, ", ")
@@ -824,9 +1304,9 @@ func (u *UserGroup) Select(opts ...QueryOption) string {
 }
 
 // Update starts building a UPDATE query.
-func (u *UserGroup) Update(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: UserGroupTable}).Apply(opts...)
-	cols := UserGroupFields
+func (u *UserFlow) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserFlowTable}).Apply(opts...)
+	cols := UserFlowFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -845,8 +1325,8 @@ func (u *UserGroup) Update(opts ...QueryOption) string {
 }
 
 // Delete starts building a DELETE query.
-func (u *UserGroup) Delete(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: UserGroupTable}).Apply(opts...)
+func (u *UserFlow) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserFlowTable}).Apply(opts...)
 	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
 	if cfg.Where != "" {
 		query += " WHERE " + cfg.Where
@@ -855,9 +1335,9 @@ func (u *UserGroup) Delete(opts ...QueryOption) string {
 }
 
 // Insert starts building an INSERT INTO query.
-func (u *UserGroupMember) Insert(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: UserGroupMemberTable, Statement: "INSERT INTO"}).Apply(opts...)
-	cols := UserGroupMemberFields
+func (u *UserGroup) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserGroupTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := UserGroupFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -865,8 +1345,8 @@ func (u *UserGroupMember) Insert(opts ...QueryOption) string {
 }
 
 // Select starts building a SELECT query.
-func (u *UserGroupMember) Select(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: UserGroupMemberTable}).Apply(opts...)
+func (u *UserGroup) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserGroupTable}).Apply(opts...)
 	cols := "*"
 	if len(cfg.Columns) > 0 {
 		cols = strings.Join(cfg.Columns, ", ")
@@ -885,9 +1365,9 @@ func (u *UserGroupMember) Select(opts ...QueryOption) string {
 }
 
 // Update starts building a UPDATE query.
-func (u *UserGroupMember) Update(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: UserGroupMemberTable}).Apply(opts...)
-	cols := UserGroupMemberFields
+func (u *UserGroup) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserGroupTable}).Apply(opts...)
+	cols := UserGroupFields
 	if len(cfg.Columns) > 0 {
 		cols = cfg.Columns
 	}
@@ -906,8 +1386,252 @@ func (u *UserGroupMember) Update(opts ...QueryOption) string {
 }
 
 // Delete starts building a DELETE query.
-func (u *UserGroupMember) Delete(opts ...QueryOption) string {
-	cfg := (&QueryConfig{Table: UserGroupMemberTable}).Apply(opts...)
+func (u *UserGroup) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserGroupTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Insert starts building an INSERT INTO query.
+func (u *UserGroupMember) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserGroupMemberTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := UserGroupMemberFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (u *UserGroupMember) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserGroupMemberTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (u *UserGroupMember) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserGroupMemberTable}).Apply(opts...)
+	cols := UserGroupMemberFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Delete starts building a DELETE query.
+func (u *UserGroupMember) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserGroupMemberTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Insert starts building an INSERT INTO query.
+func (u *UserMFA) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFATable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := UserMFAFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (u *UserMFA) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFATable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (u *UserMFA) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFATable}).Apply(opts...)
+	cols := UserMFAFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Delete starts building a DELETE query.
+func (u *UserMFA) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFATable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Insert starts building an INSERT INTO query.
+func (u *UserMFAPending) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFAPendingTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := UserMFAPendingFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (u *UserMFAPending) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFAPendingTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (u *UserMFAPending) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFAPendingTable}).Apply(opts...)
+	cols := UserMFAPendingFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Delete starts building a DELETE query.
+func (u *UserMFAPending) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFAPendingTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Insert starts building an INSERT INTO query.
+func (u *UserMFARecovery) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFARecoveryTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := UserMFARecoveryFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (u *UserMFARecovery) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFARecoveryTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (u *UserMFARecovery) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFARecoveryTable}).Apply(opts...)
+	cols := UserMFARecoveryFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Delete starts building a DELETE query.
+func (u *UserMFARecovery) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserMFARecoveryTable}).Apply(opts...)
 	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
 	if cfg.Where != "" {
 		query += " WHERE " + cfg.Where
@@ -976,6 +1700,67 @@ func (u *UserPasskey) Delete(opts ...QueryOption) string {
 	return query
 }
 
+// Insert starts building an INSERT INTO query.
+func (u *UserPasswordReset) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserPasswordResetTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := UserPasswordResetFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (u *UserPasswordReset) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserPasswordResetTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (u *UserPasswordReset) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserPasswordResetTable}).Apply(opts...)
+	cols := UserPasswordResetFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Delete starts building a DELETE query.
+func (u *UserPasswordReset) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserPasswordResetTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
 // Insert starts building an INSERT INTO query.
 func (u *UserSession) Insert(opts ...QueryOption) string {
 	cfg := (&QueryConfig{Table: UserSessionTable, Statement: "INSERT INTO"}).Apply(opts...)
@@ -1037,6 +1822,67 @@ func (u *UserSession) Delete(opts ...QueryOption) string {
 	return query
 }
 
+// Insert starts building an INSERT INTO query.
+func (u *UserToken) Insert(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserTokenTable, Statement: "INSERT INTO"}).Apply(opts...)
+	cols := UserTokenFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	return fmt.Sprintf("%s %s (%s) VALUES (:%s)", cfg.Statement, cfg.Table, strings.Join(cols, ", "), strings.Join(cols, ", :"))
+}
+
+// Select starts building a SELECT query.
+func (u *UserToken) Select(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserTokenTable}).Apply(opts...)
+	cols := "*"
+	if len(cfg.Columns) > 0 {
+		cols = strings.Join(cfg.Columns, ", ")
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", cols, cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	if cfg.OrderBy != "" {
+		query += " ORDER BY " + cfg.OrderBy
+	}
+	if cfg.LimitOffset > 0 {
+		query += fmt.Sprintf(" LIMIT %d, %d", cfg.LimitStart, cfg.LimitOffset)
+	}
+	return query
+}
+
+// Update starts building a UPDATE query.
+func (u *UserToken) Update(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserTokenTable}).Apply(opts...)
+	cols := UserTokenFields
+	if len(cfg.Columns) > 0 {
+		cols = cfg.Columns
+	}
+	setClause := ""
+	for i, col := range cols {
+		if i > 0 {
+			setClause += ", "
+		}
+		setClause += col + "=:" + col
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s", cfg.Table, setClause)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
+// Delete starts building a DELETE query.
+func (u *UserToken) Delete(opts ...QueryOption) string {
+	cfg := (&QueryConfig{Table: UserTokenTable}).Apply(opts...)
+	query := fmt.Sprintf("DELETE FROM %s", cfg.Table)
+	if cfg.Where != "" {
+		query += " WHERE " + cfg.Where
+	}
+	return query
+}
+
 // Insert starts building an INSERT INTO query.
 func (u *UserTokenRevoked) Insert(opts ...QueryOption) string {
 	cfg := (&QueryConfig{Table: UserTokenRevokedTable, Statement: "INSERT INTO"}).Apply(opts...)
@@ -1,6 +1,9 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // NewUser creates a new empty User.
 func NewUser() *User {
@@ -33,3 +36,27 @@ func (u *User) Validate() error {
 	}
 	return nil
 }
+
+// Location returns the user's timezone. An empty or unknown timezone
+// resolves to UTC.
+func (u *User) Location() *time.Location {
+	if u == nil || u.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ValidateTimezone checks that tz is empty or a known IANA timezone name.
+func ValidateTimezone(tz string) error {
+	if tz == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidTimezone, tz)
+	}
+	return nil
+}
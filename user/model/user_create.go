@@ -3,8 +3,40 @@ package model
 import (
 	"regexp"
 	"strings"
+	"sync"
 )
 
+// reserved holds usernames that can't be registered, see ReserveUsernames.
+var reserved struct {
+	sync.RWMutex
+	names map[string]bool
+}
+
+// ReserveUsernames prevents registering the given usernames. Modules
+// serving pages under /{username} reserve the names of their other
+// routes at that level, so user pages and routes can't collide. Only
+// new sign-ups are checked, existing users with a reserved name are
+// left for the module to report.
+func ReserveUsernames(names ...string) {
+	reserved.Lock()
+	defer reserved.Unlock()
+
+	if reserved.names == nil {
+		reserved.names = make(map[string]bool)
+	}
+	for _, name := range names {
+		reserved.names[strings.ToLower(name)] = true
+	}
+}
+
+// usernameReserved reports whether the username is reserved.
+func usernameReserved(username string) bool {
+	reserved.RLock()
+	defer reserved.RUnlock()
+
+	return reserved.names[strings.ToLower(username)]
+}
+
 // UserCreateRequest holds the fields required to create a new user.
 type UserCreateRequest struct {
 	FullName string `json:"full_name"`
@@ -44,6 +76,9 @@ func (r *UserCreateRequest) ValidateUsername() error {
 	if !regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*[a-z0-9]$|^[a-z0-9]{3}$`).MatchString(r.Username) {
 		return ErrUsernameInvalid
 	}
+	if usernameReserved(r.Username) {
+		return ErrUsernameReserved
+	}
 	return nil
 }
 
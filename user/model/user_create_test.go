@@ -63,3 +63,12 @@ func TestValidateUsername(t *testing.T) {
 		})
 	}
 }
+
+func TestReserveUsernames(t *testing.T) {
+	req := &UserCreateRequest{Username: "reserved-name"}
+	assert.NoError(t, req.ValidateUsername())
+
+	ReserveUsernames("Reserved-Name")
+	assert.ErrorIs(t, req.ValidateUsername(), ErrUsernameReserved)
+	assert.False(t, req.Valid())
+}
@@ -22,3 +22,18 @@ func TestUser(t *testing.T) {
 	require.Equal(t, s1, "Tit Petric")
 	require.Equal(t, s2, "Deleted user")
 }
+
+func TestUserLocation(t *testing.T) {
+	u := NewUser()
+	require.Equal(t, time.UTC, u.Location())
+
+	u.Timezone = "Europe/Ljubljana"
+	require.Equal(t, "Europe/Ljubljana", u.Location().String())
+
+	u.Timezone = "Mars/Olympus_Mons"
+	require.Equal(t, time.UTC, u.Location())
+
+	require.NoError(t, ValidateTimezone(""))
+	require.NoError(t, ValidateTimezone("America/New_York"))
+	require.Error(t, ValidateTimezone("Mars/Olympus_Mons"))
+}
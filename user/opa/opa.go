@@ -0,0 +1,27 @@
+package opa
+
+import (
+	_ "embed"
+
+	"github.com/titpetric/platform-app/user/flow"
+)
+
+// flowsYAML is the source of the generated flows.rego, flows.puml and
+// routes.json, see cmd/generate.
+//
+//go:embed flows.yml
+var flowsYAML []byte
+
+// Config returns the embedded flows.yml configuration.
+func Config() (*flow.Config, error) {
+	return flow.Parse(flowsYAML)
+}
+
+// Flows returns a flow Engine for the embedded flows.yml.
+func Flows() (*flow.Engine, error) {
+	cfg, err := Config()
+	if err != nil {
+		return nil, err
+	}
+	return flow.New(cfg)
+}
@@ -0,0 +1,22 @@
+package opa_test
+
+import (
+	"testing"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/opa"
+)
+
+func TestFlows(t *testing.T) {
+	flows, err := opa.Flows()
+	require.NoError(t, err)
+
+	step, ok := flows.Start("login")
+	require.True(t, ok)
+	require.Equal(t, "/login", step.Link)
+
+	step, ok = flows.Next("login", "login", "ok")
+	require.True(t, ok)
+	require.Equal(t, "check_mfa", step.Name)
+}
@@ -0,0 +1,14 @@
+-- Store the current flow step of a user.
+--
+-- The flow_token cookie holds a random token, only its SHA-256 hash is
+-- stored. flow and step name the last step the user was sent to, so the
+-- following pages can only be opened in the order of opa/flows.yml.
+CREATE TABLE IF NOT EXISTS user_flow (
+    token_hash TEXT PRIMARY KEY NOT NULL,
+    flow TEXT NOT NULL,
+    step TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_flow_expires_at ON user_flow(expires_at);
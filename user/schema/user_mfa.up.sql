@@ -0,0 +1,25 @@
+-- Add TOTP multi-factor authentication.
+--
+-- user_mfa holds the TOTP secret of a user. The secret is written on
+-- enrollment, and MFA only applies once enabled_at is set by verifying
+-- a first code. last_step is the time step of the last accepted code,
+-- so a code can't be replayed.
+--
+-- user_mfa_recovery holds single-use recovery codes issued when MFA is
+-- enabled. Only the SHA-256 hash of a code is stored.
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id TEXT PRIMARY KEY NOT NULL,
+    secret TEXT NOT NULL,
+    last_step INTEGER NOT NULL DEFAULT 0,
+    enabled_at DATETIME,
+    created_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS user_mfa_recovery (
+    code_hash TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    used_at DATETIME,
+    created_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_mfa_recovery_user_id ON user_mfa_recovery(user_id);
@@ -0,0 +1,15 @@
+-- Store logins pending on the MFA step.
+--
+-- A pending login is created when the password of a user with MFA
+-- enabled is accepted, and deleted once a code is verified. Only the
+-- SHA-256 hash of its token is stored. attempts counts the codes tried,
+-- so the login can be dropped after too many wrong ones.
+CREATE TABLE IF NOT EXISTS user_mfa_pending (
+    token_hash TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_mfa_pending_expires_at ON user_mfa_pending(expires_at);
@@ -0,0 +1,21 @@
+-- Add password reset tokens and per-user token revocation.
+--
+-- user_password_reset holds single-use reset tokens. Only the SHA-256
+-- hash of a token is stored, the token itself is sent by email. used_at
+-- is set when the token is exchanged, expired and used rows are purged
+-- when a new token is issued.
+--
+-- user_auth.tokens_revoked_at invalidates every JWT of a user issued
+-- before it, as on completing a password reset.
+CREATE TABLE IF NOT EXISTS user_password_reset (
+    token_hash TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    expires_at DATETIME,
+    used_at DATETIME,
+    created_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_password_reset_user_id ON user_password_reset(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_password_reset_expires_at ON user_password_reset(expires_at);
+
+ALTER TABLE user_auth ADD COLUMN tokens_revoked_at DATETIME;
@@ -0,0 +1,24 @@
+-- Add password expiry and email re-verification policies to user_auth.
+--
+-- password_changed_at and email_verified_at start the periods of the
+-- password_expiry_days and email_reverify_days features in opa/flows.yml.
+-- While NULL, the period starts at created_at.
+--
+-- email_verify_token holds the SHA-256 hash of a re-verification token,
+-- sent by email and valid until email_verify_expires_at.
+--
+-- policy_exempt is an admin override, exempting a user from both
+-- policies, e.g. a service account.
+--
+-- Existing users are backfilled as of now, so applying the migration on a
+-- populated database doesn't lock everyone out at once.
+ALTER TABLE user_auth ADD COLUMN password_changed_at DATETIME;
+ALTER TABLE user_auth ADD COLUMN email_verified_at DATETIME;
+ALTER TABLE user_auth ADD COLUMN email_verify_token TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_auth ADD COLUMN email_verify_expires_at DATETIME;
+ALTER TABLE user_auth ADD COLUMN policy_exempt INTEGER NOT NULL DEFAULT 0;
+
+UPDATE user_auth SET password_changed_at = CURRENT_TIMESTAMP WHERE password_changed_at IS NULL;
+UPDATE user_auth SET email_verified_at = CURRENT_TIMESTAMP WHERE email_verified_at IS NULL AND activated_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_user_auth_email_verify_token ON user_auth(email_verify_token);
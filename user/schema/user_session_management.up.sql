@@ -0,0 +1,30 @@
+-- Record where sessions and tokens were issued, so users can list and
+-- revoke them.
+--
+-- Sessions get a user-chosen name, the user agent and IP they were
+-- created from, and last_seen_at. Sessions use a sliding expiry: every
+-- use moves expires_at forward, keeping the window between last_seen_at
+-- and expires_at. Existing sessions read as last seen at creation.
+--
+-- user_token records the JWTs issued by the API, by their jti. Revoking a
+-- token deletes its row and adds the jti to user_token_revoked.
+ALTER TABLE user_session ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_session ADD COLUMN user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_session ADD COLUMN ip TEXT NOT NULL DEFAULT '';
+ALTER TABLE user_session ADD COLUMN last_seen_at DATETIME;
+
+UPDATE user_session SET last_seen_at = created_at WHERE last_seen_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_token (
+    jti TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    expires_at DATETIME,
+    last_seen_at DATETIME,
+    created_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_token_user_id ON user_token(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_token_expires_at ON user_token(expires_at);
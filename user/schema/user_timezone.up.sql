@@ -0,0 +1,5 @@
+-- Add a per-user timezone to the user profile.
+--
+-- The value is an IANA zone name (e.g. Europe/Ljubljana). An empty
+-- value means UTC.
+ALTER TABLE user ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- Keep the current token when revoking all tokens of a user.
+--
+-- Revoking all sessions and tokens sets user_auth.tokens_revoked_at,
+-- so JWTs that were never listed in user_token are revoked too.
+-- tokens_revoked_except holds the jti of the token that asked for it,
+-- which stays valid. A password reset clears it.
+ALTER TABLE user_auth ADD COLUMN tokens_revoked_except TEXT NOT NULL DEFAULT '';
@@ -13,7 +13,11 @@ import (
 
 	"github.com/titpetric/platform-app/user/model"
 	"github.com/titpetric/platform-app/user/service/auth"
+	"github.com/titpetric/platform-app/user/service/mfa"
 	"github.com/titpetric/platform-app/user/service/passkey"
+	"github.com/titpetric/platform-app/user/service/policy"
+	"github.com/titpetric/platform-app/user/service/recovery"
+	"github.com/titpetric/platform-app/user/service/sessions"
 	"github.com/titpetric/platform-app/user/storage"
 )
 
@@ -24,7 +28,12 @@ type Handlers struct {
 	userStorage    *storage.UserStorage
 	sessionStorage *storage.SessionStorage
 	revokedStorage *storage.RevokedTokenStorage
+	tokenStorage   *storage.TokenStorage
 	passkeySvc     *passkey.Service
+	recoverySvc    *recovery.Service
+	mfaSvc         *mfa.Service
+	policySvc      *policy.Service
+	sessionsSvc    *sessions.Service
 
 	emailActivationEnabled bool
 	emailSender            EmailSender
@@ -57,7 +66,12 @@ func NewHandlers(opts Options) *Handlers {
 		userStorage:            opts.UserStorage,
 		sessionStorage:         opts.SessionStorage,
 		revokedStorage:         opts.RevokedStorage,
+		tokenStorage:           opts.TokenStorage,
 		passkeySvc:             opts.PasskeyService,
+		recoverySvc:            opts.RecoveryService,
+		mfaSvc:                 opts.MFAService,
+		policySvc:              opts.PolicyService,
+		sessionsSvc:            opts.SessionsService,
 		emailActivationEnabled: opts.EmailActivationEnabled,
 		emailSender:            opts.EmailSender,
 		activationURLFormat:    opts.ActivationURLFormat,
@@ -73,9 +87,29 @@ func (s *Handlers) Mount(r platform.Router) {
 		r.Post("/api/user/token/refresh", s.RefreshToken)
 		r.Post("/api/user/token/revoke", s.RevokeToken)
 
+		r.Get("/api/user/sessions", s.ListSessions)
+		r.Post("/api/user/sessions/name", s.NameSession)
+		r.Post("/api/user/sessions/revoke", s.RevokeSession)
+
+		r.Get("/api/user/profile", s.GetProfile)
+		r.Patch("/api/user/profile", s.UpdateProfile)
+
 		r.Post("/api/user/email/activate", s.ActivateEmail)
 		r.Post("/api/user/email/resend", s.ResendActivation)
 
+		r.Post("/api/user/password/forgot", s.ForgotPassword)
+		r.Post("/api/user/password/reset", s.ResetPassword)
+		r.Post("/api/user/password/change", s.ChangePassword)
+
+		r.Post("/api/user/email/verify/request", s.RequestEmailVerification)
+		r.Post("/api/user/email/verify", s.VerifyEmail)
+
+		r.Get("/api/user/mfa", s.GetMFA)
+		r.Post("/api/user/mfa/enroll", s.EnrollMFA)
+		r.Post("/api/user/mfa/confirm", s.ConfirmMFA)
+		r.Post("/api/user/mfa/disable", s.DisableMFA)
+		r.Post("/api/user/mfa/verify", s.VerifyMFA)
+
 		r.Post("/api/passkey/register/begin", s.PasskeyRegisterBegin)
 		r.Post("/api/passkey/register/finish", s.PasskeyRegisterFinish)
 		r.Post("/api/passkey/login/begin", s.PasskeyLoginBegin)
@@ -147,7 +181,33 @@ func (s *Handlers) createToken(w http.ResponseWriter, r *http.Request) error {
 		}
 	}
 
-	token, err := auth.NewJWT(s.signingKey).Create(user.ID, s.tokenTTL)
+	// Second login step: users with MFA enabled get a short-lived
+	// pending token instead, to exchange with a code at
+	// /api/user/mfa/verify.
+	if s.mfaSvc != nil {
+		enabled, merr := s.mfaSvc.Enabled(r.Context(), user.ID)
+		if merr != nil {
+			return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to check mfa")}
+		}
+		if enabled {
+			mfaToken, merr := s.mfaSvc.Begin(r.Context(), user.ID)
+			if merr != nil {
+				return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to start mfa")}
+			}
+			platform.JSON(w, r, http.StatusAccepted, struct {
+				MFARequired bool   `json:"mfa_required"`
+				MFAToken    string `json:"mfa_token"`
+				ExpiresAt   int64  `json:"expires_at"`
+			}{
+				MFARequired: true,
+				MFAToken:    mfaToken,
+				ExpiresAt:   time.Now().Add(mfa.PendingTTL).Unix(),
+			})
+			return nil
+		}
+	}
+
+	token, expiresAt, err := s.issueToken(r, user.ID)
 	if err != nil {
 		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to create token")}
 	}
@@ -157,7 +217,7 @@ func (s *Handlers) createToken(w http.ResponseWriter, r *http.Request) error {
 		ExpiresAt int64  `json:"expires_at"`
 	}{
 		Token:     token,
-		ExpiresAt: time.Now().Add(s.tokenTTL).Unix(),
+		ExpiresAt: expiresAt,
 	}
 
 	platform.JSON(w, r, http.StatusOK, resp)
@@ -165,29 +225,13 @@ func (s *Handlers) createToken(w http.ResponseWriter, r *http.Request) error {
 }
 
 func (s *Handlers) refreshToken(w http.ResponseWriter, r *http.Request) error {
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		return &RequestError{StatusCode: http.StatusUnauthorized, Err: errors.New("missing authorization header")}
-	}
-
-	jwtAuth := auth.NewJWT(s.signingKey)
-	claims, err := jwtAuth.Claims(authHeader)
-	if err != nil {
-		return &RequestError{StatusCode: http.StatusUnauthorized, Err: errors.New("invalid token")}
-	}
-
 	// Reject already-revoked tokens before issuing a new one.
-	if s.revokedStorage != nil && claims.JTI != "" {
-		revoked, rerr := s.revokedStorage.IsRevoked(r.Context(), claims.JTI)
-		if rerr != nil {
-			return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to check revocation")}
-		}
-		if revoked {
-			return &RequestError{StatusCode: http.StatusUnauthorized, Err: errors.New("token revoked")}
-		}
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
 	}
 
-	token, err := jwtAuth.Create(claims.UserID, s.tokenTTL)
+	token, expiresAt, err := s.issueToken(r, claims.UserID)
 	if err != nil {
 		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to create token")}
 	}
@@ -204,7 +248,7 @@ func (s *Handlers) refreshToken(w http.ResponseWriter, r *http.Request) error {
 		ExpiresAt int64  `json:"expires_at"`
 	}{
 		Token:     token,
-		ExpiresAt: time.Now().Add(s.tokenTTL).Unix(),
+		ExpiresAt: expiresAt,
 	}
 
 	platform.JSON(w, r, http.StatusOK, resp)
@@ -290,7 +334,7 @@ func (s *Handlers) passkeyRegisterFinish(w http.ResponseWriter, r *http.Request)
 		return err
 	}
 
-	session, err := s.sessionStorage.Create(r.Context(), result.UserID)
+	session, err := s.sessionStorage.Create(r.Context(), sessions.NewSession(r, result.UserID))
 	if err != nil {
 		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to create session")}
 	}
@@ -344,7 +388,7 @@ func (s *Handlers) passkeyLoginFinish(w http.ResponseWriter, r *http.Request) er
 		return err
 	}
 
-	session, err := s.sessionStorage.Create(r.Context(), result.UserID)
+	session, err := s.sessionStorage.Create(r.Context(), sessions.NewSession(r, result.UserID))
 	if err != nil {
 		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to create session")}
 	}
@@ -399,7 +443,7 @@ func (s *Handlers) registerActivated(w http.ResponseWriter, r *http.Request, req
 		return mapRegisterError(err)
 	}
 
-	token, err := auth.NewJWT(s.signingKey).Create(user.ID, s.tokenTTL)
+	token, expiresAt, err := s.issueToken(r, user.ID)
 	if err != nil {
 		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to create token")}
 	}
@@ -411,7 +455,7 @@ func (s *Handlers) registerActivated(w http.ResponseWriter, r *http.Request, req
 	}{
 		UserID:    user.ID,
 		Token:     token,
-		ExpiresAt: time.Now().Add(s.tokenTTL).Unix(),
+		ExpiresAt: expiresAt,
 	})
 	return nil
 }
@@ -482,7 +526,7 @@ func (s *Handlers) activateEmail(w http.ResponseWriter, r *http.Request) error {
 		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to activate")}
 	}
 
-	token, err := auth.NewJWT(s.signingKey).Create(user.ID, s.tokenTTL)
+	token, expiresAt, err := s.issueToken(r, user.ID)
 	if err != nil {
 		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to create token")}
 	}
@@ -494,7 +538,7 @@ func (s *Handlers) activateEmail(w http.ResponseWriter, r *http.Request) error {
 	}{
 		UserID:    user.ID,
 		Token:     token,
-		ExpiresAt: time.Now().Add(s.tokenTTL).Unix(),
+		ExpiresAt: expiresAt,
 	})
 	return nil
 }
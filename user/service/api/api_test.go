@@ -209,3 +209,19 @@ func TestPasskeyLoginFinishMissingToken(t *testing.T) {
 
 	require.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestProfileMissingAuthorization(t *testing.T) {
+	t.Parallel()
+
+	svc := NewHandlers(Options{SigningKey: getTestSigningKey()})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/profile", nil)
+	w := httptest.NewRecorder()
+	svc.GetProfile(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodPatch, "/api/user/profile", bytes.NewBufferString(`{"timezone":"Europe/Ljubljana"}`))
+	w = httptest.NewRecorder()
+	svc.UpdateProfile(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// errMFADisabled is returned by the MFA endpoints when no MFA service is
+// configured.
+var errMFADisabled = errors.New("multi-factor authentication not configured")
+
+// mfaError maps MFA errors to request errors.
+func mfaError(err error, message string) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidMFACode):
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: model.ErrInvalidMFACode}
+	case errors.Is(err, model.ErrMFAPendingExpired):
+		return &RequestError{StatusCode: http.StatusUnauthorized, Err: model.ErrMFAPendingExpired}
+	case errors.Is(err, model.ErrMFAAlreadyEnabled), errors.Is(err, model.ErrMFANotEnrolled):
+		return &RequestError{StatusCode: http.StatusConflict, Err: err}
+	}
+	return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New(message)}
+}
+
+// decodeCode decodes a request body holding an MFA code.
+func decodeCode(r *http.Request) (string, error) {
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return "", &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("invalid request body")}
+	}
+	if req.Code == "" {
+		return "", &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("code is required")}
+	}
+	return req.Code, nil
+}
+
+// GetMFA returns the MFA status of the authenticated user.
+func (s *Handlers) GetMFA(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.getMFA(w, r))
+}
+
+func (s *Handlers) getMFA(w http.ResponseWriter, r *http.Request) error {
+	if s.mfaSvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errMFADisabled}
+	}
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
+	}
+
+	enabled, err := s.mfaSvc.Enabled(r.Context(), claims.UserID)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to check mfa")}
+	}
+	left := 0
+	if enabled {
+		if left, err = s.mfaSvc.RecoveryCodesLeft(r.Context(), claims.UserID); err != nil {
+			return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to count recovery codes")}
+		}
+	}
+
+	platform.JSON(w, r, http.StatusOK, struct {
+		Enabled           bool `json:"enabled"`
+		RecoveryCodesLeft int  `json:"recovery_codes_left"`
+	}{
+		Enabled:           enabled,
+		RecoveryCodesLeft: left,
+	})
+	return nil
+}
+
+// EnrollMFA generates a TOTP secret for the authenticated user, returned
+// with its provisioning URI. MFA is enabled with ConfirmMFA.
+func (s *Handlers) EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.enrollMFA(w, r))
+}
+
+func (s *Handlers) enrollMFA(w http.ResponseWriter, r *http.Request) error {
+	if s.mfaSvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errMFADisabled}
+	}
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
+	}
+
+	email, err := s.userStorage.GetEmail(r.Context(), claims.UserID)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to get user")}
+	}
+
+	enrollment, err := s.mfaSvc.Enroll(r.Context(), claims.UserID, email)
+	if err != nil {
+		return mfaError(err, "failed to enroll mfa")
+	}
+
+	platform.JSON(w, r, http.StatusOK, enrollment)
+	return nil
+}
+
+// ConfirmMFA enables MFA for the authenticated user with a first code,
+// and returns the recovery codes.
+func (s *Handlers) ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.confirmMFA(w, r))
+}
+
+func (s *Handlers) confirmMFA(w http.ResponseWriter, r *http.Request) error {
+	if s.mfaSvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errMFADisabled}
+	}
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
+	}
+	code, err := decodeCode(r)
+	if err != nil {
+		return err
+	}
+
+	codes, err := s.mfaSvc.Confirm(r.Context(), claims.UserID, code)
+	if err != nil {
+		return mfaError(err, "failed to enable mfa")
+	}
+
+	platform.JSON(w, r, http.StatusOK, struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}{
+		RecoveryCodes: codes,
+	})
+	return nil
+}
+
+// DisableMFA turns off MFA for the authenticated user, given a current
+// code or a recovery code.
+func (s *Handlers) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.disableMFA(w, r))
+}
+
+func (s *Handlers) disableMFA(w http.ResponseWriter, r *http.Request) error {
+	if s.mfaSvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errMFADisabled}
+	}
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
+	}
+	code, err := decodeCode(r)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mfaSvc.Disable(r.Context(), claims.UserID, code); err != nil {
+		return mfaError(err, "failed to disable mfa")
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// VerifyMFA completes a login pending on MFA, exchanging the mfa_token
+// from CreateToken and a code for a token.
+func (s *Handlers) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.verifyMFA(w, r))
+}
+
+func (s *Handlers) verifyMFA(w http.ResponseWriter, r *http.Request) error {
+	if s.mfaSvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errMFADisabled}
+	}
+
+	var req struct {
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("invalid request body")}
+	}
+	if req.MFAToken == "" || req.Code == "" {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("mfa_token and code are required")}
+	}
+
+	userID, err := s.mfaSvc.Verify(r.Context(), req.MFAToken, req.Code)
+	if err != nil {
+		return mfaError(err, "failed to verify mfa")
+	}
+
+	token, expiresAt, err := s.issueToken(r, userID)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to create token")}
+	}
+
+	platform.JSON(w, r, http.StatusOK, struct {
+		Token     string `json:"token"`
+		ExpiresAt int64  `json:"expires_at"`
+	}{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+	return nil
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/auth"
+	"github.com/titpetric/platform-app/user/service/mfa"
+)
+
+// testMFASecret is the TOTP secret of the enrolled test user.
+const testMFASecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+type mockMFAStorage struct {
+	lastStep int64
+	pending  map[string]*model.UserMFAPending
+}
+
+func (m *mockMFAStorage) Get(_ context.Context, userID string) (*model.UserMFA, error) {
+	if userID != "user-1" {
+		return nil, sql.ErrNoRows
+	}
+	now := time.Now()
+	return &model.UserMFA{UserID: userID, Secret: testMFASecret, LastStep: m.lastStep, EnabledAt: &now}, nil
+}
+
+func (m *mockMFAStorage) Enroll(context.Context, string, string) error {
+	return model.ErrMFAAlreadyEnabled
+}
+
+func (m *mockMFAStorage) Enable(context.Context, string, int64, []string) error {
+	return model.ErrMFANotEnrolled
+}
+
+func (m *mockMFAStorage) Disable(context.Context, string) error {
+	return nil
+}
+
+func (m *mockMFAStorage) UseStep(_ context.Context, _ string, step int64) (bool, error) {
+	if m.lastStep >= step {
+		return false, nil
+	}
+	m.lastStep = step
+	return true, nil
+}
+
+func (m *mockMFAStorage) UseRecoveryCode(context.Context, string, string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockMFAStorage) CountRecoveryCodes(context.Context, string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockMFAStorage) CreatePending(_ context.Context, token, userID string, ttl time.Duration) error {
+	if m.pending == nil {
+		m.pending = make(map[string]*model.UserMFAPending)
+	}
+	m.pending[token] = &model.UserMFAPending{UserID: userID}
+	m.pending[token].SetExpiresAt(time.Now().Add(ttl))
+	return nil
+}
+
+func (m *mockMFAStorage) GetPending(_ context.Context, token string) (*model.UserMFAPending, error) {
+	pending, ok := m.pending[token]
+	if !ok || time.Now().After(*pending.ExpiresAt) {
+		return nil, sql.ErrNoRows
+	}
+	return pending, nil
+}
+
+func (m *mockMFAStorage) AttemptPending(ctx context.Context, token string, maxAttempts int) (*model.UserMFAPending, error) {
+	pending, err := m.GetPending(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if pending.Attempts >= int64(maxAttempts) {
+		return nil, sql.ErrNoRows
+	}
+	pending.Attempts++
+	return pending, nil
+}
+
+func (m *mockMFAStorage) DeletePending(_ context.Context, token string) (bool, error) {
+	_, ok := m.pending[token]
+	delete(m.pending, token)
+	return ok, nil
+}
+
+func TestMFANotConfigured(t *testing.T) {
+	t.Parallel()
+
+	svc := NewHandlers(Options{SigningKey: getTestSigningKey()})
+	for _, handler := range []http.HandlerFunc{svc.GetMFA, svc.EnrollMFA, svc.ConfirmMFA, svc.DisableMFA, svc.VerifyMFA} {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/mfa", bytes.NewBufferString(`{}`))
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+
+		require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	}
+}
+
+func TestEnrollMFAMissingAuthorization(t *testing.T) {
+	t.Parallel()
+
+	svc := NewHandlers(Options{
+		SigningKey: getTestSigningKey(),
+		MFAService: mfa.New(&mockMFAStorage{}, "Platform App"),
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/mfa/enroll", nil)
+	w := httptest.NewRecorder()
+
+	svc.EnrollMFA(w, req)
+
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestVerifyMFA(t *testing.T) {
+	t.Parallel()
+
+	mfaSvc := mfa.New(&mockMFAStorage{}, "Platform App")
+	svc := NewHandlers(Options{
+		SigningKey: getTestSigningKey(),
+		MFAService: mfaSvc,
+	})
+
+	verify := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/mfa/verify", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		svc.VerifyMFA(w, req)
+		return w
+	}
+
+	code, err := mfa.Code(testMFASecret, mfa.Step(time.Now()))
+	require.NoError(t, err)
+
+	begin := func(t *testing.T) string {
+		t.Helper()
+		token, err := mfaSvc.Begin(context.Background(), "user-1")
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("missing fields", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, verify(`{"code":"123456"}`).Code)
+	})
+
+	t.Run("unknown mfa token", func(t *testing.T) {
+		require.Equal(t, http.StatusUnauthorized, verify(`{"mfa_token":"bogus","code":"`+code+`"}`).Code)
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		token := begin(t)
+		require.Equal(t, http.StatusUnauthorized, verify(`{"mfa_token":"`+token+`","code":"bogus"}`).Code)
+	})
+
+	t.Run("valid code", func(t *testing.T) {
+		token := begin(t)
+		w := verify(`{"mfa_token":"` + token + `","code":"` + code + `"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			Token string `json:"token"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+
+		userID, err := auth.NewJWT(getTestSigningKey()).UserID(resp.Token)
+		require.NoError(t, err)
+		require.Equal(t, "user-1", userID)
+
+		// The code can't be replayed.
+		token = begin(t)
+		require.Equal(t, http.StatusUnauthorized, verify(`{"mfa_token":"`+token+`","code":"`+code+`"}`).Code)
+	})
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"time"
 
+	"github.com/titpetric/platform-app/user/service/mfa"
 	"github.com/titpetric/platform-app/user/service/passkey"
+	"github.com/titpetric/platform-app/user/service/policy"
+	"github.com/titpetric/platform-app/user/service/recovery"
+	"github.com/titpetric/platform-app/user/service/sessions"
 	"github.com/titpetric/platform-app/user/storage"
 )
 
@@ -24,6 +28,27 @@ type Options struct {
 	RevokedStorage *storage.RevokedTokenStorage
 	PasskeyService *passkey.Service
 
+	// TokenStorage records issued tokens, so users can list and revoke
+	// them. When nil, tokens are issued without being recorded.
+	TokenStorage *storage.TokenStorage
+
+	// SessionsService enables the endpoints listing, naming and
+	// revoking sessions and tokens. When nil, they respond with 503.
+	SessionsService *sessions.Service
+
+	// RecoveryService enables the password reset endpoints. When nil,
+	// they respond with 503.
+	RecoveryService *recovery.Service
+
+	// MFAService enables TOTP multi-factor authentication. When nil,
+	// logins take a single step and the MFA endpoints respond with 503.
+	MFAService *mfa.Service
+
+	// PolicyService enables the endpoints completing the password
+	// expiry and email re-verification policies. When nil, they
+	// respond with 503.
+	PolicyService *policy.Service
+
 	// Activation configuration; see service.Options.
 	EmailActivationEnabled bool
 	EmailSender            EmailSender
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// errRecoveryDisabled is returned by the password endpoints when no
+// recovery service is configured, e.g. without an email sender.
+var errRecoveryDisabled = errors.New("password reset not configured")
+
+// ForgotPassword mails a password reset token to the given email. Returns
+// 204 whether or not the email has an account, to avoid leaking account
+// presence.
+func (s *Handlers) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.forgotPassword(w, r))
+}
+
+func (s *Handlers) forgotPassword(w http.ResponseWriter, r *http.Request) error {
+	if s.recoverySvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errRecoveryDisabled}
+	}
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("invalid request body")}
+	}
+	if req.Email == "" {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: model.ErrEmailMissing}
+	}
+
+	if err := s.recoverySvc.Request(r.Context(), req.Email); err != nil {
+		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to request password reset")}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// ResetPassword exchanges a password reset token for a new password. All
+// existing sessions and tokens of the user are revoked, and a fresh token
+// is returned.
+func (s *Handlers) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.resetPassword(w, r))
+}
+
+func (s *Handlers) resetPassword(w http.ResponseWriter, r *http.Request) error {
+	if s.recoverySvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errRecoveryDisabled}
+	}
+
+	var req struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("invalid request body")}
+	}
+	if req.Token == "" {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("token is required")}
+	}
+	if req.Password == "" {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: model.ErrPasswordMissing}
+	}
+
+	user, err := s.recoverySvc.Reset(r.Context(), req.Token, req.Password)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidResetToken) {
+			return &RequestError{StatusCode: http.StatusNotFound, Err: model.ErrInvalidResetToken}
+		}
+		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to reset password")}
+	}
+
+	token, expiresAt, err := s.issueToken(r, user.ID)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to create token")}
+	}
+
+	platform.JSON(w, r, http.StatusOK, struct {
+		UserID    string `json:"user_id"`
+		Token     string `json:"token"`
+		ExpiresAt int64  `json:"expires_at"`
+	}{
+		UserID:    user.ID,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+	return nil
+}
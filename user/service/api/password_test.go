@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/auth"
+	"github.com/titpetric/platform-app/user/service/recovery"
+)
+
+type mockResetStorage struct{}
+
+func (mockResetStorage) CreatePasswordReset(_ context.Context, email string, _ time.Duration) (string, string, error) {
+	if email != "me@titpetric.com" {
+		return "", "", sql.ErrNoRows
+	}
+	return "reset-token", "user-1", nil
+}
+
+func (mockResetStorage) CheckPasswordReset(_ context.Context, token string) error {
+	if token != "reset-token" {
+		return model.ErrInvalidResetToken
+	}
+	return nil
+}
+
+func (mockResetStorage) ResetPassword(_ context.Context, token, _ string) (*model.User, error) {
+	if token != "reset-token" {
+		return nil, model.ErrInvalidResetToken
+	}
+	return &model.User{ID: "user-1"}, nil
+}
+
+type mockEmailSender struct {
+	sent int
+}
+
+func (m *mockEmailSender) Send(context.Context, string, string, string) error {
+	m.sent++
+	return nil
+}
+
+func newRecoveryHandlers(sender *mockEmailSender) *Handlers {
+	return NewHandlers(Options{
+		SigningKey:      getTestSigningKey(),
+		RecoveryService: recovery.New(mockResetStorage{}, recovery.Options{EmailSender: sender}),
+	})
+}
+
+func TestForgotPassword(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not configured", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/password/forgot", bytes.NewBufferString(`{"email":"me@titpetric.com"}`))
+		w := httptest.NewRecorder()
+
+		NewHandlers(Options{}).ForgotPassword(w, req)
+
+		require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	t.Run("missing email", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/password/forgot", bytes.NewBufferString(`{}`))
+		w := httptest.NewRecorder()
+
+		newRecoveryHandlers(&mockEmailSender{}).ForgotPassword(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("known and unknown email", func(t *testing.T) {
+		sender := &mockEmailSender{}
+		svc := newRecoveryHandlers(sender)
+
+		for _, email := range []string{"me@titpetric.com", "nobody@titpetric.com"} {
+			req := httptest.NewRequest(http.MethodPost, "/api/user/password/forgot", bytes.NewBufferString(`{"email":"`+email+`"}`))
+			w := httptest.NewRecorder()
+
+			svc.ForgotPassword(w, req)
+
+			require.Equal(t, http.StatusNoContent, w.Code)
+		}
+		require.Equal(t, 1, sender.sent)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not configured", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/password/reset", bytes.NewBufferString(`{"token":"reset-token","password":"secret"}`))
+		w := httptest.NewRecorder()
+
+		NewHandlers(Options{}).ResetPassword(w, req)
+
+		require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	t.Run("missing password", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/password/reset", bytes.NewBufferString(`{"token":"reset-token"}`))
+		w := httptest.NewRecorder()
+
+		newRecoveryHandlers(&mockEmailSender{}).ResetPassword(w, req)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/password/reset", bytes.NewBufferString(`{"token":"bogus","password":"secret"}`))
+		w := httptest.NewRecorder()
+
+		newRecoveryHandlers(&mockEmailSender{}).ResetPassword(w, req)
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/password/reset", bytes.NewBufferString(`{"token":"reset-token","password":"secret"}`))
+		w := httptest.NewRecorder()
+
+		newRecoveryHandlers(&mockEmailSender{}).ResetPassword(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp struct {
+			UserID string `json:"user_id"`
+			Token  string `json:"token"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		require.Equal(t, "user-1", resp.UserID)
+
+		userID, err := auth.NewJWT(getTestSigningKey()).UserID(resp.Token)
+		require.NoError(t, err)
+		require.Equal(t, "user-1", userID)
+	})
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// errPolicyDisabled is returned by the policy endpoints when no policy
+// service is configured.
+var errPolicyDisabled = errors.New("password and email policies not configured")
+
+// ChangePassword sets a new password for the authenticated user, given
+// their current one. It completes the password_expired policy, which
+// token clients are sent to with a 403 and code "password_expired".
+func (s *Handlers) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.changePassword(w, r))
+}
+
+func (s *Handlers) changePassword(w http.ResponseWriter, r *http.Request) error {
+	if s.policySvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errPolicyDisabled}
+	}
+
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		Password        string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("invalid request body")}
+	}
+	if req.Password == "" {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: model.ErrPasswordMissing}
+	}
+
+	if err := s.policySvc.ChangePassword(r.Context(), claims.UserID, req.CurrentPassword, req.Password); err != nil {
+		if errors.Is(err, model.ErrPasswordIncorrect) {
+			return &RequestError{StatusCode: http.StatusUnauthorized, Err: model.ErrPasswordIncorrect}
+		}
+		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to change password")}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// RequestEmailVerification mails an email verification token to the
+// authenticated user, for the email_reverify policy.
+func (s *Handlers) RequestEmailVerification(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.requestEmailVerification(w, r))
+}
+
+func (s *Handlers) requestEmailVerification(w http.ResponseWriter, r *http.Request) error {
+	if s.policySvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errPolicyDisabled}
+	}
+
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
+	}
+
+	if err := s.policySvc.RequestVerification(r.Context(), claims.UserID); err != nil {
+		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to send email verification")}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// VerifyEmail exchanges an email verification token, completing the
+// email_reverify policy of its user. The token is the proof, so no
+// authorization is needed.
+func (s *Handlers) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.verifyEmail(w, r))
+}
+
+func (s *Handlers) verifyEmail(w http.ResponseWriter, r *http.Request) error {
+	if s.policySvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errPolicyDisabled}
+	}
+
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("invalid request body")}
+	}
+	if req.Token == "" {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("token is required")}
+	}
+
+	if _, err := s.policySvc.VerifyEmail(r.Context(), req.Token); err != nil {
+		if errors.Is(err, model.ErrInvalidVerifyToken) {
+			return &RequestError{StatusCode: http.StatusNotFound, Err: model.ErrInvalidVerifyToken}
+		}
+		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to verify email")}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
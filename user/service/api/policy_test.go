@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/auth"
+	"github.com/titpetric/platform-app/user/service/policy"
+)
+
+type mockPolicyStorage struct{}
+
+func (mockPolicyStorage) GetPolicy(_ context.Context, userID string) (*model.UserAuth, error) {
+	return &model.UserAuth{UserID: userID}, nil
+}
+
+func (mockPolicyStorage) ChangePassword(_ context.Context, _, current, _ string) error {
+	if current != "secret" {
+		return model.ErrPasswordIncorrect
+	}
+	return nil
+}
+
+func (mockPolicyStorage) CreateEmailVerification(context.Context, string, time.Duration) (string, string, error) {
+	return "verify-token", "me@titpetric.com", nil
+}
+
+func (mockPolicyStorage) CheckEmailVerification(_ context.Context, token string) error {
+	if token != "verify-token" {
+		return model.ErrInvalidVerifyToken
+	}
+	return nil
+}
+
+func (mockPolicyStorage) VerifyEmail(_ context.Context, token string) (*model.User, error) {
+	if token != "verify-token" {
+		return nil, model.ErrInvalidVerifyToken
+	}
+	return &model.User{ID: "user-1"}, nil
+}
+
+func newPolicyHandlers(sender *mockEmailSender) *Handlers {
+	return NewHandlers(Options{
+		SigningKey:    getTestSigningKey(),
+		PolicyService: policy.New(mockPolicyStorage{}, nil, policy.Options{EmailSender: sender}),
+	})
+}
+
+func newPolicyRequest(t *testing.T, path, body string) *http.Request {
+	t.Helper()
+
+	token, err := auth.NewJWT(getTestSigningKey()).Create("user-1", time.Hour)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestPolicyNotConfigured(t *testing.T) {
+	t.Parallel()
+
+	svc := NewHandlers(Options{SigningKey: getTestSigningKey()})
+	for _, handler := range []http.HandlerFunc{svc.ChangePassword, svc.RequestEmailVerification, svc.VerifyEmail} {
+		req := newPolicyRequest(t, "/api/user/password/change", `{}`)
+		w := httptest.NewRecorder()
+
+		handler(w, req)
+
+		require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"missing password", `{"current_password":"secret"}`, http.StatusBadRequest},
+		{"incorrect password", `{"current_password":"wrong","password":"new secret"}`, http.StatusUnauthorized},
+		{"changed", `{"current_password":"secret","password":"new secret"}`, http.StatusNoContent},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newPolicyHandlers(&mockEmailSender{}).ChangePassword(w, newPolicyRequest(t, "/api/user/password/change", tc.body))
+
+			require.Equal(t, tc.want, w.Code)
+		})
+	}
+
+	t.Run("missing authorization", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/password/change", bytes.NewBufferString(`{"current_password":"secret","password":"new secret"}`))
+		w := httptest.NewRecorder()
+
+		newPolicyHandlers(&mockEmailSender{}).ChangePassword(w, req)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestEmailVerification(t *testing.T) {
+	t.Parallel()
+
+	sender := &mockEmailSender{}
+	svc := newPolicyHandlers(sender)
+
+	w := httptest.NewRecorder()
+	svc.RequestEmailVerification(w, newPolicyRequest(t, "/api/user/email/verify/request", ``))
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Equal(t, 1, sender.sent)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"missing token", `{}`, http.StatusBadRequest},
+		{"invalid token", `{"token":"bogus"}`, http.StatusNotFound},
+		{"valid token", `{"token":"verify-token"}`, http.StatusNoContent},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/user/email/verify", bytes.NewBufferString(tc.body))
+			w := httptest.NewRecorder()
+
+			svc.VerifyEmail(w, req)
+
+			require.Equal(t, tc.want, w.Code)
+		})
+	}
+}
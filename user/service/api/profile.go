@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/auth"
+)
+
+// GetProfile returns the profile of the authenticated user.
+func (s *Handlers) GetProfile(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.getProfile(w, r))
+}
+
+func (s *Handlers) getProfile(w http.ResponseWriter, r *http.Request) error {
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userStorage.Get(r.Context(), claims.UserID)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusNotFound, Err: errors.New("user not found")}
+	}
+
+	platform.JSON(w, r, http.StatusOK, user)
+	return nil
+}
+
+// UpdateProfile updates the profile of the authenticated user.
+func (s *Handlers) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.updateProfile(w, r))
+}
+
+func (s *Handlers) updateProfile(w http.ResponseWriter, r *http.Request) error {
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
+	}
+
+	// Fields left out of the request body are not changed.
+	var req struct {
+		FullName *string `json:"full_name"`
+		Timezone *string `json:"timezone"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("invalid request body")}
+	}
+
+	ctx := r.Context()
+	user, err := s.userStorage.Get(ctx, claims.UserID)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusNotFound, Err: errors.New("user not found")}
+	}
+
+	if req.FullName != nil {
+		if *req.FullName == "" {
+			return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("full name is required")}
+		}
+		user.FullName = *req.FullName
+	}
+	if req.Timezone != nil {
+		if err := model.ValidateTimezone(*req.Timezone); err != nil {
+			return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+		}
+		user.Timezone = *req.Timezone
+	}
+
+	user, err = s.userStorage.Update(ctx, user)
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to update profile")}
+	}
+
+	platform.JSON(w, r, http.StatusOK, user)
+	return nil
+}
+
+// authorize validates the bearer token of the request and rejects
+// revoked tokens.
+func (s *Handlers) authorize(r *http.Request) (*auth.Claims, error) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return nil, &RequestError{StatusCode: http.StatusUnauthorized, Err: errors.New("missing authorization header")}
+	}
+
+	claims, err := auth.NewJWT(s.signingKey).Claims(authHeader)
+	if err != nil {
+		return nil, &RequestError{StatusCode: http.StatusUnauthorized, Err: errors.New("invalid token")}
+	}
+
+	if s.revokedStorage != nil && claims.JTI != "" {
+		revoked, err := s.revokedStorage.IsRevoked(r.Context(), claims.JTI)
+		if err != nil {
+			return nil, &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to check revocation")}
+		}
+		if revoked {
+			return nil, &RequestError{StatusCode: http.StatusUnauthorized, Err: errors.New("token revoked")}
+		}
+	}
+
+	if s.revokedStorage != nil {
+		revoked, err := s.revokedStorage.IsUserRevoked(r.Context(), claims.UserID, claims.JTI, time.Unix(claims.IssuedAt, 0))
+		if err != nil {
+			return nil, &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to check revocation")}
+		}
+		if revoked {
+			return nil, &RequestError{StatusCode: http.StatusUnauthorized, Err: errors.New("token revoked")}
+		}
+	}
+
+	// Best-effort, the last use is informational.
+	_ = s.tokenStorage.Touch(r.Context(), claims.JTI)
+
+	return claims, nil
+}
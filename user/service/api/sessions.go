@@ -0,0 +1,156 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/auth"
+	"github.com/titpetric/platform-app/user/service/sessions"
+)
+
+// errSessionsDisabled is returned by the session endpoints when no
+// sessions service is configured.
+var errSessionsDisabled = errors.New("session management not configured")
+
+// issueToken creates a JWT for the user and records it with the user
+// agent and IP of the request, so the user can list and revoke it. It
+// returns the token and its expiry as a unix timestamp.
+func (s *Handlers) issueToken(r *http.Request, userID string) (string, int64, error) {
+	expiresAt := time.Now().Add(s.tokenTTL)
+	token, jti, err := auth.NewJWT(s.signingKey).CreateWithJTI(userID, s.tokenTTL)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := s.tokenStorage.Create(r.Context(), sessions.NewToken(r, userID, jti, expiresAt)); err != nil {
+		return "", 0, err
+	}
+	return token, expiresAt.Unix(), nil
+}
+
+// ListSessions lists the web sessions and API tokens of the
+// authenticated user. The token of the request is marked current.
+func (s *Handlers) ListSessions(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.listSessions(w, r))
+}
+
+func (s *Handlers) listSessions(w http.ResponseWriter, r *http.Request) error {
+	if s.sessionsSvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errSessionsDisabled}
+	}
+
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.sessionsSvc.List(r.Context(), claims.UserID, sessions.Current{JTI: claims.JTI})
+	if err != nil {
+		return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to list sessions")}
+	}
+
+	platform.JSON(w, r, http.StatusOK, struct {
+		Sessions []sessions.Entry `json:"sessions"`
+	}{
+		Sessions: result,
+	})
+	return nil
+}
+
+// NameSession names a session or token of the authenticated user.
+func (s *Handlers) NameSession(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.nameSession(w, r))
+}
+
+func (s *Handlers) nameSession(w http.ResponseWriter, r *http.Request) error {
+	if s.sessionsSvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errSessionsDisabled}
+	}
+
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("invalid request body")}
+	}
+	if req.ID == "" {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("id is required")}
+	}
+
+	if err := s.sessionsSvc.Rename(r.Context(), claims.UserID, req.ID, req.Name); err != nil {
+		return sessionError(err, "failed to name session")
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// RevokeSession ends a session or revokes a token of the authenticated
+// user. With "all" set, it revokes all of them except the token of the
+// request, and responds with how many there were.
+func (s *Handlers) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	s.errorHandler(w, r, s.revokeSession(w, r))
+}
+
+func (s *Handlers) revokeSession(w http.ResponseWriter, r *http.Request) error {
+	if s.sessionsSvc == nil {
+		return &RequestError{StatusCode: http.StatusServiceUnavailable, Err: errSessionsDisabled}
+	}
+
+	claims, err := s.authorize(r)
+	if err != nil {
+		return err
+	}
+
+	var req struct {
+		ID  string `json:"id"`
+		All bool   `json:"all"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("invalid request body")}
+	}
+
+	if req.All {
+		n, err := s.sessionsSvc.RevokeAll(r.Context(), claims.UserID, sessions.Current{JTI: claims.JTI})
+		if err != nil {
+			return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed to revoke sessions")}
+		}
+		platform.JSON(w, r, http.StatusOK, struct {
+			Revoked int64 `json:"revoked"`
+		}{
+			Revoked: n,
+		})
+		return nil
+	}
+
+	if req.ID == "" {
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: errors.New("id or all is required")}
+	}
+	if err := s.sessionsSvc.Revoke(r.Context(), claims.UserID, req.ID); err != nil {
+		return sessionError(err, "failed to revoke session")
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// sessionError maps a sessions service error to a RequestError.
+func sessionError(err error, message string) error {
+	switch {
+	case errors.Is(err, model.ErrSessionNotFound):
+		return &RequestError{StatusCode: http.StatusNotFound, Err: err}
+	case errors.Is(err, model.ErrSessionNameTooLong):
+		return &RequestError{StatusCode: http.StatusBadRequest, Err: err}
+	}
+	return &RequestError{StatusCode: http.StatusInternalServerError, Err: errors.New(message)}
+}
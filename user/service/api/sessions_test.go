@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/auth"
+	"github.com/titpetric/platform-app/user/service/sessions"
+)
+
+type mockSessionStorage struct {
+	model.SessionStorage
+}
+
+func (mockSessionStorage) List(context.Context, string) ([]model.UserSession, error) {
+	return []model.UserSession{{ID: "session-1", UserID: "user-1"}}, nil
+}
+
+func (mockSessionStorage) Revoke(_ context.Context, _, id string) error {
+	if id != "session-1" {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (mockSessionStorage) RevokeAll(context.Context, string, string) (int64, error) {
+	return 1, nil
+}
+
+type mockTokenStorage struct {
+	model.TokenStorage
+}
+
+func (mockTokenStorage) List(context.Context, string) ([]model.UserToken, error) {
+	return []model.UserToken{{Jti: "jti-1", UserID: "user-1"}}, nil
+}
+
+func (mockTokenStorage) Revoke(context.Context, string, string) error {
+	return sql.ErrNoRows
+}
+
+func (mockTokenStorage) RevokeAll(context.Context, string, string) (int64, error) {
+	return 2, nil
+}
+
+func newSessionsHandlers() *Handlers {
+	return NewHandlers(Options{
+		SigningKey:      getTestSigningKey(),
+		SessionsService: sessions.New(mockSessionStorage{}, mockTokenStorage{}),
+	})
+}
+
+func newSessionsRequest(t *testing.T, method, path, body string) *http.Request {
+	t.Helper()
+
+	token, err := auth.NewJWT(getTestSigningKey()).Create("user-1", time.Hour)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestSessionsNotConfigured(t *testing.T) {
+	t.Parallel()
+
+	svc := NewHandlers(Options{SigningKey: getTestSigningKey()})
+	for _, handler := range []http.HandlerFunc{svc.ListSessions, svc.NameSession, svc.RevokeSession} {
+		w := httptest.NewRecorder()
+
+		handler(w, newSessionsRequest(t, http.MethodPost, "/api/user/sessions", `{}`))
+
+		require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	newSessionsHandlers().ListSessions(w, newSessionsRequest(t, http.MethodGet, "/api/user/sessions", ``))
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Sessions []sessions.Entry `json:"sessions"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Len(t, resp.Sessions, 2)
+}
+
+func TestRevokeSession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"missing id", `{}`, http.StatusBadRequest},
+		{"unknown id", `{"id":"session-2"}`, http.StatusNotFound},
+		{"revoked", `{"id":"session-1"}`, http.StatusNoContent},
+		{"all", `{"all":true}`, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newSessionsHandlers().RevokeSession(w, newSessionsRequest(t, http.MethodPost, "/api/user/sessions/revoke", tc.body))
+
+			require.Equal(t, tc.want, w.Code)
+		})
+	}
+
+	t.Run("missing authorization", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/sessions/revoke", bytes.NewBufferString(`{"all":true}`))
+		w := httptest.NewRecorder()
+
+		newSessionsHandlers().RevokeSession(w, req)
+
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
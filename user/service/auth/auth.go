@@ -23,6 +23,9 @@ type (
 		JTI string `json:"jti"`
 		// ExpiresAt is the unix timestamp from the `exp` claim, if present.
 		ExpiresAt int64 `json:"exp"`
+		// IssuedAt is the unix timestamp from the `iat` claim. Tokens
+		// issued before the claim was added read as zero.
+		IssuedAt int64 `json:"iat"`
 
 		jwt.MapClaims
 	}
@@ -80,6 +83,9 @@ func (u *JWT) Claims(tokenString string) (*Claims, error) {
 			if exp, ok := claims["exp"].(float64); ok {
 				c.ExpiresAt = int64(exp)
 			}
+			if iat, ok := claims["iat"].(float64); ok {
+				c.IssuedAt = int64(iat)
+			}
 			return c, nil
 		}
 	}
@@ -119,10 +125,12 @@ func (u *JWT) CreateWithJTI(userID string, ttl time.Duration) (string, string, e
 	}
 
 	jti := ulid.String()
+	now := time.Now()
 	claims := jwt.MapClaims{}
 	claims["user_id"] = userID
 	claims["jti"] = jti
-	claims["exp"] = time.Now().Add(ttl).Unix()
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(ttl).Unix()
 
 	at := jwt.NewWithClaims(u.signingMethod, claims)
 	signed, err := at.SignedString(signingSecret())
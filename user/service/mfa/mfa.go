@@ -0,0 +1,265 @@
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+const (
+	// PendingTTL is how long a user has to enter a code after their
+	// password was accepted.
+	PendingTTL = 5 * time.Minute
+
+	// MaxAttempts is the number of wrong codes accepted for a pending
+	// login before the user has to enter their password again.
+	MaxAttempts = 5
+
+	// RecoveryCodes is the number of recovery codes issued when MFA is
+	// enabled.
+	RecoveryCodes = 10
+)
+
+// recoveryEncoding encodes recovery codes in lowercase letters and
+// digits, without ones easily confused.
+var recoveryEncoding = base32.NewEncoding("abcdefghijkmnpqrstuvwxyz23456789").WithPadding(base32.NoPadding)
+
+// Service handles TOTP enrollment and the second login step.
+type Service struct {
+	storage model.MFAStorage
+	issuer  string
+}
+
+// Enrollment holds the secret of a new enrollment, to be added to an
+// authenticator app.
+type Enrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// New creates a new MFA Service. The issuer names the service in
+// authenticator apps.
+func New(storage model.MFAStorage, issuer string) *Service {
+	return &Service{
+		storage: storage,
+		issuer:  issuer,
+	}
+}
+
+// Enabled reports whether the user has MFA enabled.
+func (s *Service) Enabled(ctx context.Context, userID string) (bool, error) {
+	record, err := s.storage.Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return record.EnabledAt != nil, nil
+}
+
+// Enroll generates a new secret for the user. MFA isn't enabled until a
+// first code is confirmed with Confirm. The account names the user in
+// authenticator apps, usually their email.
+func (s *Service) Enroll(ctx context.Context, userID, account string) (*Enrollment, error) {
+	secret := NewSecret()
+	if err := s.storage.Enroll(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+	return &Enrollment{
+		Secret: secret,
+		URI:    ProvisioningURI(s.issuer, account, secret),
+	}, nil
+}
+
+// Enrollment returns the unconfirmed enrollment of the user, or enrolls
+// them if there is none. Unlike Enroll, reloading a setup page keeps the
+// secret already added to an authenticator app.
+func (s *Service) Enrollment(ctx context.Context, userID, account string) (*Enrollment, error) {
+	record, err := s.storage.Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.Enroll(ctx, userID, account)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.EnabledAt != nil {
+		return nil, model.ErrMFAAlreadyEnabled
+	}
+	return &Enrollment{
+		Secret: record.Secret,
+		URI:    ProvisioningURI(s.issuer, account, record.Secret),
+	}, nil
+}
+
+// Confirm enables MFA for an enrolled user with a first code from their
+// authenticator app, and returns the recovery codes. The recovery codes
+// are only stored hashed, so they can't be shown again.
+func (s *Service) Confirm(ctx context.Context, userID, code string) ([]string, error) {
+	record, err := s.storage.Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrMFANotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if record.EnabledAt != nil {
+		return nil, model.ErrMFAAlreadyEnabled
+	}
+
+	step, ok := Validate(record.Secret, code, time.Now())
+	if !ok {
+		return nil, model.ErrInvalidMFACode
+	}
+
+	codes := make([]string, RecoveryCodes)
+	for i := range codes {
+		codes[i] = newRecoveryCode()
+	}
+	if err := s.storage.Enable(ctx, userID, step, codes); err != nil {
+		return nil, err
+	}
+
+	for i, code := range codes {
+		codes[i] = code[:5] + "-" + code[5:]
+	}
+	return codes, nil
+}
+
+// Disable turns off MFA for the user, given a current code or an unused
+// recovery code.
+func (s *Service) Disable(ctx context.Context, userID, code string) error {
+	if err := s.check(ctx, userID, code); err != nil {
+		return err
+	}
+	return s.storage.Disable(ctx, userID)
+}
+
+// RecoveryCodesLeft returns the number of unused recovery codes of the
+// user.
+func (s *Service) RecoveryCodesLeft(ctx context.Context, userID string) (int, error) {
+	return s.storage.CountRecoveryCodes(ctx, userID)
+}
+
+// Begin starts the second login step for a user whose password was
+// accepted, and returns the token of the pending login. Pending logins
+// are stored, so they survive restarts and work across instances.
+func (s *Service) Begin(ctx context.Context, userID string) (string, error) {
+	token := newToken()
+	if err := s.storage.CreatePending(ctx, token, userID, PendingTTL); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Pending reports whether token is a pending login.
+func (s *Service) Pending(ctx context.Context, token string) bool {
+	if token == "" {
+		return false
+	}
+	_, err := s.storage.GetPending(ctx, token)
+	return err == nil
+}
+
+// Verify completes a pending login with a code from the authenticator app
+// or a recovery code, and returns the user ID. The pending login is
+// dropped after MaxAttempts wrong codes. Attempts are counted before the
+// code is checked, so parallel requests can't try more codes.
+func (s *Service) Verify(ctx context.Context, token, code string) (string, error) {
+	login, err := s.storage.AttemptPending(ctx, token, MaxAttempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", model.ErrMFAPendingExpired
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.check(ctx, login.UserID, code); err != nil {
+		if errors.Is(err, model.ErrInvalidMFACode) && login.Attempts >= MaxAttempts {
+			if _, derr := s.storage.DeletePending(ctx, token); derr != nil {
+				return "", derr
+			}
+		}
+		return "", err
+	}
+
+	deleted, err := s.storage.DeletePending(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	if !deleted {
+		return "", model.ErrMFAPendingExpired
+	}
+	return login.UserID, nil
+}
+
+// check accepts a TOTP code or a recovery code of the user. Each code is
+// accepted once.
+func (s *Service) check(ctx context.Context, userID, code string) error {
+	record, err := s.storage.Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ErrMFANotEnrolled
+	}
+	if err != nil {
+		return err
+	}
+	if record.EnabledAt == nil {
+		return model.ErrMFANotEnrolled
+	}
+
+	if step, ok := Validate(record.Secret, code, time.Now()); ok {
+		used, err := s.storage.UseStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return model.ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	used, err := s.storage.UseRecoveryCode(ctx, userID, normalizeRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return model.ErrInvalidMFACode
+	}
+	return nil
+}
+
+// normalizeRecoveryCode strips the separator and whitespace from a
+// recovery code as typed by the user.
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+}
+
+// newRecoveryCode returns a random 10 character recovery code.
+func newRecoveryCode() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("user/mfa: crypto/rand failed: " + err.Error())
+	}
+	return recoveryEncoding.EncodeToString(b[:])[:10]
+}
+
+// newToken returns an opaque url-safe pending login token.
+func newToken() string {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic("user/mfa: crypto/rand failed: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
@@ -0,0 +1,229 @@
+package mfa
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+type mockStorage struct {
+	records  map[string]*model.UserMFA
+	recovery map[string]bool
+	pending  map[string]*model.UserMFAPending
+}
+
+func newMockStorage() *mockStorage {
+	return &mockStorage{
+		records:  make(map[string]*model.UserMFA),
+		recovery: make(map[string]bool),
+	}
+}
+
+func (m *mockStorage) Get(_ context.Context, userID string) (*model.UserMFA, error) {
+	record, ok := m.records[userID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return record, nil
+}
+
+func (m *mockStorage) Enroll(_ context.Context, userID, secret string) error {
+	if record, ok := m.records[userID]; ok && record.EnabledAt != nil {
+		return model.ErrMFAAlreadyEnabled
+	}
+	m.records[userID] = &model.UserMFA{UserID: userID, Secret: secret}
+	return nil
+}
+
+func (m *mockStorage) Enable(_ context.Context, userID string, step int64, codes []string) error {
+	record, ok := m.records[userID]
+	if !ok || record.EnabledAt != nil {
+		return model.ErrMFANotEnrolled
+	}
+	record.SetEnabledAt(time.Now())
+	record.LastStep = step
+	for _, code := range codes {
+		m.recovery[code] = true
+	}
+	return nil
+}
+
+func (m *mockStorage) Disable(_ context.Context, userID string) error {
+	delete(m.records, userID)
+	m.recovery = make(map[string]bool)
+	return nil
+}
+
+func (m *mockStorage) UseStep(_ context.Context, userID string, step int64) (bool, error) {
+	record := m.records[userID]
+	if record.LastStep >= step {
+		return false, nil
+	}
+	record.LastStep = step
+	return true, nil
+}
+
+func (m *mockStorage) UseRecoveryCode(_ context.Context, _, code string) (bool, error) {
+	if !m.recovery[code] {
+		return false, nil
+	}
+	m.recovery[code] = false
+	return true, nil
+}
+
+func (m *mockStorage) CountRecoveryCodes(context.Context, string) (int, error) {
+	count := 0
+	for _, unused := range m.recovery {
+		if unused {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockStorage) CreatePending(_ context.Context, token, userID string, ttl time.Duration) error {
+	if m.pending == nil {
+		m.pending = make(map[string]*model.UserMFAPending)
+	}
+	m.pending[token] = &model.UserMFAPending{UserID: userID}
+	m.pending[token].SetExpiresAt(time.Now().Add(ttl))
+	return nil
+}
+
+func (m *mockStorage) GetPending(_ context.Context, token string) (*model.UserMFAPending, error) {
+	pending, ok := m.pending[token]
+	if !ok || time.Now().After(*pending.ExpiresAt) {
+		return nil, sql.ErrNoRows
+	}
+	return pending, nil
+}
+
+func (m *mockStorage) AttemptPending(ctx context.Context, token string, maxAttempts int) (*model.UserMFAPending, error) {
+	pending, err := m.GetPending(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if pending.Attempts >= int64(maxAttempts) {
+		return nil, sql.ErrNoRows
+	}
+	pending.Attempts++
+	return pending, nil
+}
+
+func (m *mockStorage) DeletePending(_ context.Context, token string) (bool, error) {
+	_, ok := m.pending[token]
+	delete(m.pending, token)
+	return ok, nil
+}
+
+// codeAt returns the code of a secret offset periods from now.
+func codeAt(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	code, err := Code(secret, Step(time.Now())+offset)
+	require.NoError(t, err)
+	return code
+}
+
+func TestService(t *testing.T) {
+	ctx := t.Context()
+	storage := newMockStorage()
+	s := New(storage, "Platform App")
+
+	enabled, err := s.Enabled(ctx, "user-1")
+	require.NoError(t, err)
+	require.False(t, enabled)
+
+	_, err = s.Confirm(ctx, "user-1", "123456")
+	require.ErrorIs(t, err, model.ErrMFANotEnrolled)
+
+	enrollment, err := s.Enroll(ctx, "user-1", "me@titpetric.com")
+	require.NoError(t, err)
+	require.NotEmpty(t, enrollment.URI)
+
+	again, err := s.Enrollment(ctx, "user-1", "me@titpetric.com")
+	require.NoError(t, err)
+	require.Equal(t, enrollment.Secret, again.Secret)
+
+	_, err = s.Confirm(ctx, "user-1", "000000x")
+	require.ErrorIs(t, err, model.ErrInvalidMFACode)
+
+	// The confirmed code is used up, so the next login needs a later one.
+	codes, err := s.Confirm(ctx, "user-1", codeAt(t, enrollment.Secret, -1))
+	require.NoError(t, err)
+	require.Equal(t, RecoveryCodes, len(codes))
+	require.Equal(t, 11, len(codes[0]))
+
+	enabled, err = s.Enabled(ctx, "user-1")
+	require.NoError(t, err)
+	require.True(t, enabled)
+
+	_, err = s.Enroll(ctx, "user-1", "me@titpetric.com")
+	require.ErrorIs(t, err, model.ErrMFAAlreadyEnabled)
+	_, err = s.Enrollment(ctx, "user-1", "me@titpetric.com")
+	require.ErrorIs(t, err, model.ErrMFAAlreadyEnabled)
+
+	begin := func(t *testing.T) string {
+		t.Helper()
+		token, err := s.Begin(ctx, "user-1")
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("verify with code", func(t *testing.T) {
+		token := begin(t)
+		require.True(t, s.Pending(ctx, token))
+
+		_, err := s.Verify(ctx, token, codeAt(t, enrollment.Secret, -1))
+		require.ErrorIs(t, err, model.ErrInvalidMFACode)
+
+		userID, err := s.Verify(ctx, token, codeAt(t, enrollment.Secret, 0))
+		require.NoError(t, err)
+		require.Equal(t, "user-1", userID)
+		require.False(t, s.Pending(ctx, token))
+
+		_, err = s.Verify(ctx, token, codeAt(t, enrollment.Secret, 1))
+		require.ErrorIs(t, err, model.ErrMFAPendingExpired)
+	})
+
+	t.Run("verify with recovery code", func(t *testing.T) {
+		token := begin(t)
+		userID, err := s.Verify(ctx, token, " "+codes[0]+" ")
+		require.NoError(t, err)
+		require.Equal(t, "user-1", userID)
+
+		left, err := s.RecoveryCodesLeft(ctx, "user-1")
+		require.NoError(t, err)
+		require.Equal(t, RecoveryCodes-1, left)
+
+		_, err = s.Verify(ctx, begin(t), codes[0])
+		require.ErrorIs(t, err, model.ErrInvalidMFACode)
+	})
+
+	t.Run("too many attempts", func(t *testing.T) {
+		token := begin(t)
+		for range MaxAttempts {
+			_, err := s.Verify(ctx, token, "bogus")
+			require.ErrorIs(t, err, model.ErrInvalidMFACode)
+		}
+		require.False(t, s.Pending(ctx, token))
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		require.ErrorIs(t, s.Disable(ctx, "user-1", "bogus"), model.ErrInvalidMFACode)
+		require.NoError(t, s.Disable(ctx, "user-1", codes[1]))
+
+		enabled, err := s.Enabled(ctx, "user-1")
+		require.NoError(t, err)
+		require.False(t, enabled)
+	})
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	require.Equal(t, "abcdefghij", normalizeRecoveryCode(" ABCDE-fghij\n"))
+	require.Equal(t, 10, len(newRecoveryCode()))
+}
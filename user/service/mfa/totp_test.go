@@ -0,0 +1,73 @@
+package mfa
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/titpetric/platform/pkg/require"
+)
+
+// rfcSecret is the SHA1 secret of the RFC 6238 test vectors,
+// "12345678901234567890" base32 encoded.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to 6 digits.
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range vectors {
+		code, err := Code(rfcSecret, Step(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		require.Equal(t, want, code)
+	}
+
+	_, err := Code("not base32!", 1)
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	step, ok := Validate(rfcSecret, "081804", now)
+	require.True(t, ok)
+	require.Equal(t, Step(now), step)
+
+	// Codes of the neighbouring periods are accepted.
+	step, ok = Validate(rfcSecret, "081804", now.Add(Period))
+	require.True(t, ok)
+	require.Equal(t, Step(now), step)
+
+	_, ok = Validate(rfcSecret, "081804", now.Add(2*Period))
+	require.False(t, ok)
+	_, ok = Validate(rfcSecret, "000000", now)
+	require.False(t, ok)
+	_, ok = Validate(rfcSecret, "81804", now)
+	require.False(t, ok)
+}
+
+func TestNewSecret(t *testing.T) {
+	secret := NewSecret()
+	require.Equal(t, 32, len(secret))
+	require.NotEqual(t, secret, NewSecret())
+
+	_, err := Code(secret, 1)
+	require.NoError(t, err)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	u, err := url.Parse(ProvisioningURI("Platform App", "me@titpetric.com", rfcSecret))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/Platform App:me@titpetric.com", u.Path)
+	require.Equal(t, rfcSecret, u.Query().Get("secret"))
+	require.Equal(t, "Platform App", u.Query().Get("issuer"))
+	require.Equal(t, "6", u.Query().Get("digits"))
+	require.Equal(t, "30", u.Query().Get("period"))
+}
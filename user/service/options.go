@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"time"
+
+	"github.com/titpetric/platform-app/user/service/recovery"
+	"github.com/titpetric/platform-app/user/storage"
 )
 
 // Options is passed from user package scope. Every field has a defensible
@@ -15,6 +18,11 @@ type Options struct {
 	// DefaultTokenTTL is used.
 	TokenTTL time.Duration
 
+	// SessionTTL is how long web sessions stay valid without use. Each
+	// use slides the expiry forward, and sessions keep the TTL they
+	// were created with. If zero, DefaultSessionTTL is used.
+	SessionTTL time.Duration
+
 	// EmailActivationEnabled gates account activation behind an email
 	// confirmation step. When false (the default), users are activated
 	// on creation; the email may still be confirmed via a separate
@@ -40,6 +48,41 @@ type Options struct {
 	// ActivationSubject overrides the subject line of activation
 	// emails. When empty, DefaultActivationSubject is used.
 	ActivationSubject string
+
+	// PasswordResetURLFormat is a Sprintf-style template that the reset
+	// token is substituted into when composing the password reset
+	// email. Example:
+	//   "https://example.com/reset-password?token=%s"
+	// When empty, the email contains the bare token. The forgotten
+	// password flow is only enabled when EmailSender is set.
+	PasswordResetURLFormat string
+
+	// PasswordResetSubject overrides the subject line of password reset
+	// emails. When empty, DefaultPasswordResetSubject is used.
+	PasswordResetSubject string
+
+	// PasswordResetTTL is how long a password reset token stays valid.
+	// If zero, DefaultPasswordResetTTL is used.
+	PasswordResetTTL time.Duration
+
+	// PoliciesEnabled enforces the password expiry and email
+	// re-verification policies of opa/flows.yml. When false (the
+	// default), neither is enforced. Email re-verification mails a
+	// link, so when it is enabled by the email_reverify_days feature
+	// and EmailSender is nil, the user module fails to start.
+	PoliciesEnabled bool
+
+	// EmailVerifyURLFormat is a Sprintf-style template that the
+	// verification token is substituted into when composing the email
+	// re-verification email. Example:
+	//   "https://example.com/verify-email?token=%s"
+	// When empty, the email contains the bare token.
+	EmailVerifyURLFormat string
+
+	// MFAIssuer names the service in authenticator apps when users
+	// enroll in TOTP multi-factor authentication. When empty,
+	// DefaultMFAIssuer is used.
+	MFAIssuer string
 }
 
 // EmailSender is the minimal contract the user module needs to deliver
@@ -57,6 +100,18 @@ const (
 	// DefaultTokenTTL preserves the previously-hardcoded value of 30 days.
 	DefaultTokenTTL = 30 * 24 * time.Hour
 
+	// DefaultSessionTTL is used when Options.SessionTTL is zero.
+	DefaultSessionTTL = storage.DefaultSessionTTL
+
 	// DefaultActivationSubject is used when Options.ActivationSubject is empty.
 	DefaultActivationSubject = "Confirm your account"
+
+	// DefaultPasswordResetSubject is used when Options.PasswordResetSubject is empty.
+	DefaultPasswordResetSubject = recovery.DefaultSubject
+
+	// DefaultPasswordResetTTL is used when Options.PasswordResetTTL is zero.
+	DefaultPasswordResetTTL = recovery.DefaultTTL
+
+	// DefaultMFAIssuer is used when Options.MFAIssuer is empty.
+	DefaultMFAIssuer = "Platform App"
 )
@@ -0,0 +1,184 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/titpetric/platform-app/user/flow"
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// EmailSender delivers the email verification emails. It mirrors
+// service.EmailSender but is re-declared here so this package does not
+// import its parent.
+type EmailSender interface {
+	Send(ctx context.Context, recipient, subject, body string) error
+}
+
+// Flow and step names of the policies in opa/flows.yml.
+const (
+	// Flow is the flow holding the policy steps.
+	Flow = "policies"
+
+	// PasswordExpired is the step asking for a new password once the
+	// password is older than the password_expiry_days feature.
+	PasswordExpired = "password_expired"
+
+	// EmailReverify is the step asking to verify the email again once
+	// it was last verified longer ago than the email_reverify_days
+	// feature.
+	EmailReverify = "email_reverify"
+)
+
+// Default values applied when the corresponding Options fields are zero.
+const (
+	// DefaultTTL is how long an email verification token stays valid.
+	DefaultTTL = 24 * time.Hour
+
+	// DefaultSubject is the subject line of email verification emails.
+	DefaultSubject = "Verify your email"
+)
+
+// Options configures email verification.
+type Options struct {
+	EmailSender EmailSender
+
+	// URLFormat is a Sprintf-style template the verification token is
+	// substituted into, e.g. "https://example.com/verify-email?token=%s".
+	// When empty, the email contains the bare token.
+	URLFormat string
+
+	// Subject of verification emails. If empty, DefaultSubject is used.
+	Subject string
+
+	// TTL of verification tokens. If zero, DefaultTTL is used.
+	TTL time.Duration
+}
+
+// Service enforces the password expiry and email re-verification
+// policies. Which policies apply, and their periods, is configured by
+// the policies flow and features of opa/flows.yml.
+type Service struct {
+	storage model.PolicyStorage
+	flows   *flow.Engine
+	opts    Options
+}
+
+// New creates a new policy Service.
+func New(storage model.PolicyStorage, flows *flow.Engine, opts Options) *Service {
+	if opts.Subject == "" {
+		opts.Subject = DefaultSubject
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	return &Service{
+		storage: storage,
+		flows:   flows,
+		opts:    opts,
+	}
+}
+
+// Check returns the first enabled policy step the user has to complete,
+// in flow order, or false if there is none. Users exempted by an admin
+// have none.
+func (s *Service) Check(ctx context.Context, userID string) (flow.Step, bool, error) {
+	auth, err := s.storage.GetPolicy(ctx, userID)
+	if err != nil {
+		return flow.Step{}, false, err
+	}
+	if auth.PolicyExempt != 0 {
+		return flow.Step{}, false, nil
+	}
+
+	now := time.Now()
+	for _, step := range s.flows.Steps(Flow) {
+		if s.flows.Enabled(step) && s.due(step.Name, auth, now) {
+			return step, true, nil
+		}
+	}
+	return flow.Step{}, false, nil
+}
+
+// due reports whether the named policy step applies to the user.
+func (s *Service) due(step string, auth *model.UserAuth, now time.Time) bool {
+	switch step {
+	case PasswordExpired:
+		return expired(auth.PasswordChangedAt, auth.CreatedAt, s.flows.FeatureInt("password_expiry_days"), now)
+	case EmailReverify:
+		return expired(auth.EmailVerifiedAt, auth.CreatedAt, s.flows.FeatureInt("email_reverify_days"), now)
+	}
+	return false
+}
+
+// expired reports whether more than days have passed since since, or
+// since created if since is unset. Zero days never expire.
+func expired(since, created *time.Time, days int, now time.Time) bool {
+	if days <= 0 {
+		return false
+	}
+	if since == nil {
+		since = created
+	}
+	if since == nil {
+		return false
+	}
+	return now.After(since.AddDate(0, 0, days))
+}
+
+// Error returns the error of a policy step, for API clients.
+func Error(step flow.Step) error {
+	switch step.Name {
+	case PasswordExpired:
+		return model.ErrPasswordExpired
+	case EmailReverify:
+		return model.ErrEmailReverifyRequired
+	}
+	return fmt.Errorf("policy %s required", step.Name)
+}
+
+// ChangePassword sets a new password for the user, given their current
+// one, which completes the password expiry policy.
+func (s *Service) ChangePassword(ctx context.Context, userID, current, password string) error {
+	return s.storage.ChangePassword(ctx, userID, current, password)
+}
+
+// RequestVerification mails an email verification token to the user.
+func (s *Service) RequestVerification(ctx context.Context, userID string) error {
+	if s.opts.EmailSender == nil {
+		return fmt.Errorf("send email verification: no email sender")
+	}
+
+	token, email, err := s.storage.CreateEmailVerification(ctx, userID, s.opts.TTL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.opts.EmailSender.Send(ctx, email, s.opts.Subject, s.body(token)); err != nil {
+		return fmt.Errorf("send email verification: %w", err)
+	}
+	return nil
+}
+
+// ValidVerifyToken reports whether the verification token can be used,
+// without using it.
+func (s *Service) ValidVerifyToken(ctx context.Context, token string) bool {
+	return s.storage.CheckEmailVerification(ctx, token) == nil
+}
+
+// VerifyEmail exchanges a verification token, which completes the email
+// re-verification policy of its user.
+func (s *Service) VerifyEmail(ctx context.Context, token string) (*model.User, error) {
+	return s.storage.VerifyEmail(ctx, token)
+}
+
+// body renders the email body. If the URLFormat option is set, the token
+// is interpolated into it and offered as a link; otherwise the token is
+// included verbatim with a short instruction.
+func (s *Service) body(token string) string {
+	if s.opts.URLFormat != "" {
+		return fmt.Sprintf("Please confirm this is still your email address by following this link:\n\n%s\n", fmt.Sprintf(s.opts.URLFormat, token))
+	}
+	return fmt.Sprintf("Please confirm this is still your email address by entering the following token at /verify-email:\n\n%s\n", token)
+}
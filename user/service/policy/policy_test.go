@@ -0,0 +1,153 @@
+package policy
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/flow"
+	"github.com/titpetric/platform-app/user/model"
+)
+
+const testFlows = `
+features:
+  password_expiry_days: 30
+  email_reverify_days: 180
+
+flows:
+  policies:
+    steps:
+      - name: password_expired
+        enabled_if: features.password_expiry_days > 0
+        view: password_expired.vuego
+        link: /password-expired
+        next:
+          ok: email_reverify
+      - name: email_reverify
+        enabled_if: features.email_reverify_days > 0
+        view: email_reverify.vuego
+        link: /verify-email
+        next:
+          ok: done
+      - name: done
+        link: /
+`
+
+type mockStorage struct {
+	auth  *model.UserAuth
+	email string
+	ttl   time.Duration
+}
+
+func (m *mockStorage) GetPolicy(context.Context, string) (*model.UserAuth, error) {
+	return m.auth, nil
+}
+
+func (m *mockStorage) ChangePassword(context.Context, string, string, string) error {
+	return nil
+}
+
+func (m *mockStorage) CreateEmailVerification(_ context.Context, _ string, ttl time.Duration) (string, string, error) {
+	m.ttl = ttl
+	return "verify-token", m.email, nil
+}
+
+func (m *mockStorage) CheckEmailVerification(context.Context, string) error {
+	return nil
+}
+
+func (m *mockStorage) VerifyEmail(context.Context, string) (*model.User, error) {
+	return &model.User{ID: "user-1"}, nil
+}
+
+type mockSender struct {
+	recipient, subject, body string
+}
+
+func (m *mockSender) Send(_ context.Context, recipient, subject, body string) error {
+	m.recipient, m.subject, m.body = recipient, subject, body
+	return nil
+}
+
+func newEngine(t *testing.T, yaml string) *flow.Engine {
+	t.Helper()
+
+	cfg, err := flow.Parse([]byte(yaml))
+	require.NoError(t, err)
+	engine, err := flow.New(cfg)
+	require.NoError(t, err)
+	return engine
+}
+
+func daysAgo(days int) *time.Time {
+	t := time.Now().AddDate(0, 0, -days)
+	return &t
+}
+
+func TestService_Check(t *testing.T) {
+	ctx := t.Context()
+	engine := newEngine(t, testFlows)
+
+	tests := []struct {
+		name string
+		auth *model.UserAuth
+		want string
+	}{
+		{"up to date", &model.UserAuth{CreatedAt: daysAgo(400), PasswordChangedAt: daysAgo(1), EmailVerifiedAt: daysAgo(1)}, ""},
+		{"password expired", &model.UserAuth{PasswordChangedAt: daysAgo(31), EmailVerifiedAt: daysAgo(1)}, PasswordExpired},
+		{"email reverify", &model.UserAuth{PasswordChangedAt: daysAgo(1), EmailVerifiedAt: daysAgo(181)}, EmailReverify},
+		{"password first", &model.UserAuth{PasswordChangedAt: daysAgo(31), EmailVerifiedAt: daysAgo(181)}, PasswordExpired},
+		{"falls back to created_at", &model.UserAuth{CreatedAt: daysAgo(31), EmailVerifiedAt: daysAgo(1)}, PasswordExpired},
+		{"exempt", &model.UserAuth{PasswordChangedAt: daysAgo(400), EmailVerifiedAt: daysAgo(400), PolicyExempt: 1}, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := New(&mockStorage{auth: tc.auth}, engine, Options{})
+
+			step, ok, err := s.Check(ctx, "user-1")
+			require.NoError(t, err)
+			require.Equal(t, tc.want != "", ok)
+			require.Equal(t, tc.want, step.Name)
+		})
+	}
+
+	t.Run("disabled policies", func(t *testing.T) {
+		yaml := strings.Replace(testFlows, "password_expiry_days: 30", "password_expiry_days: 0", 1)
+		auth := &model.UserAuth{PasswordChangedAt: daysAgo(400), EmailVerifiedAt: daysAgo(1)}
+		s := New(&mockStorage{auth: auth}, newEngine(t, yaml), Options{})
+
+		_, ok, err := s.Check(ctx, "user-1")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestService_RequestVerification(t *testing.T) {
+	ctx := t.Context()
+	engine := newEngine(t, testFlows)
+	storage := &mockStorage{email: "me@titpetric.com"}
+
+	require.Error(t, New(storage, engine, Options{}).RequestVerification(ctx, "user-1"))
+
+	sender := &mockSender{}
+	s := New(storage, engine, Options{
+		EmailSender: sender,
+		URLFormat:   "https://example.com/verify-email?token=%s",
+	})
+
+	require.NoError(t, s.RequestVerification(ctx, "user-1"))
+	require.Equal(t, DefaultTTL, storage.ttl)
+	require.Equal(t, "me@titpetric.com", sender.recipient)
+	require.Equal(t, DefaultSubject, sender.subject)
+	require.Contains(t, sender.body, "https://example.com/verify-email?token=verify-token")
+}
+
+func TestError(t *testing.T) {
+	require.ErrorIs(t, Error(flow.Step{Name: PasswordExpired}), model.ErrPasswordExpired)
+	require.ErrorIs(t, Error(flow.Step{Name: EmailReverify}), model.ErrEmailReverifyRequired)
+	require.Error(t, Error(flow.Step{Name: "other"}))
+}
@@ -0,0 +1,121 @@
+package recovery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// EmailSender delivers the password reset emails. It mirrors
+// service.EmailSender but is re-declared here so this package does not
+// import its parent.
+type EmailSender interface {
+	Send(ctx context.Context, recipient, subject, body string) error
+}
+
+// Default values applied when the corresponding Options fields are zero.
+const (
+	// DefaultTTL is how long a password reset token stays valid.
+	DefaultTTL = time.Hour
+
+	// DefaultSubject is the subject line of password reset emails.
+	DefaultSubject = "Reset your password"
+)
+
+// Options configures the password reset flow.
+type Options struct {
+	EmailSender EmailSender
+
+	// URLFormat is a Sprintf-style template the reset token is
+	// substituted into, e.g. "https://example.com/reset-password?token=%s".
+	// When empty, the email contains the bare token.
+	URLFormat string
+
+	// Subject of reset emails. If empty, DefaultSubject is used.
+	Subject string
+
+	// TTL of reset tokens. If zero, DefaultTTL is used.
+	TTL time.Duration
+}
+
+// Service implements the forgotten password flow: it issues reset tokens,
+// mails them to the user and exchanges them for a new password.
+type Service struct {
+	storage model.PasswordResetStorage
+	opts    Options
+}
+
+// New creates a new password reset Service.
+func New(storage model.PasswordResetStorage, opts Options) *Service {
+	if opts.Subject == "" {
+		opts.Subject = DefaultSubject
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultTTL
+	}
+	return &Service{
+		storage: storage,
+		opts:    opts,
+	}
+}
+
+// Request mails a password reset token to email. Unknown emails are
+// ignored without an error, so the flow can't be used to find out which
+// emails have an account.
+func (s *Service) Request(ctx context.Context, email string) error {
+	if email == "" {
+		return model.ErrEmailMissing
+	}
+
+	token, _, err := s.storage.CreatePasswordReset(ctx, email, s.opts.TTL)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.opts.EmailSender.Send(ctx, email, s.opts.Subject, s.body(token)); err != nil {
+		return fmt.Errorf("send password reset: %w", err)
+	}
+	return nil
+}
+
+// Valid reports whether the reset token can be used, without using it.
+func (s *Service) Valid(ctx context.Context, token string) bool {
+	return s.storage.CheckPasswordReset(ctx, token) == nil
+}
+
+// Reset sets a new password for the user the token was issued to. All
+// sessions and tokens of the user are revoked.
+func (s *Service) Reset(ctx context.Context, token, password string) (*model.User, error) {
+	return s.storage.ResetPassword(ctx, token, password)
+}
+
+// body renders the email body. If the URLFormat option is set, the token
+// is interpolated into it and offered as a link; otherwise the token is
+// included verbatim with a short instruction.
+func (s *Service) body(token string) string {
+	expires := formatTTL(s.opts.TTL)
+	if s.opts.URLFormat != "" {
+		return fmt.Sprintf("Someone asked to reset the password of your account. To choose a new password, follow this link within %s:\n\n%s\n\nIf it wasn't you, you can ignore this email.\n", expires, fmt.Sprintf(s.opts.URLFormat, token))
+	}
+	return fmt.Sprintf("Someone asked to reset the password of your account. To choose a new password, enter the following token at /reset-password within %s:\n\n%s\n\nIf it wasn't you, you can ignore this email.\n", expires, token)
+}
+
+// formatTTL formats a token lifetime for the email body, in hours when
+// it is a whole number of them and in minutes otherwise.
+func formatTTL(ttl time.Duration) string {
+	unit, n := "minute", int64(max(ttl.Round(time.Minute)/time.Minute, 1))
+	if ttl%time.Hour == 0 {
+		unit, n = "hour", int64(ttl/time.Hour)
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
@@ -0,0 +1,106 @@
+package recovery
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+type mockStorage struct {
+	emails map[string]string
+	ttl    time.Duration
+}
+
+func (m *mockStorage) CreatePasswordReset(_ context.Context, email string, ttl time.Duration) (string, string, error) {
+	userID, ok := m.emails[email]
+	if !ok {
+		return "", "", sql.ErrNoRows
+	}
+	m.ttl = ttl
+	return "reset-token", userID, nil
+}
+
+func (m *mockStorage) CheckPasswordReset(_ context.Context, token string) error {
+	if token != "reset-token" {
+		return model.ErrInvalidResetToken
+	}
+	return nil
+}
+
+func (m *mockStorage) ResetPassword(_ context.Context, token, password string) (*model.User, error) {
+	if token != "reset-token" {
+		return nil, model.ErrInvalidResetToken
+	}
+	return &model.User{ID: "user-1"}, nil
+}
+
+type sentEmail struct {
+	recipient, subject, body string
+}
+
+type mockSender struct {
+	sent []sentEmail
+}
+
+func (m *mockSender) Send(_ context.Context, recipient, subject, body string) error {
+	m.sent = append(m.sent, sentEmail{recipient, subject, body})
+	return nil
+}
+
+func TestService_Request(t *testing.T) {
+	ctx := t.Context()
+	storage := &mockStorage{emails: map[string]string{"me@titpetric.com": "user-1"}}
+	sender := &mockSender{}
+
+	s := New(storage, Options{
+		EmailSender: sender,
+		URLFormat:   "https://example.com/reset-password?token=%s",
+	})
+
+	require.ErrorIs(t, s.Request(ctx, ""), model.ErrEmailMissing)
+
+	require.NoError(t, s.Request(ctx, "nobody@titpetric.com"))
+	require.Equal(t, 0, len(sender.sent))
+
+	require.NoError(t, s.Request(ctx, "me@titpetric.com"))
+	require.Equal(t, 1, len(sender.sent))
+	require.Equal(t, DefaultTTL, storage.ttl)
+
+	mail := sender.sent[0]
+	require.Equal(t, "me@titpetric.com", mail.recipient)
+	require.Equal(t, DefaultSubject, mail.subject)
+	require.True(t, strings.Contains(mail.body, "https://example.com/reset-password?token=reset-token"))
+	require.True(t, strings.Contains(mail.body, "within 1 hour"))
+}
+
+func TestService_body(t *testing.T) {
+	s := New(nil, Options{TTL: 30 * time.Minute})
+	body := s.body("reset-token")
+	require.True(t, strings.Contains(body, "/reset-password"))
+	require.True(t, strings.Contains(body, "\n\nreset-token\n"))
+	require.True(t, strings.Contains(body, "within 30 minutes"))
+}
+
+func TestFormatTTL(t *testing.T) {
+	require.Equal(t, "1 hour", formatTTL(time.Hour))
+	require.Equal(t, "24 hours", formatTTL(24*time.Hour))
+	require.Equal(t, "1 minute", formatTTL(10*time.Second))
+	require.Equal(t, "90 minutes", formatTTL(90*time.Minute))
+}
+
+func TestService_Reset(t *testing.T) {
+	s := New(&mockStorage{}, Options{EmailSender: &mockSender{}})
+
+	_, err := s.Reset(t.Context(), "bogus", "password")
+	require.ErrorIs(t, err, model.ErrInvalidResetToken)
+
+	user, err := s.Reset(t.Context(), "reset-token", "password")
+	require.NoError(t, err)
+	require.Equal(t, "user-1", user.ID)
+}
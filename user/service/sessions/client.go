@@ -0,0 +1,43 @@
+package sessions
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// NewSession returns a session for the user, recording the user agent
+// and IP of the request creating it.
+func NewSession(r *http.Request, userID string) *model.UserSession {
+	return &model.UserSession{
+		UserID:    userID,
+		UserAgent: r.UserAgent(),
+		IP:        ClientIP(r),
+	}
+}
+
+// NewToken returns a record of a token issued to the user, with the user
+// agent and IP of the request issuing it.
+func NewToken(r *http.Request, userID, jti string, expiresAt time.Time) *model.UserToken {
+	token := &model.UserToken{
+		Jti:       jti,
+		UserID:    userID,
+		UserAgent: r.UserAgent(),
+		IP:        ClientIP(r),
+	}
+	token.SetExpiresAt(expiresAt)
+	return token
+}
+
+// ClientIP returns the IP address of the client of the request. Proxy
+// headers are not read here; a real IP middleware in front of the
+// router rewrites the remote address.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
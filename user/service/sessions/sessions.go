@@ -0,0 +1,170 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// Kinds of listed entries.
+const (
+	// KindSession is a web session, identified by the session cookie.
+	KindSession = "session"
+
+	// KindToken is a JWT issued by the API, identified by its jti.
+	KindToken = "token"
+)
+
+// MaxNameLength is the longest name a session or token can be given.
+const MaxNameLength = 64
+
+// Entry is a session or token of the user, as listed to them.
+type Entry struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	Name       string     `json:"name"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  *time.Time `json:"created_at"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+
+	// Current is set for the session or token of the listing request.
+	Current bool `json:"current"`
+}
+
+// Current identifies the session or token a request was made with. List
+// marks it and RevokeAll keeps it.
+type Current struct {
+	SessionID string
+	JTI       string
+}
+
+// Service lists, names and revokes the sessions and tokens of users.
+type Service struct {
+	sessions model.SessionStorage
+	tokens   model.TokenStorage
+}
+
+// New creates a new sessions Service.
+func New(sessions model.SessionStorage, tokens model.TokenStorage) *Service {
+	return &Service{
+		sessions: sessions,
+		tokens:   tokens,
+	}
+}
+
+// List returns the active sessions and tokens of the user, most recently
+// used first.
+func (s *Service) List(ctx context.Context, userID string, current Current) ([]Entry, error) {
+	sessions, err := s.sessions.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.tokens.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Entry, 0, len(sessions)+len(tokens))
+	for _, session := range sessions {
+		result = append(result, Entry{
+			ID:         session.ID,
+			Kind:       KindSession,
+			Name:       session.Name,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == current.SessionID,
+		})
+	}
+	for _, token := range tokens {
+		result = append(result, Entry{
+			ID:         token.Jti,
+			Kind:       KindToken,
+			Name:       token.Name,
+			UserAgent:  token.UserAgent,
+			IP:         token.IP,
+			CreatedAt:  token.CreatedAt,
+			LastSeenAt: token.LastSeenAt,
+			ExpiresAt:  token.ExpiresAt,
+			Current:    token.Jti == current.JTI,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return lastSeen(result[i]).After(lastSeen(result[j]))
+	})
+	return result, nil
+}
+
+// lastSeen returns when an entry was last used, or created.
+func lastSeen(e Entry) time.Time {
+	switch {
+	case e.LastSeenAt != nil:
+		return *e.LastSeenAt
+	case e.CreatedAt != nil:
+		return *e.CreatedAt
+	}
+	return time.Time{}
+}
+
+// Rename names a session or token of the user, so they can tell them
+// apart. An empty name clears it.
+func (s *Service) Rename(ctx context.Context, userID, id, name string) error {
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return model.ErrSessionNameTooLong
+	}
+
+	return s.either(
+		func() error { return s.sessions.Rename(ctx, userID, id, name) },
+		func() error { return s.tokens.Rename(ctx, userID, id, name) },
+	)
+}
+
+// Revoke ends a session or revokes a token of the user.
+func (s *Service) Revoke(ctx context.Context, userID, id string) error {
+	return s.either(
+		func() error { return s.sessions.Revoke(ctx, userID, id) },
+		func() error { return s.tokens.Revoke(ctx, userID, id) },
+	)
+}
+
+// RevokeAll ends all sessions and revokes all tokens of the user, except
+// the current one, and returns how many there were.
+func (s *Service) RevokeAll(ctx context.Context, userID string, current Current) (int64, error) {
+	sessions, err := s.sessions.RevokeAll(ctx, userID, current.SessionID)
+	if err != nil {
+		return 0, err
+	}
+	tokens, err := s.tokens.RevokeAll(ctx, userID, current.JTI)
+	if err != nil {
+		return sessions, err
+	}
+	return sessions + tokens, nil
+}
+
+// either applies a change to a session, or to a token if there's no
+// session with the ID. Session IDs and jtis are both ULIDs, so an ID
+// names one or the other. Errors with model.ErrSessionNotFound if
+// neither exists.
+func (s *Service) either(session, token func() error) error {
+	err := session()
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	err = token()
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ErrSessionNotFound
+	}
+	return err
+}
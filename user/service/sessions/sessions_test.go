@@ -0,0 +1,211 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+type mockSessions struct {
+	sessions map[string]*model.UserSession
+}
+
+func (m *mockSessions) Create(_ context.Context, session *model.UserSession) (*model.UserSession, error) {
+	m.sessions[session.ID] = session
+	return session, nil
+}
+
+func (m *mockSessions) Get(_ context.Context, id string) (*model.UserSession, error) {
+	if session, ok := m.sessions[id]; ok {
+		return session, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *mockSessions) Touch(context.Context, *model.UserSession) error {
+	return nil
+}
+
+func (m *mockSessions) Delete(_ context.Context, id string) error {
+	delete(m.sessions, id)
+	return nil
+}
+
+func (m *mockSessions) List(_ context.Context, userID string) ([]model.UserSession, error) {
+	var result []model.UserSession
+	for _, session := range m.sessions {
+		if session.UserID == userID {
+			result = append(result, *session)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockSessions) Rename(_ context.Context, userID, id, name string) error {
+	session, ok := m.sessions[id]
+	if !ok || session.UserID != userID {
+		return sql.ErrNoRows
+	}
+	session.Name = name
+	return nil
+}
+
+func (m *mockSessions) Revoke(_ context.Context, userID, id string) error {
+	session, ok := m.sessions[id]
+	if !ok || session.UserID != userID {
+		return sql.ErrNoRows
+	}
+	delete(m.sessions, id)
+	return nil
+}
+
+func (m *mockSessions) RevokeAll(_ context.Context, userID, except string) (int64, error) {
+	var n int64
+	for id, session := range m.sessions {
+		if session.UserID == userID && id != except {
+			delete(m.sessions, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+type mockTokens struct {
+	tokens map[string]*model.UserToken
+}
+
+func (m *mockTokens) Create(_ context.Context, token *model.UserToken) (*model.UserToken, error) {
+	m.tokens[token.Jti] = token
+	return token, nil
+}
+
+func (m *mockTokens) Touch(context.Context, string) error {
+	return nil
+}
+
+func (m *mockTokens) List(_ context.Context, userID string) ([]model.UserToken, error) {
+	var result []model.UserToken
+	for _, token := range m.tokens {
+		if token.UserID == userID {
+			result = append(result, *token)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockTokens) Rename(_ context.Context, userID, jti, name string) error {
+	token, ok := m.tokens[jti]
+	if !ok || token.UserID != userID {
+		return sql.ErrNoRows
+	}
+	token.Name = name
+	return nil
+}
+
+func (m *mockTokens) Revoke(_ context.Context, userID, jti string) error {
+	token, ok := m.tokens[jti]
+	if !ok || token.UserID != userID {
+		return sql.ErrNoRows
+	}
+	delete(m.tokens, jti)
+	return nil
+}
+
+func (m *mockTokens) RevokeAll(_ context.Context, userID, except string) (int64, error) {
+	var n int64
+	for jti, token := range m.tokens {
+		if token.UserID == userID && jti != except {
+			delete(m.tokens, jti)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func minutesAgo(minutes int) *time.Time {
+	t := time.Now().Add(-time.Duration(minutes) * time.Minute)
+	return &t
+}
+
+func newService() (*Service, *mockSessions, *mockTokens) {
+	sessions := &mockSessions{sessions: map[string]*model.UserSession{
+		"session-1": {ID: "session-1", UserID: "user-1", LastSeenAt: minutesAgo(10)},
+		"session-2": {ID: "session-2", UserID: "user-1", LastSeenAt: minutesAgo(1)},
+		"session-3": {ID: "session-3", UserID: "user-2", LastSeenAt: minutesAgo(1)},
+	}}
+	tokens := &mockTokens{tokens: map[string]*model.UserToken{
+		"jti-1": {Jti: "jti-1", UserID: "user-1", LastSeenAt: minutesAgo(5)},
+		"jti-2": {Jti: "jti-2", UserID: "user-1", CreatedAt: minutesAgo(20)},
+	}}
+	return New(sessions, tokens), sessions, tokens
+}
+
+func TestService_List(t *testing.T) {
+	s, _, _ := newService()
+
+	list, err := s.List(t.Context(), "user-1", Current{SessionID: "session-1"})
+	require.NoError(t, err)
+	require.Len(t, list, 4)
+
+	var ids []string
+	for _, e := range list {
+		ids = append(ids, e.ID)
+	}
+	require.Equal(t, []string{"session-2", "jti-1", "session-1", "jti-2"}, ids)
+	require.Equal(t, KindToken, list[1].Kind)
+	require.True(t, list[2].Current)
+	require.False(t, list[0].Current)
+}
+
+func TestService_Rename(t *testing.T) {
+	ctx := t.Context()
+	s, sessions, tokens := newService()
+
+	require.NoError(t, s.Rename(ctx, "user-1", "session-1", "  laptop "))
+	require.Equal(t, "laptop", sessions.sessions["session-1"].Name)
+
+	require.NoError(t, s.Rename(ctx, "user-1", "jti-1", "cli"))
+	require.Equal(t, "cli", tokens.tokens["jti-1"].Name)
+
+	require.ErrorIs(t, s.Rename(ctx, "user-1", "session-3", "stolen"), model.ErrSessionNotFound)
+	require.ErrorIs(t, s.Rename(ctx, "user-1", "session-1", strings.Repeat("x", MaxNameLength+1)), model.ErrSessionNameTooLong)
+}
+
+func TestService_Revoke(t *testing.T) {
+	ctx := t.Context()
+	s, sessions, tokens := newService()
+
+	require.NoError(t, s.Revoke(ctx, "user-1", "jti-2"))
+	require.Len(t, tokens.tokens, 1)
+	require.ErrorIs(t, s.Revoke(ctx, "user-1", "jti-2"), model.ErrSessionNotFound)
+	require.ErrorIs(t, s.Revoke(ctx, "user-1", "session-3"), model.ErrSessionNotFound)
+
+	n, err := s.RevokeAll(ctx, "user-1", Current{SessionID: "session-1"})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+	require.Len(t, sessions.sessions, 2)
+	require.Len(t, tokens.tokens, 0)
+}
+
+func TestNewToken(t *testing.T) {
+	r := httptest.NewRequest("POST", "/api/user/token/create", nil)
+	r.RemoteAddr = "192.0.2.1:1234"
+	r.Header.Set("User-Agent", "pulse/1.0")
+
+	expiresAt := time.Now().Add(time.Hour)
+	token := NewToken(r, "user-1", "jti-1", expiresAt)
+	require.Equal(t, "192.0.2.1", token.IP)
+	require.Equal(t, "pulse/1.0", token.UserAgent)
+	require.Equal(t, expiresAt, *token.ExpiresAt)
+
+	session := NewSession(r, "user-1")
+	require.Equal(t, "192.0.2.1", session.IP)
+	require.Equal(t, "user-1", session.UserID)
+}
@@ -8,9 +8,14 @@ import (
 	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/titpetric/platform"
 
+	"github.com/titpetric/platform-app/user/opa"
 	"github.com/titpetric/platform-app/user/schema"
 	"github.com/titpetric/platform-app/user/service/api"
+	"github.com/titpetric/platform-app/user/service/mfa"
 	"github.com/titpetric/platform-app/user/service/passkey"
+	"github.com/titpetric/platform-app/user/service/policy"
+	"github.com/titpetric/platform-app/user/service/recovery"
+	"github.com/titpetric/platform-app/user/service/sessions"
 	"github.com/titpetric/platform-app/user/service/web"
 	"github.com/titpetric/platform-app/user/storage"
 )
@@ -55,8 +60,10 @@ func (h *UserModule) Start(ctx context.Context) error {
 
 	userStorage := storage.NewUserStorage(db)
 	sessionStorage := storage.NewSessionStorage(db)
+	sessionStorage.SetTTL(h.opts.SessionTTL)
 	passkeyStorage := storage.NewPasskeyStorage(db)
 	revokedStorage := storage.NewRevokedTokenStorage(db)
+	tokenStorage := storage.NewTokenStorage(db)
 
 	rpID := os.Getenv("WEBAUTHN_RP_ID")
 	if rpID == "" {
@@ -78,6 +85,44 @@ func (h *UserModule) Start(ctx context.Context) error {
 
 	passkeySvc := passkey.New(wa, passkeyStorage, userStorage)
 
+	flows, err := opa.Flows()
+	if err != nil {
+		return fmt.Errorf("user module: flows: %w", err)
+	}
+	sessionsSvc := sessions.New(sessionStorage, tokenStorage)
+	webOpts := []web.Option{
+		web.WithFlows(flows),
+		web.WithFlowStorage(storage.NewFlowStorage(db)),
+		web.WithSessions(sessionsSvc),
+	}
+
+	// MFA is enabled with the mfa feature of opa/flows.yml.
+	var mfaSvc *mfa.Service
+	if flows.FeatureEnabled("mfa") {
+		issuer := h.opts.MFAIssuer
+		if issuer == "" {
+			issuer = DefaultMFAIssuer
+		}
+		mfaSvc = mfa.New(storage.NewMFAStorage(db), issuer)
+		webOpts = append(webOpts, web.WithMFA(mfaSvc))
+	}
+
+	// Password expiry and email re-verification follow the policies
+	// flow of opa/flows.yml, and are only enforced when enabled. Email
+	// re-verification can't be completed without a sender, which would
+	// lock users out.
+	var policySvc *policy.Service
+	if h.opts.PoliciesEnabled {
+		if flows.FeatureInt("email_reverify_days") > 0 && h.opts.EmailSender == nil {
+			return fmt.Errorf("user module: PoliciesEnabled with email_reverify_days requires an EmailSender (see user.WithEmailSender)")
+		}
+		policySvc = policy.New(userStorage, flows, policy.Options{
+			EmailSender: h.opts.EmailSender,
+			URLFormat:   h.opts.EmailVerifyURLFormat,
+		})
+		webOpts = append(webOpts, web.WithPolicy(policySvc))
+	}
+
 	// Loud failure when activation is enabled but no sender was wired.
 	// Activation otherwise silently degrades to "user is created
 	// pending and can never receive their token" — much harder to
@@ -86,14 +131,32 @@ func (h *UserModule) Start(ctx context.Context) error {
 		return fmt.Errorf("user module: EmailActivationEnabled requires an EmailSender (see user.WithEmailSender)")
 	}
 
-	h.web = web.NewHandlers(userStorage, sessionStorage, FS(ctx))
+	// The forgotten password flow mails reset tokens, so it's only
+	// enabled when a sender was wired.
+	var recoverySvc *recovery.Service
+	if h.opts.EmailSender != nil {
+		recoverySvc = recovery.New(userStorage, recovery.Options{
+			EmailSender: h.opts.EmailSender,
+			URLFormat:   h.opts.PasswordResetURLFormat,
+			Subject:     h.opts.PasswordResetSubject,
+			TTL:         h.opts.PasswordResetTTL,
+		})
+		webOpts = append(webOpts, web.WithRecovery(recoverySvc))
+	}
+
+	h.web = web.NewHandlers(userStorage, sessionStorage, FS(ctx), webOpts...)
 	h.api = api.NewHandlers(api.Options{
 		SigningKey:             h.opts.SigningKey,
 		TokenTTL:               h.opts.TokenTTL,
 		UserStorage:            userStorage,
 		SessionStorage:         sessionStorage,
 		RevokedStorage:         revokedStorage,
+		TokenStorage:           tokenStorage,
 		PasskeyService:         passkeySvc,
+		RecoveryService:        recoverySvc,
+		MFAService:             mfaSvc,
+		PolicyService:          policySvc,
+		SessionsService:        sessionsSvc,
 		EmailActivationEnabled: h.opts.EmailActivationEnabled,
 		EmailSender:            h.opts.EmailSender,
 		ActivationURLFormat:    h.opts.ActivationURLFormat,
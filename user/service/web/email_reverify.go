@@ -0,0 +1,69 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/titpetric/oida"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/policy"
+)
+
+// ReverifyEmail handles the email re-verification form. With a token it
+// verifies the email and continues with the next pending policy or the
+// end of the flow, otherwise it mails a verification link to the logged
+// in user.
+func (h *Handlers) ReverifyEmail(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.reverifyEmail(w, r))
+}
+
+func (h *Handlers) reverifyEmail(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.ReverifyEmail")
+	defer span.End()
+
+	if r.FormValue("token") != "" {
+		return h.verifyEmail(w, r)
+	}
+
+	user, err := h.sessionUser(r)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+
+	if err := h.policy.RequestVerification(r.Context(), user.ID); err != nil {
+		h.Error(r, "Can't send verification email", err)
+		return h.fail(w, r, policy.Flow, policy.EmailReverify, h.EmailReverifyView)
+	}
+
+	h.Message(r, "We've sent you a link to verify your email")
+	return h.emailReverifyForm(w, r)
+}
+
+// verifyEmail exchanges the token of the request. The emailed link may
+// be opened without a session, in which case the login page is shown.
+func (h *Handlers) verifyEmail(w http.ResponseWriter, r *http.Request) error {
+	user, err := h.policy.VerifyEmail(r.Context(), r.FormValue("token"))
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidVerifyToken) {
+			h.Error(r, "The verification link is invalid or has expired", err)
+		} else {
+			h.Error(r, "Can't verify email", err)
+		}
+		if _, err := h.sessionUser(r); err != nil {
+			h.LoginView(w, r)
+			return nil
+		}
+		return h.fail(w, r, policy.Flow, policy.EmailReverify, func(w http.ResponseWriter, r *http.Request) {
+			h.errorHandler(w, r, h.emailReverifyForm(w, r))
+		})
+	}
+
+	if step, ok := h.policyStep(r, user.ID); ok {
+		h.goTo(w, r, policy.Flow, step)
+		return nil
+	}
+	h.advance(w, r, policy.Flow, policy.EmailReverify)
+	return nil
+}
@@ -0,0 +1,47 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/titpetric/oida"
+)
+
+// EmailReverifyView renders the page asking the logged in user to verify
+// their email again. An emailed link carries the token in the query
+// string, which is verified right away.
+func (h *Handlers) EmailReverifyView(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.emailReverifyView(w, r))
+}
+
+func (h *Handlers) emailReverifyView(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.EmailReverifyView")
+	defer span.End()
+
+	if r.Method == http.MethodGet && r.URL.Query().Get("token") != "" {
+		return h.verifyEmail(w, r)
+	}
+	return h.emailReverifyForm(w, r)
+}
+
+// emailReverifyForm renders the page, ignoring a token in the query
+// string.
+func (h *Handlers) emailReverifyForm(w http.ResponseWriter, r *http.Request) error {
+	user, err := h.sessionUser(r)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+
+	email, err := h.userStorage.GetEmail(r.Context(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	return h.view.EmailReverify(EmailReverifyData{
+		SessionUser:  user,
+		Email:        email,
+		ErrorMessage: h.GetError(r),
+		Message:      h.GetMessage(r),
+		Links:        h.links(),
+	}).Render(r.Context(), w)
+}
@@ -16,6 +16,12 @@ type errorMessageKey struct{}
 
 var errorMessageContext = httpcontext.NewValue[string](errorMessageKey{})
 
+// messageKey is a request context scoped value, like errorMessageKey,
+// for a notice to display, e.g. after a password reset.
+type messageKey struct{}
+
+var messageContext = httpcontext.NewValue[string](messageKey{})
+
 // Error records an error message into the request context and captures telemetry.
 func (h *Handlers) Error(r *http.Request, message string, err error) {
 	errorMessageContext.Set(r, message)
@@ -33,6 +39,16 @@ func (h *Handlers) GetError(r *http.Request) string {
 	return errorMessageContext.Get(r)
 }
 
+// Message records a notice into the request context.
+func (h *Handlers) Message(r *http.Request, message string) {
+	messageContext.Set(r, message)
+}
+
+// GetMessage returns the notice stored in the request context.
+func (h *Handlers) GetMessage(r *http.Request) string {
+	return messageContext.Get(r)
+}
+
 func (h *Handlers) errorHandler(w http.ResponseWriter, r *http.Request, err error) {
 	if err != nil {
 		ctx := r.Context()
@@ -0,0 +1,248 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/titpetric/oida"
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/user/flow"
+	"github.com/titpetric/platform-app/user/service/policy"
+)
+
+// mfaChallengeView is the view of the second login step.
+const mfaChallengeView = "mfa_challenge.vuego"
+
+// successMessages are shown on the page ending a flow, by flow name.
+var successMessages = map[string]string{
+	"forgotten_password": "Your password has been reset, you can now login",
+}
+
+// stepRoute holds the handlers of a flow step view. Submit is nil for
+// steps that only show a page.
+type stepRoute struct {
+	View   http.HandlerFunc
+	Submit http.HandlerFunc
+}
+
+// stepRoutes returns the handlers of the flow step views, by view name.
+// Views of services that aren't configured are left out.
+func (h *Handlers) stepRoutes() map[string]stepRoute {
+	routes := map[string]stepRoute{
+		"login.vuego":    {h.LoginView, h.Login},
+		"register.vuego": {h.RegisterView, h.Register},
+	}
+	if h.recovery != nil {
+		routes["forgot_password.vuego"] = stepRoute{h.ForgotPasswordView, h.ForgotPassword}
+		routes["reset_sent.vuego"] = stepRoute{h.ResetSentView, nil}
+		routes["reset_password.vuego"] = stepRoute{h.ResetPasswordView, h.ResetPassword}
+	}
+	if h.mfa != nil {
+		routes[mfaChallengeView] = stepRoute{h.MFAView, h.VerifyMFA}
+	}
+	if h.policy != nil {
+		routes["password_expired.vuego"] = stepRoute{h.PasswordExpiredView, h.ChangeExpiredPassword}
+		routes["email_reverify.vuego"] = stepRoute{h.EmailReverifyView, h.ReverifyEmail}
+	}
+	return routes
+}
+
+// mountFlows registers the routes of the enabled flow steps. Steps
+// without a view end their flow and are served by Success. A link shared
+// by several steps is registered once. Steps are only served when they
+// are reachable for the user, see guard.
+func (h *Handlers) mountFlows(r platform.Router) {
+	routes := h.stepRoutes()
+	mounted := make(map[string]bool)
+
+	for _, name := range h.flows.Flows() {
+		for _, step := range h.flows.Steps(name) {
+			if mounted[step.Link] || !h.flows.Enabled(step) {
+				continue
+			}
+
+			if step.View == "" {
+				r.Get(step.Link, h.guard(name, step, h.Success))
+				mounted[step.Link] = true
+				continue
+			}
+
+			route, ok := routes[step.View]
+			if !ok {
+				continue
+			}
+			r.Get(step.Link, h.guard(name, step, route.View))
+			if route.Submit != nil {
+				r.Post(step.Link, h.guard(name, step, route.Submit))
+			}
+			mounted[step.Link] = true
+		}
+	}
+}
+
+// serves reports whether a step can be shown, that is if it ends the
+// flow or there is a handler for its view.
+func (h *Handlers) serves(step flow.Step) bool {
+	if step.View == "" {
+		return true
+	}
+	_, ok := h.stepRoutes()[step.View]
+	return ok
+}
+
+// next returns the step following from after the given result. Steps
+// without a handler, or rejected by skip, are passed through by their
+// "ok" transition. Skip may be nil.
+func (h *Handlers) next(flowName, from, result string, skip func(flow.Step) bool) (flow.Step, bool) {
+	step, ok := h.flows.Next(flowName, from, result)
+	for ok && (!h.serves(step) || skip != nil && skip(step)) {
+		step, ok = h.flows.Next(flowName, step.Name, "ok")
+	}
+	return step, ok
+}
+
+// guard serves a step of a flow only if it is reachable for the user,
+// or the request carries a valid token for it. Otherwise it redirects to
+// the start of the flow.
+func (h *Handlers) guard(flowName string, step flow.Step, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.reachable(r, step.Link) || h.validToken(r, step) {
+			next(w, r)
+			return
+		}
+
+		start, ok := h.flows.Start(flowName)
+		if !ok || start.Link == step.Link {
+			start.Link = "/login"
+		}
+		http.Redirect(w, r, start.Link, http.StatusSeeOther)
+	}
+}
+
+// reachable reports whether the user may open link. The start of any
+// flow can be opened, other steps only if they are the current step of
+// the user, or follow from it by a transition.
+func (h *Handlers) reachable(r *http.Request, link string) bool {
+	for _, name := range h.flows.Flows() {
+		if start, ok := h.flows.Start(name); ok && start.Link == link {
+			return true
+		}
+	}
+
+	flowName, current := h.currentStep(r)
+	step, ok := h.flows.Step(flowName, current)
+	if !ok {
+		return false
+	}
+	if step.Link == link {
+		return true
+	}
+	for result := range step.Next {
+		if next, ok := h.next(flowName, current, result, nil); ok && next.Link == link {
+			return true
+		}
+	}
+	return false
+}
+
+// validToken reports whether the request carries a valid token for a
+// step opened from an emailed link, like the password reset. Such links
+// may be opened in another browser than the one that requested them.
+func (h *Handlers) validToken(r *http.Request, step flow.Step) bool {
+	token := r.FormValue("token")
+	if token == "" {
+		return false
+	}
+	switch {
+	case step.Name == "reset_password" && h.recovery != nil:
+		return h.recovery.Valid(r.Context(), token)
+	case step.Name == policy.EmailReverify && h.policy != nil:
+		return h.policy.ValidVerifyToken(r.Context(), token)
+	}
+	return false
+}
+
+// goTo tracks the step as the current step of the user and redirects to
+// its link.
+func (h *Handlers) goTo(w http.ResponseWriter, r *http.Request, flowName string, step flow.Step) {
+	if h.flowStorage != nil {
+		if err := flow.Track(w, r, h.flowStorage, flowName, step); err != nil {
+			oida.RecordError(r.Context(), err)
+		}
+	}
+	http.Redirect(w, r, step.Link, http.StatusSeeOther)
+}
+
+// advance continues the flow after from succeeded. Without a following
+// step it redirects to the login page.
+func (h *Handlers) advance(w http.ResponseWriter, r *http.Request, flowName, from string) {
+	step, ok := h.next(flowName, from, "ok", nil)
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	h.goTo(w, r, flowName, step)
+}
+
+// fail continues the flow after from failed. When the error transition
+// names a step of the flow it redirects there, otherwise the current
+// step is rendered again with view, showing the error.
+func (h *Handlers) fail(w http.ResponseWriter, r *http.Request, flowName, from string, view http.HandlerFunc) error {
+	if step, ok := h.next(flowName, from, "error", nil); ok {
+		h.goTo(w, r, flowName, step)
+		return nil
+	}
+	view(w, r)
+	return nil
+}
+
+// policyStep returns the first policy step pending for the user, if
+// any, to take after a login or after completing a policy step.
+func (h *Handlers) policyStep(r *http.Request, userID string) (flow.Step, bool) {
+	if h.policy == nil {
+		return flow.Step{}, false
+	}
+	step, ok, err := h.policy.Check(r.Context(), userID)
+	if err != nil {
+		oida.RecordError(r.Context(), err)
+		return flow.Step{}, false
+	}
+	return step, ok
+}
+
+// currentStep returns the tracked flow and step of the user, if any.
+func (h *Handlers) currentStep(r *http.Request) (string, string) {
+	token := flow.Token(r)
+	if token == "" || h.flowStorage == nil {
+		return "", ""
+	}
+	current, err := h.flowStorage.Get(r.Context(), token)
+	if err != nil {
+		return "", ""
+	}
+	return current.Flow, current.Step
+}
+
+// Success ends the tracked flow and renders the login page, or the
+// logged in page with a session.
+func (h *Handlers) Success(w http.ResponseWriter, r *http.Request) {
+	r, span := oida.StartRequest(r, "user.service.Success")
+	defer span.End()
+
+	flowName, _ := h.currentStep(r)
+	if message, ok := successMessages[flowName]; ok {
+		h.Message(r, message)
+	}
+
+	if token := flow.Token(r); token != "" && h.flowStorage != nil {
+		if err := h.flowStorage.Delete(r.Context(), token); err != nil {
+			oida.RecordError(r.Context(), err)
+		}
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:   flow.CookieName,
+		Path:   "/",
+		MaxAge: -1,
+	})
+	h.LoginView(w, r)
+}
@@ -0,0 +1,190 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/user/flow"
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/recovery"
+	"github.com/titpetric/platform-app/user/service/web"
+)
+
+type flowStorage map[string]*model.UserFlow
+
+func (s flowStorage) Get(_ context.Context, token string) (*model.UserFlow, error) {
+	current, ok := s[token]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return current, nil
+}
+
+func (s flowStorage) Set(_ context.Context, token, flow, step string, _ time.Duration) error {
+	s[token] = &model.UserFlow{Flow: flow, Step: step}
+	return nil
+}
+
+func (s flowStorage) Delete(_ context.Context, token string) error {
+	delete(s, token)
+	return nil
+}
+
+const testFlows = `
+features:
+  survey: false
+
+flows:
+  forgotten_password:
+    steps:
+      - name: forgot_password
+        view: forgot_password.vuego
+        link: /recover
+        next:
+          ok: survey
+          error: help
+      - name: survey
+        enabled_if: features.survey
+        view: survey.vuego
+        link: /survey
+        next:
+          ok: send_reset
+      - name: send_reset
+        view: reset_sent.vuego
+        link: /recover/sent
+        next:
+          ok: done
+      - name: help
+        view: reset_sent.vuego
+        link: /recover/help
+        next:
+          ok: done
+      - name: done
+        link: /done
+`
+
+func TestFlows(t *testing.T) {
+	cfg, err := flow.Parse([]byte(testFlows))
+	require.NoError(t, err)
+	engine, err := flow.New(cfg)
+	require.NoError(t, err)
+
+	steps := flowStorage{}
+	svc := web.NewHandlers(nil, nil, newViewFS(),
+		web.WithFlows(engine),
+		web.WithFlowStorage(steps),
+		web.WithRecovery(recovery.New(resetStorage{}, recovery.Options{EmailSender: &emailSender{}})),
+	)
+
+	t.Run("ok skips disabled steps", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.ForgotPassword(w, postForm("/recover", url.Values{"email": {"john@example.com"}}))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/recover/sent", w.Header().Get("Location"))
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, "flow_token", cookies[0].Name)
+
+		current, ok := steps[cookies[0].Value]
+		require.True(t, ok)
+		assert.Equal(t, "forgotten_password", current.Flow)
+		assert.Equal(t, "send_reset", current.Step)
+	})
+
+	t.Run("error redirects to the error step", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.ForgotPassword(w, postForm("/recover", url.Values{}))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/recover/help", w.Header().Get("Location"))
+	})
+
+	t.Run("success ends the flow", func(t *testing.T) {
+		steps["done-token"] = &model.UserFlow{Flow: "forgotten_password", Step: "done"}
+
+		req := httptest.NewRequest(http.MethodGet, "/done", nil)
+		req.AddCookie(&http.Cookie{Name: "flow_token", Value: "done-token"})
+		w := httptest.NewRecorder()
+		svc.Success(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.True(t, cookies[0].MaxAge < 0)
+		assert.NotContains(t, steps, "done-token")
+	})
+}
+
+func TestFlowsRejectUnreachableSteps(t *testing.T) {
+	r := chi.NewRouter()
+	web.NewHandlers(nil, nil, newViewFS(),
+		web.WithFlowStorage(flowStorage{}),
+		web.WithRecovery(recovery.New(resetStorage{}, recovery.Options{EmailSender: &emailSender{}})),
+	).Mount(r)
+
+	serve := func(req *http.Request, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("reset password without send reset", func(t *testing.T) {
+		for _, method := range []string{http.MethodGet, http.MethodPost} {
+			for _, target := range []string{"/reset-password", "/reset-password?token=bogus"} {
+				w := serve(httptest.NewRequest(method, target, nil))
+
+				assert.Equal(t, http.StatusSeeOther, w.Code)
+				assert.Equal(t, "/forgot-password", w.Header().Get("Location"))
+			}
+		}
+	})
+
+	t.Run("reset link in another browser", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodGet, "/reset-password?token=reset-token", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = serve(postForm("/reset-password", url.Values{
+			"token":            {"reset-token"},
+			"password":         {"secret123"},
+			"password_confirm": {"secret123"},
+		}))
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/login/success", w.Header().Get("Location"))
+	})
+
+	t.Run("unknown flow token", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodGet, "/reset-password", nil), &http.Cookie{Name: "flow_token", Value: "bogus"})
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/forgot-password", w.Header().Get("Location"))
+	})
+
+	t.Run("reset password after send reset", func(t *testing.T) {
+		w := serve(postForm("/forgot-password", url.Values{"email": {"john@example.com"}}))
+		require.Equal(t, http.StatusSeeOther, w.Code)
+		require.Equal(t, "/forgot-password/sent", w.Header().Get("Location"))
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+
+		w = serve(httptest.NewRequest(http.MethodGet, "/forgot-password/sent", nil), cookies...)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = serve(httptest.NewRequest(http.MethodGet, "/reset-password?token=reset-token", nil), cookies...)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
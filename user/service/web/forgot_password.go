@@ -0,0 +1,32 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/titpetric/oida"
+)
+
+// ForgotPassword mails a password reset link via HTML form submission.
+// Unknown emails get the same response as known ones.
+func (h *Handlers) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.forgotPassword(w, r))
+}
+
+func (h *Handlers) forgotPassword(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.ForgotPassword")
+	defer span.End()
+
+	email := r.FormValue("email")
+	if email == "" {
+		h.Error(r, "Email is required", nil)
+		return h.fail(w, r, "forgotten_password", "forgot_password", h.ForgotPasswordView)
+	}
+
+	if err := h.recovery.Request(r.Context(), email); err != nil {
+		h.Error(r, "Can't send password reset email", err)
+		return h.fail(w, r, "forgotten_password", "forgot_password", h.ForgotPasswordView)
+	}
+
+	h.advance(w, r, "forgotten_password", "forgot_password")
+	return nil
+}
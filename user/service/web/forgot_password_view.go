@@ -0,0 +1,37 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/titpetric/oida"
+)
+
+// ForgotPasswordView renders the page to request a password reset.
+func (h *Handlers) ForgotPasswordView(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.forgotPasswordView(w, r))
+}
+
+func (h *Handlers) forgotPasswordView(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.ForgotPasswordView")
+	defer span.End()
+
+	return h.view.ForgotPassword(ForgotPasswordData{
+		ErrorMessage: h.GetError(r),
+		Email:        r.FormValue("email"),
+		Links:        h.links(),
+	}).Render(r.Context(), w)
+}
+
+// ResetSentView renders the page confirming a password reset email was sent.
+func (h *Handlers) ResetSentView(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.resetSentView(w, r))
+}
+
+func (h *Handlers) resetSentView(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.ResetSentView")
+	defer span.End()
+
+	return h.view.ResetSent(ResetSentData{
+		Links: h.links(),
+	}).Render(r.Context(), w)
+}
@@ -17,6 +17,11 @@ var Funcs = vuego.FuncMap{
 		switch t := val.(type) {
 		case time.Time:
 			return t.Format(layoutStr)
+		case *time.Time:
+			if t == nil {
+				return ""
+			}
+			return t.Format(layoutStr)
 		case string:
 			// Try to parse as RFC3339 or Unix timestamp
 			if parsed, err := time.Parse(time.RFC3339, t); err == nil {
@@ -5,6 +5,13 @@ import (
 
 	"github.com/titpetric/platform"
 
+	"github.com/titpetric/platform-app/user/flow"
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/opa"
+	"github.com/titpetric/platform-app/user/service/mfa"
+	"github.com/titpetric/platform-app/user/service/policy"
+	"github.com/titpetric/platform-app/user/service/recovery"
+	"github.com/titpetric/platform-app/user/service/sessions"
 	"github.com/titpetric/platform-app/user/storage"
 )
 
@@ -12,27 +19,120 @@ import (
 type Handlers struct {
 	userStorage    *storage.UserStorage
 	sessionStorage *storage.SessionStorage
+	recovery       *recovery.Service
+	mfa            *mfa.Service
+	flows          *flow.Engine
+	flowStorage    model.FlowStorage
+	policy         *policy.Service
+	sessions       *sessions.Service
 
 	view *Renderer
 }
 
+// Option configures optional Handlers dependencies.
+type Option func(*Handlers)
+
+// WithRecovery enables the forgotten password flow.
+func WithRecovery(svc *recovery.Service) Option {
+	return func(h *Handlers) {
+		h.recovery = svc
+	}
+}
+
+// WithMFA enables TOTP multi-factor authentication as a second login step.
+func WithMFA(svc *mfa.Service) Option {
+	return func(h *Handlers) {
+		h.mfa = svc
+	}
+}
+
+// WithPolicy enables the password expiry and email re-verification
+// policy steps.
+func WithPolicy(svc *policy.Service) Option {
+	return func(h *Handlers) {
+		h.policy = svc
+	}
+}
+
+// WithSessions enables the pages listing, naming and revoking the
+// sessions and tokens of the logged in user.
+func WithSessions(svc *sessions.Service) Option {
+	return func(h *Handlers) {
+		h.sessions = svc
+	}
+}
+
+// WithFlows sets the flow engine driving the login, registration and
+// forgotten password steps. The default is the embedded opa/flows.yml.
+func WithFlows(engine *flow.Engine) Option {
+	return func(h *Handlers) {
+		h.flows = engine
+	}
+}
+
+// WithFlowStorage keeps the current flow step of users, so steps can
+// only be opened in the order of the flows. Without it, only the start
+// of each flow can be opened through the mounted routes.
+func WithFlowStorage(storage model.FlowStorage) Option {
+	return func(h *Handlers) {
+		h.flowStorage = storage
+	}
+}
+
 // NewHandlers takes in required dependencies to support the MVC framework.
 // Context should be passed from Start() to access platform options.
-func NewHandlers(u *storage.UserStorage, s *storage.SessionStorage, viewFS fs.FS) *Handlers {
+func NewHandlers(u *storage.UserStorage, s *storage.SessionStorage, viewFS fs.FS, opts ...Option) *Handlers {
 	svc := &Handlers{
 		userStorage:    u,
 		sessionStorage: s,
 		view:           NewRenderer(viewFS, nil),
 	}
+	for _, opt := range opts {
+		opt(svc)
+	}
+	if svc.flows == nil {
+		svc.flows = flow.Must(opa.Flows())
+	}
 	return svc
 }
 
-// Mount registers login, logout, and register routes.
+// Mount registers the flow step routes, the logout routes, and the MFA
+// and session settings routes if they are enabled.
 func (s *Handlers) Mount(r platform.Router) {
-	r.Get("/login", s.LoginView)
-	r.Post("/login", s.Login)
+	s.mountFlows(r)
+
 	r.Get("/logout", s.LogoutView)
 	r.Post("/logout", s.Logout)
-	r.Get("/register", s.RegisterView)
-	r.Post("/register", s.Register)
+
+	if s.mfa != nil {
+		r.Get("/mfa/setup", s.MFASetupView)
+		r.Post("/mfa/setup", s.ConfirmMFA)
+		r.Post("/mfa/disable", s.DisableMFA)
+	}
+
+	if s.sessions != nil {
+		r.Get("/sessions", s.SessionsView)
+		r.Post("/sessions/name", s.NameSession)
+		r.Post("/sessions/revoke", s.RevokeSession)
+	}
+}
+
+// links returns the links shown in views. Recover, MFA and Sessions are
+// only set when they are enabled.
+func (s *Handlers) links() Links {
+	links := Links{
+		Login:    "/login",
+		Logout:   "/logout",
+		Register: "/register",
+	}
+	if s.recovery != nil {
+		links.Recover = "/forgot-password"
+	}
+	if s.mfa != nil {
+		links.MFA = "/mfa/setup"
+	}
+	if s.sessions != nil {
+		links.Sessions = "/sessions"
+	}
+	return links
 }
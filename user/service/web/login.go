@@ -5,7 +5,9 @@ import (
 
 	"github.com/titpetric/oida"
 
+	"github.com/titpetric/platform-app/user/flow"
 	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/policy"
 )
 
 // Login handles user authentication via HTML form submission.
@@ -22,8 +24,7 @@ func (h *Handlers) login(w http.ResponseWriter, r *http.Request) error {
 
 	if email == "" || password == "" {
 		h.Error(r, "Email and Password are required", nil)
-		h.LoginView(w, r)
-		return nil
+		return h.fail(w, r, "login", "login", h.LoginView)
 	}
 
 	user, err := h.userStorage.Authenticate(r.Context(), model.UserAuth{
@@ -32,27 +33,51 @@ func (h *Handlers) login(w http.ResponseWriter, r *http.Request) error {
 	})
 	if err != nil || !user.Ok() {
 		h.Error(r, "Invalid credentials for login", err)
-		h.LoginView(w, r)
+		return h.fail(w, r, "login", "login", h.LoginView)
+	}
+
+	mfaEnabled := false
+	if h.mfa != nil {
+		mfaEnabled, err = h.mfa.Enabled(r.Context(), user.ID)
+		if err != nil {
+			h.Error(r, "Can't check multi-factor authentication", err)
+			h.LoginView(w, r)
+			return nil
+		}
+	}
+
+	// The MFA step is only for users with MFA enabled. It starts a
+	// pending login, any other step follows a new session.
+	step, ok := h.next("login", "login", "ok", func(step flow.Step) bool {
+		return step.View == mfaChallengeView && !mfaEnabled
+	})
+	if ok && step.View == mfaChallengeView {
+		token, err := h.mfa.Begin(r.Context(), user.ID)
+		if err != nil {
+			h.Error(r, "Can't start multi-factor authentication", err)
+			h.LoginView(w, r)
+			return nil
+		}
+		setMFACookie(w, token)
+		h.goTo(w, r, "login", step)
 		return nil
 	}
 
-	session, err := h.sessionStorage.Create(r.Context(), user.ID)
-	if err != nil {
+	if err := h.startSession(w, r, user.ID); err != nil {
 		h.Error(r, "Can't create session", err)
 		h.LoginView(w, r)
 		return nil
 	}
 
-	cookie := &http.Cookie{
-		Name:     "session_id",
-		Value:    session.ID,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   true,
-		Expires:  *session.ExpiresAt,
+	if step, ok := h.policyStep(r, user.ID); ok {
+		h.goTo(w, r, policy.Flow, step)
+		return nil
 	}
-	http.SetCookie(w, cookie)
 
-	http.Redirect(w, r, "/login", http.StatusSeeOther)
+	if !ok {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+	h.goTo(w, r, "login", step)
 	return nil
 }
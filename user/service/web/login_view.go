@@ -1,6 +1,7 @@
 package web
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/titpetric/oida"
@@ -18,33 +19,21 @@ func (h *Handlers) loginView(w http.ResponseWriter, r *http.Request) error {
 
 	ctx := r.Context()
 
-	cookie, err := r.Cookie("session_id")
-	if err == nil && cookie.Value != "" {
-		if session, err := h.sessionStorage.Get(ctx, cookie.Value); err == nil {
-			if user, err := h.userStorage.Get(ctx, session.UserID); err == nil {
-				return h.view.Logout(LogoutData{
-					SessionUser: user,
-					Links: Links{
-						Login:    "/login",
-						Logout:   "/logout",
-						Register: "/register",
-					},
-				}).Render(ctx, w)
-			} else {
-				oida.RecordError(ctx, err)
-			}
-		} else {
-			oida.RecordError(ctx, err)
-		}
+	user, err := h.sessionUser(r)
+	if err == nil {
+		return h.view.Logout(LogoutData{
+			SessionUser: user,
+			Links:       h.links(),
+		}).Render(ctx, w)
+	}
+	if !errors.Is(err, errNoSession) {
+		oida.RecordError(ctx, err)
 	}
 
 	return h.view.Login(LoginData{
 		ErrorMessage: h.GetError(r),
+		Message:      h.GetMessage(r),
 		Email:        r.FormValue("email"),
-		Links: Links{
-			Login:    "/login",
-			Logout:   "/logout",
-			Register: "/register",
-		},
+		Links:        h.links(),
 	}).Render(ctx, w)
 }
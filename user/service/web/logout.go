@@ -17,18 +17,9 @@ func (h *Handlers) logout(w http.ResponseWriter, r *http.Request) error {
 
 	ctx := r.Context()
 
-	cookie, err := r.Cookie("session_id")
-
-	if err == nil && cookie.Value != "" {
-		_ = h.sessionStorage.Delete(ctx, cookie.Value)
-
-		http.SetCookie(w, &http.Cookie{
-			Name:     "session_id",
-			Value:    "",
-			Path:     "/",
-			HttpOnly: true,
-			MaxAge:   -1,
-		})
+	if sessionID := sessionID(r); sessionID != "" {
+		_ = h.sessionStorage.Delete(ctx, sessionID)
+		clearSessionCookie(w)
 	}
 
 	http.Redirect(w, r, "/login", http.StatusSeeOther)
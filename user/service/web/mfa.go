@@ -0,0 +1,120 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/titpetric/oida"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/policy"
+)
+
+// VerifyMFA completes a login pending on MFA via HTML form submission,
+// with a code from an authenticator app or a recovery code.
+func (h *Handlers) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.verifyMFA(w, r))
+}
+
+func (h *Handlers) verifyMFA(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.VerifyMFA")
+	defer span.End()
+
+	token := mfaToken(r)
+	code := r.FormValue("code")
+	if code == "" {
+		h.Error(r, "Code is required", nil)
+		return h.fail(w, r, "login", "check_mfa", h.MFAView)
+	}
+
+	userID, err := h.mfa.Verify(r.Context(), token, code)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidMFACode) && h.mfa.Pending(r.Context(), token):
+			h.Error(r, "Invalid authentication code", err)
+			return h.fail(w, r, "login", "check_mfa", h.MFAView)
+		case errors.Is(err, model.ErrInvalidMFACode), errors.Is(err, model.ErrMFAPendingExpired):
+			setMFACookie(w, "")
+			h.Error(r, "Your login expired, please login again", err)
+			h.LoginView(w, r)
+		default:
+			h.Error(r, "Can't verify authentication code", err)
+			h.MFAView(w, r)
+		}
+		return nil
+	}
+
+	setMFACookie(w, "")
+	if err := h.startSession(w, r, userID); err != nil {
+		h.Error(r, "Can't create session", err)
+		h.LoginView(w, r)
+		return nil
+	}
+
+	if step, ok := h.policyStep(r, userID); ok {
+		h.goTo(w, r, policy.Flow, step)
+		return nil
+	}
+	h.advance(w, r, "login", "check_mfa")
+	return nil
+}
+
+// ConfirmMFA enables MFA for the logged in user via HTML form submission,
+// with a first code from their authenticator app. The recovery codes are
+// shown once.
+func (h *Handlers) ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.confirmMFA(w, r))
+}
+
+func (h *Handlers) confirmMFA(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.ConfirmMFA")
+	defer span.End()
+
+	user, err := h.sessionUser(r)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+
+	codes, err := h.mfa.Confirm(r.Context(), user.ID, r.FormValue("code"))
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidMFACode) {
+			h.Error(r, "Invalid authentication code, check the time on your device", err)
+		} else {
+			h.Error(r, "Can't enable multi-factor authentication", err)
+		}
+		return h.mfaSetupView(w, r, nil)
+	}
+
+	h.Message(r, "Multi-factor authentication is enabled")
+	return h.mfaSetupView(w, r, codes)
+}
+
+// DisableMFA turns off MFA for the logged in user via HTML form
+// submission, given a current code or a recovery code.
+func (h *Handlers) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.disableMFA(w, r))
+}
+
+func (h *Handlers) disableMFA(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.DisableMFA")
+	defer span.End()
+
+	user, err := h.sessionUser(r)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+
+	if err := h.mfa.Disable(r.Context(), user.ID, r.FormValue("code")); err != nil {
+		if errors.Is(err, model.ErrInvalidMFACode) {
+			h.Error(r, "Invalid authentication code", err)
+		} else {
+			h.Error(r, "Can't disable multi-factor authentication", err)
+		}
+		return h.mfaSetupView(w, r, nil)
+	}
+
+	h.Message(r, "Multi-factor authentication is disabled")
+	return h.mfaSetupView(w, r, nil)
+}
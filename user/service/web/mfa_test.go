@@ -0,0 +1,137 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/mfa"
+	"github.com/titpetric/platform-app/user/service/web"
+)
+
+type mfaStorage struct {
+	pending map[string]*model.UserMFAPending
+}
+
+func (*mfaStorage) Get(_ context.Context, userID string) (*model.UserMFA, error) {
+	if userID != "user-1" {
+		return nil, sql.ErrNoRows
+	}
+	now := time.Now()
+	return &model.UserMFA{UserID: userID, Secret: mfa.NewSecret(), EnabledAt: &now}, nil
+}
+
+func (*mfaStorage) Enroll(context.Context, string, string) error { return nil }
+
+func (*mfaStorage) Enable(context.Context, string, int64, []string) error { return nil }
+
+func (*mfaStorage) Disable(context.Context, string) error { return nil }
+
+func (*mfaStorage) UseStep(context.Context, string, int64) (bool, error) { return true, nil }
+
+func (*mfaStorage) UseRecoveryCode(context.Context, string, string) (bool, error) { return false, nil }
+
+func (*mfaStorage) CountRecoveryCodes(context.Context, string) (int, error) { return 0, nil }
+
+func (m *mfaStorage) CreatePending(_ context.Context, token, userID string, ttl time.Duration) error {
+	if m.pending == nil {
+		m.pending = make(map[string]*model.UserMFAPending)
+	}
+	m.pending[token] = &model.UserMFAPending{UserID: userID}
+	m.pending[token].SetExpiresAt(time.Now().Add(ttl))
+	return nil
+}
+
+func (m *mfaStorage) GetPending(_ context.Context, token string) (*model.UserMFAPending, error) {
+	pending, ok := m.pending[token]
+	if !ok || time.Now().After(*pending.ExpiresAt) {
+		return nil, sql.ErrNoRows
+	}
+	return pending, nil
+}
+
+func (m *mfaStorage) AttemptPending(ctx context.Context, token string, maxAttempts int) (*model.UserMFAPending, error) {
+	pending, err := m.GetPending(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if pending.Attempts >= int64(maxAttempts) {
+		return nil, sql.ErrNoRows
+	}
+	pending.Attempts++
+	return pending, nil
+}
+
+func (m *mfaStorage) DeletePending(_ context.Context, token string) (bool, error) {
+	_, ok := m.pending[token]
+	delete(m.pending, token)
+	return ok, nil
+}
+
+func TestMFA(t *testing.T) {
+	mfaSvc := mfa.New(&mfaStorage{}, "Platform App")
+	svc := web.NewHandlers(nil, nil, newViewFS(), web.WithMFA(mfaSvc))
+
+	withToken := func(req *http.Request, token string) *http.Request {
+		req.AddCookie(&http.Cookie{Name: "mfa_pending", Value: token})
+		return req
+	}
+
+	begin := func(t *testing.T) string {
+		t.Helper()
+		token, err := mfaSvc.Begin(context.Background(), "user-1")
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("challenge without pending login redirects to login", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.MFAView(w, httptest.NewRequest(http.MethodGet, "/mfa", nil))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/login", w.Header().Get("Location"))
+	})
+
+	t.Run("challenge with pending login", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.MFAView(w, withToken(httptest.NewRequest(http.MethodGet, "/mfa", nil), begin(t)))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("wrong code keeps the pending login", func(t *testing.T) {
+		token := begin(t)
+		w := httptest.NewRecorder()
+		svc.VerifyMFA(w, withToken(postForm("/mfa", url.Values{"code": {"bogus"}}), token))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, mfaSvc.Pending(context.Background(), token))
+	})
+
+	t.Run("expired login clears the cookie", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.VerifyMFA(w, withToken(postForm("/mfa", url.Values{"code": {"123456"}}), "expired"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, "mfa_pending", cookies[0].Name)
+		assert.True(t, cookies[0].MaxAge < 0)
+	})
+
+	t.Run("setup without session redirects to login", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.MFASetupView(w, httptest.NewRequest(http.MethodGet, "/mfa/setup", nil))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/login", w.Header().Get("Location"))
+	})
+}
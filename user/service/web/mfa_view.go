@@ -0,0 +1,94 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/titpetric/oida"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// MFAView renders the second login step, asking for a code. Without a
+// pending login it redirects to the login page.
+func (h *Handlers) MFAView(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.mfaView(w, r))
+}
+
+func (h *Handlers) mfaView(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.MFAView")
+	defer span.End()
+
+	if !h.mfa.Pending(r.Context(), mfaToken(r)) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+
+	return h.view.MFAChallenge(MFAChallengeData{
+		ErrorMessage: h.GetError(r),
+		Links:        h.links(),
+	}).Render(r.Context(), w)
+}
+
+// MFASetupView renders the MFA settings of the logged in user: the secret
+// to add to an authenticator app, the recovery codes once MFA is enabled,
+// or the form to disable MFA.
+func (h *Handlers) MFASetupView(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.mfaSetupView(w, r, nil))
+}
+
+func (h *Handlers) mfaSetupView(w http.ResponseWriter, r *http.Request, recoveryCodes []string) error {
+	r, span := oida.StartRequest(r, "user.service.MFASetupView")
+	defer span.End()
+
+	ctx := r.Context()
+
+	user, err := h.sessionUser(r)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+
+	data := MFASetupData{
+		SessionUser:  user,
+		ErrorMessage: h.GetError(r),
+		Message:      h.GetMessage(r),
+		Links:        h.links(),
+	}
+
+	enabled, err := h.mfa.Enabled(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if enabled {
+		left, err := h.mfa.RecoveryCodesLeft(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		data.MFA = MFA{
+			Enabled:           true,
+			RecoveryCodes:     recoveryCodes,
+			RecoveryCodesLeft: left,
+		}
+		return h.view.MFASetup(data).Render(ctx, w)
+	}
+
+	email, err := h.userStorage.GetEmail(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	enrollment, err := h.mfa.Enrollment(ctx, user.ID, email)
+	if errors.Is(err, model.ErrMFAAlreadyEnabled) {
+		http.Redirect(w, r, "/mfa/setup", http.StatusSeeOther)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data.MFA = MFA{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	}
+	return h.view.MFASetup(data).Render(ctx, w)
+}
@@ -1,6 +1,9 @@
 package web
 
-import "github.com/titpetric/platform-app/user/model"
+import (
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/sessions"
+)
 
 // Web view model types used for rendering templates.
 type (
@@ -9,19 +12,44 @@ type (
 		Logout   string `json:"logout"`
 		Register string `json:"register"`
 		Recover  string `json:"recover"`
+		MFA      string `json:"mfa"`
+		Sessions string `json:"sessions"`
+	}
+
+	MFA struct {
+		Enabled           bool     `json:"enabled"`
+		Secret            string   `json:"secret"`
+		URI               string   `json:"uri"`
+		RecoveryCodes     []string `json:"recoveryCodes"`
+		RecoveryCodesLeft int      `json:"recoveryCodesLeft"`
 	}
 
 	Data struct {
-		SessionUser  *model.User `json:"sessionUser"`
-		User         string      `json:"user"`
-		Email        string      `json:"email"`
-		Username     string      `json:"username"`
-		ErrorMessage string      `json:"errorMessage"`
-		FullName     string      `json:"fullName"`
-		Links        Links       `json:"links"`
+		SessionUser  *model.User      `json:"sessionUser"`
+		User         string           `json:"user"`
+		Email        string           `json:"email"`
+		Username     string           `json:"username"`
+		ErrorMessage string           `json:"errorMessage"`
+		Message      string           `json:"message"`
+		Token        string           `json:"token"`
+		MFA          MFA              `json:"mfa"`
+		Sessions     []sessions.Entry `json:"sessions"`
+		FullName     string           `json:"fullName"`
+		Links        Links            `json:"links"`
 	}
 
 	LoginData    = Data
 	LogoutData   = Data
 	RegisterData = Data
+
+	ForgotPasswordData = Data
+	ResetSentData      = Data
+	ResetPasswordData  = Data
+	MFAChallengeData   = Data
+	MFASetupData       = Data
+
+	PasswordExpiredData = Data
+	EmailReverifyData   = Data
+
+	SessionsData = Data
 )
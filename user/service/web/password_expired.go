@@ -0,0 +1,60 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/titpetric/oida"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/policy"
+)
+
+// ChangeExpiredPassword sets a new password for the logged in user whose
+// password expired, via HTML form submission, and continues with the
+// next pending policy or the end of the flow.
+func (h *Handlers) ChangeExpiredPassword(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.changeExpiredPassword(w, r))
+}
+
+func (h *Handlers) changeExpiredPassword(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.ChangeExpiredPassword")
+	defer span.End()
+
+	user, err := h.sessionUser(r)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+
+	current := r.FormValue("current_password")
+	password := r.FormValue("password")
+
+	switch {
+	case password == "":
+		h.Error(r, "Password is required", nil)
+		return h.fail(w, r, policy.Flow, policy.PasswordExpired, h.PasswordExpiredView)
+	case password != r.FormValue("password_confirm"):
+		h.Error(r, "Passwords do not match", nil)
+		return h.fail(w, r, policy.Flow, policy.PasswordExpired, h.PasswordExpiredView)
+	case password == current:
+		h.Error(r, "Choose a password different from the current one", nil)
+		return h.fail(w, r, policy.Flow, policy.PasswordExpired, h.PasswordExpiredView)
+	}
+
+	if err := h.policy.ChangePassword(r.Context(), user.ID, current, password); err != nil {
+		if errors.Is(err, model.ErrPasswordIncorrect) {
+			h.Error(r, "Current password is incorrect", err)
+		} else {
+			h.Error(r, "Can't change password", err)
+		}
+		return h.fail(w, r, policy.Flow, policy.PasswordExpired, h.PasswordExpiredView)
+	}
+
+	if step, ok := h.policyStep(r, user.ID); ok {
+		h.goTo(w, r, policy.Flow, step)
+		return nil
+	}
+	h.advance(w, r, policy.Flow, policy.PasswordExpired)
+	return nil
+}
@@ -0,0 +1,31 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/titpetric/oida"
+)
+
+// PasswordExpiredView renders the page asking the logged in user for a
+// new password, once theirs expired. Without a session it redirects to
+// the login page.
+func (h *Handlers) PasswordExpiredView(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.passwordExpiredView(w, r))
+}
+
+func (h *Handlers) passwordExpiredView(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.PasswordExpiredView")
+	defer span.End()
+
+	user, err := h.sessionUser(r)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+
+	return h.view.PasswordExpired(PasswordExpiredData{
+		SessionUser:  user,
+		ErrorMessage: h.GetError(r),
+		Links:        h.links(),
+	}).Render(r.Context(), w)
+}
@@ -0,0 +1,128 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/recovery"
+	"github.com/titpetric/platform-app/user/service/web"
+)
+
+type resetStorage struct{}
+
+func (resetStorage) CreatePasswordReset(_ context.Context, email string, _ time.Duration) (string, string, error) {
+	if email != "john@example.com" {
+		return "", "", sql.ErrNoRows
+	}
+	return "reset-token", "user-1", nil
+}
+
+func (resetStorage) CheckPasswordReset(_ context.Context, token string) error {
+	if token != "reset-token" {
+		return model.ErrInvalidResetToken
+	}
+	return nil
+}
+
+func (resetStorage) ResetPassword(_ context.Context, token, _ string) (*model.User, error) {
+	if token != "reset-token" {
+		return nil, model.ErrInvalidResetToken
+	}
+	return &model.User{ID: "user-1"}, nil
+}
+
+type emailSender struct {
+	recipients []string
+}
+
+func (s *emailSender) Send(_ context.Context, recipient, _, _ string) error {
+	s.recipients = append(s.recipients, recipient)
+	return nil
+}
+
+func postForm(target string, form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestForgotPassword(t *testing.T) {
+	sender := &emailSender{}
+	svc := web.NewHandlers(nil, nil, newViewFS(), web.WithRecovery(recovery.New(resetStorage{}, recovery.Options{EmailSender: sender})))
+
+	for _, email := range []string{"john@example.com", "nobody@example.com"} {
+		w := httptest.NewRecorder()
+		svc.ForgotPassword(w, postForm("/forgot-password", url.Values{"email": {email}}))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/forgot-password/sent", w.Header().Get("Location"))
+	}
+	require.Equal(t, []string{"john@example.com"}, sender.recipients)
+}
+
+func TestResetPasswordRendersView(t *testing.T) {
+	steps := flowStorage{}
+	svc := web.NewHandlers(nil, nil, newViewFS(),
+		web.WithFlowStorage(steps),
+		web.WithRecovery(recovery.New(resetStorage{}, recovery.Options{EmailSender: &emailSender{}})),
+	)
+
+	t.Run("mismatched passwords", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.ResetPassword(w, postForm("/reset-password", url.Values{
+			"token":            {"reset-token"},
+			"password":         {"secret123"},
+			"password_confirm": {"secret321"},
+		}))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Passwords do not match")
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.ResetPassword(w, postForm("/reset-password", url.Values{
+			"token":            {"bogus"},
+			"password":         {"secret123"},
+			"password_confirm": {"secret123"},
+		}))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid or has expired")
+	})
+
+	t.Run("valid token continues to login", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.ResetPassword(w, postForm("/reset-password", url.Values{
+			"token":            {"reset-token"},
+			"password":         {"secret123"},
+			"password_confirm": {"secret123"},
+		}))
+
+		assert.Equal(t, http.StatusSeeOther, w.Code)
+		assert.Equal(t, "/login/success", w.Header().Get("Location"))
+
+		cookies := w.Result().Cookies()
+		require.Len(t, cookies, 1)
+		require.Contains(t, steps, cookies[0].Value)
+		assert.Equal(t, "success", steps[cookies[0].Value].Step)
+
+		req := httptest.NewRequest(http.MethodGet, "/login/success", nil)
+		req.AddCookie(cookies[0])
+		w = httptest.NewRecorder()
+		svc.Success(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Your password has been reset")
+	})
+}
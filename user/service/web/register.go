@@ -6,6 +6,7 @@ import (
 	"github.com/titpetric/oida"
 
 	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/sessions"
 )
 
 // Register handles creating a new user and starting a session via HTML form submission.
@@ -32,18 +33,16 @@ func (h *Handlers) register(w http.ResponseWriter, r *http.Request) error {
 		} else {
 			h.Error(r, "All fields are required", nil)
 		}
-		h.RegisterView(w, r)
-		return nil
+		return h.fail(w, r, "registration", "register_form", h.RegisterView)
 	}
 
 	createdUser, err := h.userStorage.Create(ctx, req)
 	if err != nil {
 		h.Error(r, "Failed to create user", err)
-		h.RegisterView(w, r)
-		return nil
+		return h.fail(w, r, "registration", "register_form", h.RegisterView)
 	}
 
-	session, err := h.sessionStorage.Create(ctx, createdUser.ID)
+	session, err := h.sessionStorage.Create(ctx, sessions.NewSession(r, createdUser.ID))
 	if err != nil {
 		h.Error(r, "Failed to create session", err)
 		h.RegisterView(w, r)
@@ -59,6 +58,6 @@ func (h *Handlers) register(w http.ResponseWriter, r *http.Request) error {
 	}
 	http.SetCookie(w, cookie)
 
-	http.Redirect(w, r, "/login", http.StatusSeeOther)
+	h.advance(w, r, "registration", "register_form")
 	return nil
 }
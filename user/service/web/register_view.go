@@ -20,10 +20,6 @@ func (h *Handlers) registerView(w http.ResponseWriter, r *http.Request) error {
 		FullName:     r.FormValue("full_name"),
 		Email:        r.FormValue("email"),
 		Username:     r.FormValue("username"),
-		Links: Links{
-			Login:    "/login",
-			Logout:   "/logout",
-			Register: "/register",
-		},
+		Links:        h.links(),
 	}).Render(r.Context(), w)
 }
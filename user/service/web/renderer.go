@@ -42,3 +42,35 @@ func (r *Renderer) Logout(data LogoutData) vuego.Template {
 func (r *Renderer) Register(data RegisterData) vuego.Template {
 	return r.Load("register.vuego", data)
 }
+
+func (r *Renderer) ForgotPassword(data ForgotPasswordData) vuego.Template {
+	return r.Load("forgot_password.vuego", data)
+}
+
+func (r *Renderer) ResetSent(data ResetSentData) vuego.Template {
+	return r.Load("reset_sent.vuego", data)
+}
+
+func (r *Renderer) ResetPassword(data ResetPasswordData) vuego.Template {
+	return r.Load("reset_password.vuego", data)
+}
+
+func (r *Renderer) MFAChallenge(data MFAChallengeData) vuego.Template {
+	return r.Load("mfa_challenge.vuego", data)
+}
+
+func (r *Renderer) MFASetup(data MFASetupData) vuego.Template {
+	return r.Load("mfa_setup.vuego", data)
+}
+
+func (r *Renderer) PasswordExpired(data PasswordExpiredData) vuego.Template {
+	return r.Load("password_expired.vuego", data)
+}
+
+func (r *Renderer) EmailReverify(data EmailReverifyData) vuego.Template {
+	return r.Load("email_reverify.vuego", data)
+}
+
+func (r *Renderer) Sessions(data SessionsData) vuego.Template {
+	return r.Load("sessions.vuego", data)
+}
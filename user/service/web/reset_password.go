@@ -0,0 +1,48 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/titpetric/oida"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// ResetPassword sets a new password with a reset token via HTML form
+// submission, and continues with the next step of the flow on success.
+func (h *Handlers) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.resetPassword(w, r))
+}
+
+func (h *Handlers) resetPassword(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.ResetPassword")
+	defer span.End()
+
+	token := r.FormValue("token")
+	password := r.FormValue("password")
+
+	switch {
+	case token == "":
+		h.Error(r, "The password reset link is invalid or has expired", nil)
+		return h.fail(w, r, "forgotten_password", "reset_password", h.ResetPasswordView)
+	case password == "":
+		h.Error(r, "Password is required", nil)
+		return h.fail(w, r, "forgotten_password", "reset_password", h.ResetPasswordView)
+	case password != r.FormValue("password_confirm"):
+		h.Error(r, "Passwords do not match", nil)
+		return h.fail(w, r, "forgotten_password", "reset_password", h.ResetPasswordView)
+	}
+
+	if _, err := h.recovery.Reset(r.Context(), token, password); err != nil {
+		if errors.Is(err, model.ErrInvalidResetToken) {
+			h.Error(r, "The password reset link is invalid or has expired", err)
+		} else {
+			h.Error(r, "Can't reset password", err)
+		}
+		return h.fail(w, r, "forgotten_password", "reset_password", h.ResetPasswordView)
+	}
+
+	h.advance(w, r, "forgotten_password", "reset_password")
+	return nil
+}
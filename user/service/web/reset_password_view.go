@@ -0,0 +1,24 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/titpetric/oida"
+)
+
+// ResetPasswordView renders the page to choose a new password. The reset
+// token is taken from the query string of the emailed link.
+func (h *Handlers) ResetPasswordView(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.resetPasswordView(w, r))
+}
+
+func (h *Handlers) resetPasswordView(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.ResetPasswordView")
+	defer span.End()
+
+	return h.view.ResetPassword(ResetPasswordData{
+		ErrorMessage: h.GetError(r),
+		Token:        r.FormValue("token"),
+		Links:        h.links(),
+	}).Render(r.Context(), w)
+}
@@ -0,0 +1,99 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/mfa"
+	"github.com/titpetric/platform-app/user/service/sessions"
+)
+
+// mfaCookieName holds the token of a login pending on MFA.
+const mfaCookieName = "mfa_pending"
+
+// errNoSession is returned by sessionUser without a session cookie.
+var errNoSession = errors.New("no session")
+
+// startSession creates a session for the user and sets the session cookie.
+func (h *Handlers) startSession(w http.ResponseWriter, r *http.Request, userID string) error {
+	session, err := h.sessionStorage.Create(r.Context(), sessions.NewSession(r, userID))
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     "session_id",
+		Value:    session.ID,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		Expires:  *session.ExpiresAt,
+	})
+	return nil
+}
+
+// sessionUser returns the user of the session cookie, and records the
+// use of the session.
+func (h *Handlers) sessionUser(r *http.Request) (*model.User, error) {
+	sessionID := sessionID(r)
+	if sessionID == "" {
+		return nil, errNoSession
+	}
+
+	ctx := r.Context()
+	session, err := h.sessionStorage.Get(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	_ = h.sessionStorage.Touch(ctx, session)
+	return h.userStorage.Get(ctx, session.UserID)
+}
+
+// sessionID returns the ID from the session cookie, if any.
+func sessionID(r *http.Request) string {
+	cookie, err := r.Cookie("session_id")
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// clearSessionCookie deletes the session cookie.
+func clearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "session_id",
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		MaxAge:   -1,
+	})
+}
+
+// setMFACookie sets the cookie of a login pending on MFA. An empty token
+// clears it.
+func setMFACookie(w http.ResponseWriter, token string) {
+	cookie := &http.Cookie{
+		Name:     mfaCookieName,
+		Value:    token,
+		Path:     "/mfa",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   int(mfa.PendingTTL / time.Second),
+	}
+	if token == "" {
+		cookie.MaxAge = -1
+	}
+	http.SetCookie(w, cookie)
+}
+
+// mfaToken returns the token of the login pending on MFA, if any.
+func mfaToken(r *http.Request) string {
+	cookie, err := r.Cookie(mfaCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
@@ -0,0 +1,90 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/titpetric/oida"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/service/sessions"
+)
+
+// NameSession names a session or token of the logged in user via HTML
+// form submission.
+func (h *Handlers) NameSession(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.nameSession(w, r))
+}
+
+func (h *Handlers) nameSession(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.NameSession")
+	defer span.End()
+
+	user, err := h.sessionUser(r)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+
+	err = h.sessions.Rename(r.Context(), user.ID, r.FormValue("id"), r.FormValue("name"))
+	switch {
+	case err == nil:
+		h.Message(r, "Session renamed")
+	case errors.Is(err, model.ErrSessionNameTooLong):
+		h.Error(r, fmt.Sprintf("Names can be up to %d characters long", sessions.MaxNameLength), err)
+	case errors.Is(err, model.ErrSessionNotFound):
+		h.Error(r, "Session not found", err)
+	default:
+		h.Error(r, "Can't rename session", err)
+	}
+	return h.sessionsView(w, r)
+}
+
+// RevokeSession ends a session or revokes a token of the logged in user
+// via HTML form submission. With the "all" field set, it revokes all of
+// them except the current session. Revoking the current session logs out.
+func (h *Handlers) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.revokeSession(w, r))
+}
+
+func (h *Handlers) revokeSession(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.RevokeSession")
+	defer span.End()
+
+	ctx := r.Context()
+
+	user, err := h.sessionUser(r)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+
+	current := sessionID(r)
+
+	if r.FormValue("all") != "" {
+		n, err := h.sessions.RevokeAll(ctx, user.ID, sessions.Current{SessionID: current})
+		if err != nil {
+			h.Error(r, "Can't revoke sessions", err)
+		} else {
+			h.Message(r, fmt.Sprintf("Revoked %d sessions", n))
+		}
+		return h.sessionsView(w, r)
+	}
+
+	id := r.FormValue("id")
+	err = h.sessions.Revoke(ctx, user.ID, id)
+	switch {
+	case err == nil && id == current:
+		clearSessionCookie(w)
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	case err == nil:
+		h.Message(r, "Session revoked")
+	case errors.Is(err, model.ErrSessionNotFound):
+		h.Error(r, "Session not found", err)
+	default:
+		h.Error(r, "Can't revoke session", err)
+	}
+	return h.sessionsView(w, r)
+}
@@ -0,0 +1,41 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/titpetric/oida"
+
+	"github.com/titpetric/platform-app/user/service/sessions"
+)
+
+// SessionsView lists the sessions and API tokens of the logged in user,
+// with forms to name and revoke them.
+func (h *Handlers) SessionsView(w http.ResponseWriter, r *http.Request) {
+	h.errorHandler(w, r, h.sessionsView(w, r))
+}
+
+func (h *Handlers) sessionsView(w http.ResponseWriter, r *http.Request) error {
+	r, span := oida.StartRequest(r, "user.service.SessionsView")
+	defer span.End()
+
+	ctx := r.Context()
+
+	user, err := h.sessionUser(r)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return nil
+	}
+
+	entries, err := h.sessions.List(ctx, user.ID, sessions.Current{SessionID: sessionID(r)})
+	if err != nil {
+		return err
+	}
+
+	return h.view.Sessions(SessionsData{
+		SessionUser:  user,
+		Sessions:     entries,
+		ErrorMessage: h.GetError(r),
+		Message:      h.GetMessage(r),
+		Links:        h.links(),
+	}).Render(ctx, w)
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/titpetric/oida"
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// FlowStorage keeps the current flow step of users in the database.
+type FlowStorage struct {
+	db *sqlx.DB
+}
+
+// NewFlowStorage creates a new FlowStorage.
+func NewFlowStorage(db *sqlx.DB) *FlowStorage {
+	return &FlowStorage{
+		db: db,
+	}
+}
+
+// Get returns the current step of a flow token. Errors with
+// sql.ErrNoRows if there is none, or it expired.
+func (s *FlowStorage) Get(ctx context.Context, token string) (*model.UserFlow, error) {
+	ctx, span := oida.StartAuto(ctx, s.Get)
+	defer span.End()
+
+	result := &model.UserFlow{}
+	if err := s.db.GetContext(ctx, result, `SELECT * FROM user_flow WHERE token_hash = ? AND expires_at > ?`, hashToken(token), time.Now()); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Set stores step of flow as the current step of a flow token, valid for
+// ttl. Expired flow steps are deleted.
+func (s *FlowStorage) Set(ctx context.Context, token, flow, step string, ttl time.Duration) error {
+	ctx, span := oida.StartAuto(ctx, s.Set)
+	defer span.End()
+
+	now := time.Now()
+	tokenHash := hashToken(token)
+	return platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_flow WHERE token_hash = ? OR expires_at < ?`, tokenHash, now); err != nil {
+			return fmt.Errorf("set flow step: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO user_flow (token_hash, flow, step, expires_at, updated_at) VALUES (?, ?, ?, ?, ?)`, tokenHash, flow, step, now.Add(ttl), now); err != nil {
+			return fmt.Errorf("set flow step: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete ends the flow of a flow token.
+func (s *FlowStorage) Delete(ctx context.Context, token string) error {
+	ctx, span := oida.StartAuto(ctx, s.Delete)
+	defer span.End()
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM user_flow WHERE token_hash = ?`, hashToken(token)); err != nil {
+		return fmt.Errorf("delete flow step: %w", err)
+	}
+	return nil
+}
+
+var _ model.FlowStorage = (*FlowStorage)(nil)
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/titpetric/oida"
+	"github.com/titpetric/platform/pkg/ulid"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// GroupStorage implements the model.GroupStorage interface using the database.
+type GroupStorage struct {
+	db *sqlx.DB
+}
+
+// NewGroupStorage returns a new GroupStorage backed by the given sqlx.DB.
+func NewGroupStorage(db *sqlx.DB) *GroupStorage {
+	return &GroupStorage{
+		db: db,
+	}
+}
+
+// Create inserts a new group with the given title.
+func (s *GroupStorage) Create(ctx context.Context, title string) (*model.UserGroup, error) {
+	ctx, span := oida.StartAuto(ctx, s.Create)
+	defer span.End()
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, errors.New("create group: title is required")
+	}
+
+	now := time.Now()
+	group := &model.UserGroup{
+		ID:    ulid.String(),
+		Title: title,
+	}
+	group.SetCreatedAt(now)
+	group.SetUpdatedAt(now)
+
+	query := `INSERT INTO user_group (id, title, created_at, updated_at) VALUES (?, ?, ?, ?)`
+	if _, err := s.db.ExecContext(ctx, query, group.ID, group.Title, group.CreatedAt, group.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("create group: %w", err)
+	}
+	return group, nil
+}
+
+// Get retrieves a group by ULID.
+func (s *GroupStorage) Get(ctx context.Context, id string) (*model.UserGroup, error) {
+	ctx, span := oida.StartAuto(ctx, s.Get)
+	defer span.End()
+
+	group := &model.UserGroup{}
+	query := `SELECT id, title, created_at, updated_at FROM user_group WHERE id=?`
+	if err := s.db.GetContext(ctx, group, query, id); err != nil {
+		return nil, fmt.Errorf("get group id=%s: %w", id, err)
+	}
+	return group, nil
+}
+
+// List returns all groups ordered by title.
+func (s *GroupStorage) List(ctx context.Context) ([]model.UserGroup, error) {
+	ctx, span := oida.StartAuto(ctx, s.List)
+	defer span.End()
+
+	var groups []model.UserGroup
+	query := `SELECT id, title, created_at, updated_at FROM user_group ORDER BY title`
+	if err := s.db.SelectContext(ctx, &groups, query); err != nil {
+		return nil, fmt.Errorf("list groups: %w", err)
+	}
+	return groups, nil
+}
+
+// AddMember adds a user to a group. Adding an existing member is a no-op.
+func (s *GroupStorage) AddMember(ctx context.Context, groupID, userID string) error {
+	ctx, span := oida.StartAuto(ctx, s.AddMember)
+	defer span.End()
+
+	query := `INSERT OR IGNORE INTO user_group_member (user_group_id, user_id, joined_at) VALUES (?, ?, ?)`
+	if _, err := s.db.ExecContext(ctx, query, groupID, userID, time.Now()); err != nil {
+		return fmt.Errorf("add group member: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember removes a user from a group.
+func (s *GroupStorage) RemoveMember(ctx context.Context, groupID, userID string) error {
+	ctx, span := oida.StartAuto(ctx, s.RemoveMember)
+	defer span.End()
+
+	query := `DELETE FROM user_group_member WHERE user_group_id=? AND user_id=?`
+	if _, err := s.db.ExecContext(ctx, query, groupID, userID); err != nil {
+		return fmt.Errorf("remove group member: %w", err)
+	}
+	return nil
+}
+
+// ListMembers returns the IDs of the users in a group.
+func (s *GroupStorage) ListMembers(ctx context.Context, groupID string) ([]string, error) {
+	ctx, span := oida.StartAuto(ctx, s.ListMembers)
+	defer span.End()
+
+	var userIDs []string
+	query := `SELECT user_id FROM user_group_member WHERE user_group_id=? ORDER BY joined_at`
+	if err := s.db.SelectContext(ctx, &userIDs, query, groupID); err != nil {
+		return nil, fmt.Errorf("list group members: %w", err)
+	}
+	return userIDs, nil
+}
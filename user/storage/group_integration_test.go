@@ -0,0 +1,53 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+
+	_ "github.com/titpetric/platform/pkg/drivers"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/schema"
+	"github.com/titpetric/platform-app/user/storage"
+)
+
+func TestGroupStorage_integration(t *testing.T) {
+	ctx := t.Context()
+
+	db := NewTestDB(t)
+	require.NoError(t, storage.Migrate(ctx, db, schema.Migrations()))
+
+	s := storage.NewGroupStorage(db)
+	users := storage.NewUserStorage(db)
+
+	_, err := s.Create(ctx, " ")
+	require.NotNil(t, err)
+
+	group, err := s.Create(ctx, "Platform team")
+	require.NoError(t, err)
+	require.NotEmpty(t, group.ID)
+
+	_, err = s.Get(ctx, "missing")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, s.AddMember(ctx, group.ID, "user-1"))
+	require.NoError(t, s.AddMember(ctx, group.ID, "user-1"))
+	require.NoError(t, s.AddMember(ctx, group.ID, "user-2"))
+
+	members, err := s.ListMembers(ctx, group.ID)
+	require.NoError(t, err)
+	require.True(t, slices.Equal([]string{"user-1", "user-2"}, members))
+
+	groups, err := users.GetGroups(ctx, "user-1")
+	require.NoError(t, err)
+	require.True(t, len(groups) == 1 && groups[0].ID == group.ID)
+
+	require.NoError(t, s.RemoveMember(ctx, group.ID, "user-1"))
+	members, err = s.ListMembers(ctx, group.ID)
+	require.NoError(t, err)
+	require.True(t, slices.Equal([]string{"user-2"}, members))
+}
@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/titpetric/oida"
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// MFAStorage implements TOTP secret and recovery code persistence using
+// the database.
+type MFAStorage struct {
+	db *sqlx.DB
+}
+
+// NewMFAStorage creates a new MFAStorage.
+func NewMFAStorage(db *sqlx.DB) *MFAStorage {
+	return &MFAStorage{
+		db: db,
+	}
+}
+
+// Get returns the MFA record of a user. Errors with sql.ErrNoRows if the
+// user never enrolled.
+func (s *MFAStorage) Get(ctx context.Context, userID string) (*model.UserMFA, error) {
+	ctx, span := oida.StartAuto(ctx, s.Get)
+	defer span.End()
+
+	result := &model.UserMFA{}
+	if err := s.db.GetContext(ctx, result, `SELECT * FROM user_mfa WHERE user_id=?`, userID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Enroll stores a new TOTP secret for the user, replacing an earlier
+// enrollment that was never enabled. Errors with ErrMFAAlreadyEnabled if
+// MFA is enabled.
+func (s *MFAStorage) Enroll(ctx context.Context, userID, secret string) error {
+	ctx, span := oida.StartAuto(ctx, s.Enroll)
+	defer span.End()
+
+	return platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		var enabled int
+		if err := tx.GetContext(ctx, &enabled, `SELECT COUNT(*) FROM user_mfa WHERE user_id = ? AND enabled_at IS NOT NULL`, userID); err != nil {
+			return fmt.Errorf("enroll mfa: %w", err)
+		}
+		if enabled > 0 {
+			return model.ErrMFAAlreadyEnabled
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = ?`, userID); err != nil {
+			return fmt.Errorf("enroll mfa: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO user_mfa (user_id, secret, last_step, created_at) VALUES (?, ?, 0, ?)`, userID, secret, time.Now()); err != nil {
+			return fmt.Errorf("enroll mfa: %w", err)
+		}
+		return nil
+	})
+}
+
+// Enable enables MFA for an enrolled user, recording step as the time
+// step of the verified code. The recovery codes replace any earlier ones,
+// only their hashes are stored. Errors with ErrMFANotEnrolled if there's
+// no pending enrollment.
+func (s *MFAStorage) Enable(ctx context.Context, userID string, step int64, recoveryCodes []string) error {
+	ctx, span := oida.StartAuto(ctx, s.Enable)
+	defer span.End()
+
+	now := time.Now()
+	return platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, `UPDATE user_mfa SET enabled_at = ?, last_step = ? WHERE user_id = ? AND enabled_at IS NULL`, now, step, userID)
+		if err != nil {
+			return fmt.Errorf("enable mfa: %w", err)
+		}
+		if n, err := result.RowsAffected(); err != nil || n != 1 {
+			return model.ErrMFANotEnrolled
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_mfa_recovery WHERE user_id = ?`, userID); err != nil {
+			return fmt.Errorf("enable mfa: %w", err)
+		}
+		for _, code := range recoveryCodes {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO user_mfa_recovery (code_hash, user_id, created_at) VALUES (?, ?, ?)`, hashToken(code), userID, now); err != nil {
+				return fmt.Errorf("enable mfa: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// Disable removes the TOTP secret and recovery codes of a user.
+func (s *MFAStorage) Disable(ctx context.Context, userID string) error {
+	ctx, span := oida.StartAuto(ctx, s.Disable)
+	defer span.End()
+
+	return platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_mfa_recovery WHERE user_id = ?`, userID); err != nil {
+			return fmt.Errorf("disable mfa: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = ?`, userID); err != nil {
+			return fmt.Errorf("disable mfa: %w", err)
+		}
+		return nil
+	})
+}
+
+// UseStep records step as the time step of an accepted code. It reports
+// false if a code of the same or a later step was accepted before, so
+// each code is only accepted once.
+func (s *MFAStorage) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	ctx, span := oida.StartAuto(ctx, s.UseStep)
+	defer span.End()
+
+	result, err := s.db.ExecContext(ctx, `UPDATE user_mfa SET last_step = ? WHERE user_id = ? AND enabled_at IS NOT NULL AND last_step < ?`, step, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("use mfa step: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("use mfa step: %w", err)
+	}
+	return n == 1, nil
+}
+
+// UseRecoveryCode marks a recovery code of the user as used. It reports
+// false if the code is unknown or was used before.
+func (s *MFAStorage) UseRecoveryCode(ctx context.Context, userID, code string) (bool, error) {
+	ctx, span := oida.StartAuto(ctx, s.UseRecoveryCode)
+	defer span.End()
+
+	result, err := s.db.ExecContext(ctx, `UPDATE user_mfa_recovery SET used_at = ? WHERE code_hash = ? AND user_id = ? AND used_at IS NULL`, time.Now(), hashToken(code), userID)
+	if err != nil {
+		return false, fmt.Errorf("use recovery code: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("use recovery code: %w", err)
+	}
+	return n == 1, nil
+}
+
+// CountRecoveryCodes returns the number of unused recovery codes of a user.
+func (s *MFAStorage) CountRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	ctx, span := oida.StartAuto(ctx, s.CountRecoveryCodes)
+	defer span.End()
+
+	var count int
+	if err := s.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM user_mfa_recovery WHERE user_id = ? AND used_at IS NULL`, userID); err != nil {
+		return 0, fmt.Errorf("count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+// CreatePending stores a login pending on the MFA step, valid for ttl.
+// Expired pending logins are deleted.
+func (s *MFAStorage) CreatePending(ctx context.Context, token, userID string, ttl time.Duration) error {
+	ctx, span := oida.StartAuto(ctx, s.CreatePending)
+	defer span.End()
+
+	now := time.Now()
+	return platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_mfa_pending WHERE expires_at < ?`, now); err != nil {
+			return fmt.Errorf("create mfa pending: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO user_mfa_pending (token_hash, user_id, attempts, expires_at, created_at) VALUES (?, ?, 0, ?, ?)`, hashToken(token), userID, now.Add(ttl), now); err != nil {
+			return fmt.Errorf("create mfa pending: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetPending returns a pending login. Errors with sql.ErrNoRows if there
+// is none, or it expired.
+func (s *MFAStorage) GetPending(ctx context.Context, token string) (*model.UserMFAPending, error) {
+	ctx, span := oida.StartAuto(ctx, s.GetPending)
+	defer span.End()
+
+	result := &model.UserMFAPending{}
+	if err := s.db.GetContext(ctx, result, `SELECT * FROM user_mfa_pending WHERE token_hash = ? AND expires_at > ?`, hashToken(token), time.Now()); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AttemptPending counts an attempt at the code of a pending login, and
+// returns the pending login with the attempt counted. The attempt is
+// counted before the code is checked, in a single statement, so parallel
+// requests can't try more than maxAttempts codes. Errors with
+// sql.ErrNoRows if there's no pending login, it expired, or it has no
+// attempts left.
+func (s *MFAStorage) AttemptPending(ctx context.Context, token string, maxAttempts int) (*model.UserMFAPending, error) {
+	ctx, span := oida.StartAuto(ctx, s.AttemptPending)
+	defer span.End()
+
+	tokenHash := hashToken(token)
+	result, err := s.db.ExecContext(ctx, `UPDATE user_mfa_pending SET attempts = attempts + 1 WHERE token_hash = ? AND attempts < ? AND expires_at > ?`, tokenHash, maxAttempts, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("attempt mfa pending: %w", err)
+	}
+	if err := requireRow(result); err != nil {
+		return nil, err
+	}
+
+	pending := &model.UserMFAPending{}
+	if err := s.db.GetContext(ctx, pending, `SELECT * FROM user_mfa_pending WHERE token_hash = ?`, tokenHash); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// DeletePending deletes a pending login. It reports false if there was
+// none, so a pending login is only completed once.
+func (s *MFAStorage) DeletePending(ctx context.Context, token string) (bool, error) {
+	ctx, span := oida.StartAuto(ctx, s.DeletePending)
+	defer span.End()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM user_mfa_pending WHERE token_hash = ?`, hashToken(token))
+	if err != nil {
+		return false, fmt.Errorf("delete mfa pending: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("delete mfa pending: %w", err)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,142 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/titpetric/platform/pkg/drivers"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/schema"
+	"github.com/titpetric/platform-app/user/storage"
+)
+
+func TestMFAStorage_integration(t *testing.T) {
+	ctx := t.Context()
+
+	db := NewTestDB(t)
+	require.NoError(t, storage.Migrate(ctx, db, schema.Migrations()))
+
+	s := storage.NewMFAStorage(db)
+	require.NotNil(t, s)
+
+	_, err := s.Get(ctx, "user-1")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.ErrorIs(t, s.Enable(ctx, "user-1", 1, nil), model.ErrMFANotEnrolled)
+
+	// Enrolling again replaces the secret until MFA is enabled.
+	require.NoError(t, s.Enroll(ctx, "user-1", "SECRETONE"))
+	require.NoError(t, s.Enroll(ctx, "user-1", "SECRETTWO"))
+
+	record, err := s.Get(ctx, "user-1")
+	require.NoError(t, err)
+	require.Equal(t, "SECRETTWO", record.Secret)
+	require.Nil(t, record.EnabledAt)
+
+	used, err := s.UseStep(ctx, "user-1", 10)
+	require.NoError(t, err)
+	require.False(t, used)
+
+	require.NoError(t, s.Enable(ctx, "user-1", 10, []string{"aaaaabbbbb", "cccccddddd"}))
+	require.ErrorIs(t, s.Enroll(ctx, "user-1", "SECRETTHREE"), model.ErrMFAAlreadyEnabled)
+
+	record, err = s.Get(ctx, "user-1")
+	require.NoError(t, err)
+	require.NotNil(t, record.EnabledAt)
+	require.Equal(t, int64(10), record.LastStep)
+
+	t.Run("steps are used once", func(t *testing.T) {
+		used, err := s.UseStep(ctx, "user-1", 10)
+		require.NoError(t, err)
+		require.False(t, used)
+
+		used, err = s.UseStep(ctx, "user-1", 11)
+		require.NoError(t, err)
+		require.True(t, used)
+	})
+
+	t.Run("recovery codes are used once", func(t *testing.T) {
+		used, err := s.UseRecoveryCode(ctx, "user-1", "aaaaabbbbb")
+		require.NoError(t, err)
+		require.True(t, used)
+
+		used, err = s.UseRecoveryCode(ctx, "user-1", "aaaaabbbbb")
+		require.NoError(t, err)
+		require.False(t, used)
+
+		used, err = s.UseRecoveryCode(ctx, "user-2", "cccccddddd")
+		require.NoError(t, err)
+		require.False(t, used)
+
+		count, err := s.CountRecoveryCodes(ctx, "user-1")
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		require.NoError(t, s.Disable(ctx, "user-1"))
+
+		_, err := s.Get(ctx, "user-1")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		count, err := s.CountRecoveryCodes(ctx, "user-1")
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+	})
+}
+
+func TestMFAStorage_pending_integration(t *testing.T) {
+	ctx := t.Context()
+
+	db := NewTestDB(t)
+	require.NoError(t, storage.Migrate(ctx, db, schema.Migrations()))
+
+	s := storage.NewMFAStorage(db)
+
+	_, err := s.GetPending(ctx, "token")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, s.CreatePending(ctx, "token", "user-1", time.Minute))
+
+	pending, err := s.GetPending(ctx, "token")
+	require.NoError(t, err)
+	require.Equal(t, "user-1", pending.UserID)
+	require.Equal(t, int64(0), pending.Attempts)
+
+	t.Run("attempts are limited", func(t *testing.T) {
+		for i := 1; i <= 2; i++ {
+			pending, err := s.AttemptPending(ctx, "token", 2)
+			require.NoError(t, err)
+			require.Equal(t, int64(i), pending.Attempts)
+		}
+
+		_, err := s.AttemptPending(ctx, "token", 2)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("delete once", func(t *testing.T) {
+		deleted, err := s.DeletePending(ctx, "token")
+		require.NoError(t, err)
+		require.True(t, deleted)
+
+		deleted, err = s.DeletePending(ctx, "token")
+		require.NoError(t, err)
+		require.False(t, deleted)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		require.NoError(t, s.CreatePending(ctx, "expired", "user-1", -time.Minute))
+
+		_, err := s.GetPending(ctx, "expired")
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		_, err = s.AttemptPending(ctx, "expired", 5)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/titpetric/oida"
+	"github.com/titpetric/platform"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// hashToken returns the stored form of a password reset token, an email
+// verification token or an MFA recovery code. A leaked database row
+// can't be used in their place.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreatePasswordReset issues a single-use password reset token for the
+// user with the given email, valid for ttl, and returns the token and
+// the user ID. Only a hash of the token is stored. Expired and used
+// tokens of the user are purged. Errors with sql.ErrNoRows if no such
+// user exists.
+func (s *UserStorage) CreatePasswordReset(ctx context.Context, email string, ttl time.Duration) (string, string, error) {
+	ctx, span := oida.StartAuto(ctx, s.CreatePasswordReset)
+	defer span.End()
+
+	var userID string
+	if err := s.db.GetContext(ctx, &userID, `SELECT user_id FROM user_auth WHERE email=? LIMIT 1`, email); err != nil {
+		return "", "", err
+	}
+
+	token := newActivationToken()
+	now := time.Now()
+	err := platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_password_reset WHERE user_id = ? AND (used_at IS NOT NULL OR expires_at < ?)`, userID, now); err != nil {
+			return fmt.Errorf("purge password resets: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO user_password_reset (token_hash, user_id, expires_at, created_at) VALUES (?, ?, ?, ?)`, hashToken(token), userID, now.Add(ttl), now); err != nil {
+			return fmt.Errorf("create password reset: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return token, userID, nil
+}
+
+// CheckPasswordReset returns model.ErrInvalidResetToken unless the
+// password reset token can be used. The token isn't used up.
+func (s *UserStorage) CheckPasswordReset(ctx context.Context, token string) error {
+	ctx, span := oida.StartAuto(ctx, s.CheckPasswordReset)
+	defer span.End()
+
+	_, err := s.passwordResetUser(ctx, token)
+	return err
+}
+
+// passwordResetUser returns the user a usable password reset token was
+// issued to.
+func (s *UserStorage) passwordResetUser(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", model.ErrInvalidResetToken
+	}
+
+	var row struct {
+		UserID    string     `db:"user_id"`
+		ExpiresAt *time.Time `db:"expires_at"`
+		UsedAt    *time.Time `db:"used_at"`
+	}
+	err := s.db.GetContext(ctx, &row, `SELECT user_id, expires_at, used_at FROM user_password_reset WHERE token_hash=?`, hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", model.ErrInvalidResetToken
+	}
+	if err != nil {
+		return "", fmt.Errorf("password reset lookup: %w", err)
+	}
+
+	if row.UsedAt != nil || row.ExpiresAt == nil || time.Now().After(*row.ExpiresAt) {
+		return "", model.ErrInvalidResetToken
+	}
+	return row.UserID, nil
+}
+
+// ResetPassword exchanges a password reset token for a new password.
+// The token is single-use, and any other outstanding tokens of the user
+// are used up with it. All sessions of the user are deleted and all
+// their JWTs are revoked, see RevokedTokenStorage.IsUserRevoked.
+func (s *UserStorage) ResetPassword(ctx context.Context, token, password string) (*model.User, error) {
+	ctx, span := oida.StartAuto(ctx, s.ResetPassword)
+	defer span.End()
+
+	if token == "" {
+		return nil, model.ErrInvalidResetToken
+	}
+	if password == "" {
+		return nil, model.ErrPasswordMissing
+	}
+
+	userID, err := s.passwordResetUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	_, span2 := oida.Start(ctx, "bcrypt.GenerateFromPassword")
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	span2.End()
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	err = platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		// Claiming the token first means a concurrent reset with the
+		// same token finds nothing left to claim.
+		result, err := tx.ExecContext(ctx, `UPDATE user_password_reset SET used_at = ? WHERE token_hash = ? AND used_at IS NULL`, now, hashToken(token))
+		if err != nil {
+			return fmt.Errorf("use password reset: %w", err)
+		}
+		if n, err := result.RowsAffected(); err != nil || n != 1 {
+			return model.ErrInvalidResetToken
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE user_password_reset SET used_at = ? WHERE user_id = ? AND used_at IS NULL`, now, userID); err != nil {
+			return fmt.Errorf("use password resets: %w", err)
+		}
+
+		// JWTs carry their issue time in seconds, so the cutoff is
+		// truncated to keep tokens issued right after the reset valid.
+		if _, err := tx.ExecContext(ctx, `UPDATE user_auth SET password = ?, updated_at = ?, password_changed_at = ?, tokens_revoked_at = ?, tokens_revoked_except = '' WHERE user_id = ?`, string(hashed), now, now, now.Truncate(time.Second), userID); err != nil {
+			return fmt.Errorf("update password: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_session WHERE user_id = ?`, userID); err != nil {
+			return fmt.Errorf("delete sessions: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_token WHERE user_id = ?`, userID); err != nil {
+			return fmt.Errorf("delete tokens: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Get(ctx, userID)
+}
@@ -0,0 +1,102 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/titpetric/platform/pkg/drivers"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/schema"
+	"github.com/titpetric/platform-app/user/storage"
+)
+
+func TestPasswordReset_integration(t *testing.T) {
+	ctx := t.Context()
+
+	db := NewTestDB(t)
+	require.NoError(t, storage.Migrate(ctx, db, schema.Migrations()))
+
+	users := storage.NewUserStorage(db)
+	sessions := storage.NewSessionStorage(db)
+	revoked := storage.NewRevokedTokenStorage(db)
+
+	user, err := users.Create(ctx, &model.UserCreateRequest{
+		FullName: "Reset Me",
+		Email:    "reset@titpetric.com",
+		Password: "horse battery staple",
+		Username: "resetme",
+	})
+	require.NoError(t, err)
+
+	t.Run("unknown email", func(t *testing.T) {
+		_, _, err := users.CreatePasswordReset(ctx, "nobody@titpetric.com", time.Hour)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := users.ResetPassword(ctx, "bogus", "new password")
+		require.ErrorIs(t, err, model.ErrInvalidResetToken)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		token, _, err := users.CreatePasswordReset(ctx, "reset@titpetric.com", -time.Minute)
+		require.NoError(t, err)
+
+		_, err = users.ResetPassword(ctx, token, "new password")
+		require.ErrorIs(t, err, model.ErrInvalidResetToken)
+	})
+
+	t.Run("reset is single-use and revokes sessions", func(t *testing.T) {
+		session, err := sessions.Create(ctx, &model.UserSession{UserID: user.ID})
+		require.NoError(t, err)
+
+		token, userID, err := users.CreatePasswordReset(ctx, "reset@titpetric.com", time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, userID)
+
+		other, _, err := users.CreatePasswordReset(ctx, "reset@titpetric.com", time.Hour)
+		require.NoError(t, err)
+
+		_, err = users.ResetPassword(ctx, token, "")
+		require.ErrorIs(t, err, model.ErrPasswordMissing)
+
+		// Checking the token doesn't use it up.
+		require.NoError(t, users.CheckPasswordReset(ctx, token))
+		require.ErrorIs(t, users.CheckPasswordReset(ctx, "bogus"), model.ErrInvalidResetToken)
+
+		issuedAt := time.Now().Add(-time.Minute)
+		reset, err := users.ResetPassword(ctx, token, "correct horse battery")
+		require.NoError(t, err)
+		require.Equal(t, user.ID, reset.ID)
+
+		_, err = users.ResetPassword(ctx, token, "again")
+		require.ErrorIs(t, err, model.ErrInvalidResetToken)
+		_, err = users.ResetPassword(ctx, other, "again")
+		require.ErrorIs(t, err, model.ErrInvalidResetToken)
+		require.ErrorIs(t, users.CheckPasswordReset(ctx, token), model.ErrInvalidResetToken)
+
+		_, err = sessions.Get(ctx, session.ID)
+		require.Error(t, err)
+
+		isRevoked, err := revoked.IsUserRevoked(ctx, user.ID, "", issuedAt)
+		require.NoError(t, err)
+		require.True(t, isRevoked)
+
+		isRevoked, err = revoked.IsUserRevoked(ctx, user.ID, "", time.Now().Add(time.Second))
+		require.NoError(t, err)
+		require.False(t, isRevoked)
+
+		authed, err := users.Authenticate(ctx, model.UserAuth{
+			Email:    "reset@titpetric.com",
+			Password: "correct horse battery",
+		})
+		require.NoError(t, err)
+		require.Equal(t, user.ID, authed.ID)
+	})
+}
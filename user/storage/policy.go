@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/titpetric/oida"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// GetPolicy returns the password expiry and email re-verification state
+// of the user: the user ID, email, created_at, password_changed_at,
+// email_verified_at and policy_exempt columns of their user_auth row.
+func (s *UserStorage) GetPolicy(ctx context.Context, userID string) (*model.UserAuth, error) {
+	ctx, span := oida.StartAuto(ctx, s.GetPolicy)
+	defer span.End()
+
+	result := &model.UserAuth{}
+	query := `SELECT user_id, email, created_at, password_changed_at, email_verified_at, policy_exempt FROM user_auth WHERE user_id=?`
+	if err := s.db.GetContext(ctx, result, query, userID); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ChangePassword sets a new password for the user, given their current
+// one, and restarts the password expiry period. Sessions and tokens of
+// the user stay valid.
+func (s *UserStorage) ChangePassword(ctx context.Context, userID, current, password string) error {
+	ctx, span := oida.StartAuto(ctx, s.ChangePassword)
+	defer span.End()
+
+	if password == "" {
+		return model.ErrPasswordMissing
+	}
+
+	var hashed string
+	if err := s.db.GetContext(ctx, &hashed, `SELECT password FROM user_auth WHERE user_id=?`, userID); err != nil {
+		return fmt.Errorf("change password lookup: %w", err)
+	}
+
+	_, span2 := oida.Start(ctx, "bcrypt.CompareHashAndPassword")
+	err := bcrypt.CompareHashAndPassword([]byte(hashed), []byte(current))
+	span2.End()
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return model.ErrPasswordIncorrect
+	}
+	if err != nil {
+		return fmt.Errorf("bcrypt compare: %w", err)
+	}
+
+	_, span3 := oida.Start(ctx, "bcrypt.GenerateFromPassword")
+	newHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	span3.End()
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	now := time.Now()
+	if _, err := s.db.ExecContext(ctx, `UPDATE user_auth SET password = ?, updated_at = ?, password_changed_at = ? WHERE user_id = ?`, string(newHash), now, now, userID); err != nil {
+		return fmt.Errorf("change password: %w", err)
+	}
+	return nil
+}
+
+// CreateEmailVerification issues an email re-verification token for the
+// user, valid for ttl, and returns the token and the email to send it
+// to. Only a hash of the token is stored, and issuing a new token
+// replaces the previous one.
+func (s *UserStorage) CreateEmailVerification(ctx context.Context, userID string, ttl time.Duration) (string, string, error) {
+	ctx, span := oida.StartAuto(ctx, s.CreateEmailVerification)
+	defer span.End()
+
+	email, err := s.GetEmail(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	token := newActivationToken()
+	if _, err := s.db.ExecContext(ctx, `UPDATE user_auth SET email_verify_token = ?, email_verify_expires_at = ? WHERE user_id = ?`, hashToken(token), time.Now().Add(ttl), userID); err != nil {
+		return "", "", fmt.Errorf("create email verification: %w", err)
+	}
+	return token, email, nil
+}
+
+// CheckEmailVerification returns model.ErrInvalidVerifyToken unless the
+// email re-verification token can be used. The token isn't used up.
+func (s *UserStorage) CheckEmailVerification(ctx context.Context, token string) error {
+	ctx, span := oida.StartAuto(ctx, s.CheckEmailVerification)
+	defer span.End()
+
+	_, err := s.emailVerificationUser(ctx, token)
+	return err
+}
+
+// emailVerificationUser returns the user a usable email re-verification
+// token was issued to.
+func (s *UserStorage) emailVerificationUser(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", model.ErrInvalidVerifyToken
+	}
+
+	var row struct {
+		UserID    string     `db:"user_id"`
+		ExpiresAt *time.Time `db:"email_verify_expires_at"`
+	}
+	err := s.db.GetContext(ctx, &row, `SELECT user_id, email_verify_expires_at FROM user_auth WHERE email_verify_token=? AND email_verify_token<>'' LIMIT 1`, hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", model.ErrInvalidVerifyToken
+	}
+	if err != nil {
+		return "", fmt.Errorf("verify email lookup: %w", err)
+	}
+
+	if row.ExpiresAt == nil || time.Now().After(*row.ExpiresAt) {
+		return "", model.ErrInvalidVerifyToken
+	}
+	return row.UserID, nil
+}
+
+// VerifyEmail exchanges an email re-verification token, and restarts the
+// email re-verification period of the user. The token is single-use.
+func (s *UserStorage) VerifyEmail(ctx context.Context, token string) (*model.User, error) {
+	ctx, span := oida.StartAuto(ctx, s.VerifyEmail)
+	defer span.End()
+
+	userID, err := s.emailVerificationUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	// Clearing the token by its hash means a concurrent verification
+	// with the same token finds nothing left to clear.
+	result, err := s.db.ExecContext(ctx, `UPDATE user_auth SET email_verified_at = ?, email_verify_token = '', email_verify_expires_at = NULL WHERE user_id = ? AND email_verify_token = ?`, now, userID, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("verify email: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil || n != 1 {
+		return nil, model.ErrInvalidVerifyToken
+	}
+
+	return s.Get(ctx, userID)
+}
+
+// SetPolicyExempt exempts the user from password expiry and email
+// re-verification, or clears the exemption. Errors with sql.ErrNoRows if
+// no such user exists.
+func (s *UserStorage) SetPolicyExempt(ctx context.Context, userID string, exempt bool) error {
+	ctx, span := oida.StartAuto(ctx, s.SetPolicyExempt)
+	defer span.End()
+
+	var value int64
+	if exempt {
+		value = 1
+	}
+	result, err := s.db.ExecContext(ctx, `UPDATE user_auth SET policy_exempt = ?, updated_at = ? WHERE user_id = ?`, value, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("set policy exempt: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
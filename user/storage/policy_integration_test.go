@@ -0,0 +1,91 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/titpetric/platform/pkg/drivers"
+
+	"github.com/titpetric/platform/pkg/require"
+
+	"github.com/titpetric/platform-app/user/model"
+	"github.com/titpetric/platform-app/user/schema"
+	"github.com/titpetric/platform-app/user/storage"
+)
+
+func TestPolicy_integration(t *testing.T) {
+	ctx := t.Context()
+
+	db := NewTestDB(t)
+	require.NoError(t, storage.Migrate(ctx, db, schema.Migrations()))
+
+	users := storage.NewUserStorage(db)
+
+	user, err := users.Create(ctx, &model.UserCreateRequest{
+		FullName: "Policy Me",
+		Email:    "policy@titpetric.com",
+		Password: "horse battery staple",
+		Username: "policyme",
+	})
+	require.NoError(t, err)
+
+	t.Run("new users start the expiry period", func(t *testing.T) {
+		auth, err := users.GetPolicy(ctx, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, auth.PasswordChangedAt)
+		require.Equal(t, int64(0), auth.PolicyExempt)
+	})
+
+	t.Run("change password", func(t *testing.T) {
+		require.ErrorIs(t, users.ChangePassword(ctx, user.ID, "wrong", "new password"), model.ErrPasswordIncorrect)
+		require.ErrorIs(t, users.ChangePassword(ctx, user.ID, "horse battery staple", ""), model.ErrPasswordMissing)
+		require.NoError(t, users.ChangePassword(ctx, user.ID, "horse battery staple", "correct horse battery"))
+
+		authed, err := users.Authenticate(ctx, model.UserAuth{
+			Email:    "policy@titpetric.com",
+			Password: "correct horse battery",
+		})
+		require.NoError(t, err)
+		require.Equal(t, user.ID, authed.ID)
+	})
+
+	t.Run("verify email", func(t *testing.T) {
+		_, err := users.VerifyEmail(ctx, "bogus")
+		require.ErrorIs(t, err, model.ErrInvalidVerifyToken)
+
+		expired, _, err := users.CreateEmailVerification(ctx, user.ID, -time.Minute)
+		require.NoError(t, err)
+		_, err = users.VerifyEmail(ctx, expired)
+		require.ErrorIs(t, err, model.ErrInvalidVerifyToken)
+		require.ErrorIs(t, users.CheckEmailVerification(ctx, expired), model.ErrInvalidVerifyToken)
+
+		token, email, err := users.CreateEmailVerification(ctx, user.ID, time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, "policy@titpetric.com", email)
+		require.NoError(t, users.CheckEmailVerification(ctx, token))
+
+		verified, err := users.VerifyEmail(ctx, token)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, verified.ID)
+
+		_, err = users.VerifyEmail(ctx, token)
+		require.ErrorIs(t, err, model.ErrInvalidVerifyToken)
+
+		auth, err := users.GetPolicy(ctx, user.ID)
+		require.NoError(t, err)
+		require.NotNil(t, auth.EmailVerifiedAt)
+	})
+
+	t.Run("exempt", func(t *testing.T) {
+		require.NoError(t, users.SetPolicyExempt(ctx, user.ID, true))
+		auth, err := users.GetPolicy(ctx, user.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), auth.PolicyExempt)
+
+		require.NoError(t, users.SetPolicyExempt(ctx, user.ID, false))
+		require.ErrorIs(t, users.SetPolicyExempt(ctx, "missing", true), sql.ErrNoRows)
+	})
+}
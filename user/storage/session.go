@@ -14,33 +14,48 @@ import (
 	"github.com/titpetric/platform-app/user/model"
 )
 
+// DefaultSessionTTL is how long a session stays valid without use.
+const DefaultSessionTTL = 24 * time.Hour
+
+// touchInterval limits how often Touch writes to a session in use.
+const touchInterval = time.Minute
+
 // SessionStorage implements session persistence using MySQL.
 type SessionStorage struct {
-	db *sqlx.DB
+	db  *sqlx.DB
+	ttl time.Duration
 }
 
 // NewSessionStorage creates a new SessionStorage.
 func NewSessionStorage(db *sqlx.DB) *SessionStorage {
 	return &SessionStorage{
-		db: db,
+		db:  db,
+		ttl: DefaultSessionTTL,
 	}
 }
 
-// Create inserts a new session for the given userID.
-func (s *SessionStorage) Create(ctx context.Context, userID string) (*model.UserSession, error) {
+// SetTTL sets how long new sessions stay valid without use. A zero ttl
+// keeps DefaultSessionTTL.
+func (s *SessionStorage) SetTTL(ttl time.Duration) {
+	if ttl > 0 {
+		s.ttl = ttl
+	}
+}
+
+// Create inserts a new session. The caller fills in the UserID, and
+// optionally the Name, UserAgent and IP of the session.
+func (s *SessionStorage) Create(ctx context.Context, session *model.UserSession) (*model.UserSession, error) {
 	ctx, span := oida.StartAuto(ctx, s.Create)
 	defer span.End()
 
 	now := time.Now()
-	session := &model.UserSession{
-		ID:     ulid.String(),
-		UserID: userID,
-	}
+	session.ID = ulid.String()
 	session.SetCreatedAt(now)
-	session.SetExpiresAt(now.Add(24 * time.Hour)) // default 24h expiration
+	session.SetLastSeenAt(now)
+	session.SetExpiresAt(now.Add(s.ttl))
 
-	query := `INSERT INTO user_session (id, user_id, expires_at, created_at) VALUES (?, ?, ?, ?)`
-	_, err := s.db.ExecContext(ctx, query, session.ID, session.UserID, session.ExpiresAt, session.CreatedAt)
+	query := `INSERT INTO user_session (id, user_id, name, user_agent, ip, expires_at, last_seen_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := s.db.ExecContext(ctx, query, session.ID, session.UserID, session.Name, session.UserAgent, session.IP, session.ExpiresAt, session.LastSeenAt, session.CreatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("create session: %w", err)
 	}
@@ -70,6 +85,94 @@ func (s *SessionStorage) Get(ctx context.Context, sessionID string) (*model.User
 	return session, nil
 }
 
+// Touch records the use of a session, and slides its expiry forward by
+// the TTL it was created with. Uses within a minute of the last recorded
+// one are not written.
+func (s *SessionStorage) Touch(ctx context.Context, session *model.UserSession) error {
+	ctx, span := oida.StartAuto(ctx, s.Touch)
+	defer span.End()
+
+	lastSeen := session.LastSeenAt
+	if lastSeen == nil {
+		lastSeen = session.CreatedAt
+	}
+	if lastSeen == nil || session.ExpiresAt == nil {
+		return nil
+	}
+
+	now := time.Now()
+	if now.Sub(*lastSeen) < touchInterval {
+		return nil
+	}
+
+	// The window between last use and expiry is the TTL in effect when
+	// the session was created, so changing the TTL leaves existing
+	// sessions as they are.
+	expiresAt := now.Add(session.ExpiresAt.Sub(*lastSeen))
+
+	query := `UPDATE user_session SET last_seen_at = ?, expires_at = ? WHERE id = ?`
+	if _, err := s.db.ExecContext(ctx, query, now, expiresAt, session.ID); err != nil {
+		return fmt.Errorf("touch session: %w", err)
+	}
+
+	session.SetLastSeenAt(now)
+	session.SetExpiresAt(expiresAt)
+	return nil
+}
+
+// List returns the unexpired sessions of the user, most recently used
+// first.
+func (s *SessionStorage) List(ctx context.Context, userID string) ([]model.UserSession, error) {
+	ctx, span := oida.StartAuto(ctx, s.List)
+	defer span.End()
+
+	result := []model.UserSession{}
+	query := `SELECT * FROM user_session WHERE user_id=? AND expires_at > ? ORDER BY last_seen_at DESC, created_at DESC`
+	if err := s.db.SelectContext(ctx, &result, query, userID, time.Now()); err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	return result, nil
+}
+
+// Rename sets the name of a session of the user. Errors with
+// sql.ErrNoRows if the user has no such session.
+func (s *SessionStorage) Rename(ctx context.Context, userID, sessionID, name string) error {
+	ctx, span := oida.StartAuto(ctx, s.Rename)
+	defer span.End()
+
+	result, err := s.db.ExecContext(ctx, `UPDATE user_session SET name = ? WHERE id = ? AND user_id = ?`, name, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("rename session: %w", err)
+	}
+	return requireRow(result)
+}
+
+// Revoke deletes a session of the user. Errors with sql.ErrNoRows if the
+// user has no such session.
+func (s *SessionStorage) Revoke(ctx context.Context, userID, sessionID string) error {
+	ctx, span := oida.StartAuto(ctx, s.Revoke)
+	defer span.End()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM user_session WHERE id = ? AND user_id = ?`, sessionID, userID)
+	if err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+	return requireRow(result)
+}
+
+// RevokeAll deletes all sessions of the user except the one with the
+// except ID, and returns how many were deleted.
+func (s *SessionStorage) RevokeAll(ctx context.Context, userID, except string) (int64, error) {
+	ctx, span := oida.StartAuto(ctx, s.RevokeAll)
+	defer span.End()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM user_session WHERE user_id = ? AND id <> ?`, userID, except)
+	if err != nil {
+		return 0, fmt.Errorf("revoke sessions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
 // Delete removes a session by sessionID.
 func (s *SessionStorage) Delete(ctx context.Context, sessionID string) error {
 	ctx, span := oida.StartAuto(ctx, s.Delete)
@@ -83,4 +186,16 @@ func (s *SessionStorage) Delete(ctx context.Context, sessionID string) error {
 	return nil
 }
 
+// requireRow errors with sql.ErrNoRows if the result affected no rows.
+func requireRow(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 var _ model.SessionStorage = (*SessionStorage)(nil)
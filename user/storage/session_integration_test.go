@@ -5,6 +5,7 @@ package storage_test
 import (
 	"database/sql"
 	"testing"
+	"time"
 
 	_ "github.com/titpetric/platform/pkg/drivers"
 
@@ -39,3 +40,96 @@ func TestNewSessionStorage_integration(t *testing.T) {
 		require.ErrorIs(t, err, sql.ErrNoRows)
 	}
 }
+
+func TestSessionManagement_integration(t *testing.T) {
+	ctx := t.Context()
+
+	db := NewTestDB(t)
+	require.NoError(t, storage.Migrate(ctx, db, schema.Migrations()))
+
+	s := storage.NewSessionStorage(db)
+	s.SetTTL(time.Hour)
+	tokens := storage.NewTokenStorage(db)
+	revoked := storage.NewRevokedTokenStorage(db)
+
+	first, err := s.Create(ctx, &model.UserSession{UserID: "user-1", UserAgent: "curl/8.0", IP: "127.0.0.1"})
+	require.NoError(t, err)
+	second, err := s.Create(ctx, &model.UserSession{UserID: "user-1"})
+	require.NoError(t, err)
+	_, err = s.Create(ctx, &model.UserSession{UserID: "user-2"})
+	require.NoError(t, err)
+
+	t.Run("sliding expiry", func(t *testing.T) {
+		session, err := s.Get(ctx, first.ID)
+		require.NoError(t, err)
+		require.Equal(t, "curl/8.0", session.UserAgent)
+
+		// Pretend the session was last used ten minutes ago.
+		lastSeen := time.Now().Add(-10 * time.Minute)
+		session.SetLastSeenAt(lastSeen)
+		session.SetExpiresAt(lastSeen.Add(time.Hour))
+		require.NoError(t, s.Touch(ctx, session))
+		require.True(t, session.ExpiresAt.After(time.Now().Add(59*time.Minute)))
+
+		stored, err := s.Get(ctx, first.ID)
+		require.NoError(t, err)
+		require.Equal(t, session.ExpiresAt.Unix(), stored.ExpiresAt.Unix())
+	})
+
+	t.Run("list, rename and revoke sessions", func(t *testing.T) {
+		list, err := s.List(ctx, "user-1")
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+
+		require.NoError(t, s.Rename(ctx, "user-1", first.ID, "laptop"))
+		require.ErrorIs(t, s.Rename(ctx, "user-2", first.ID, "stolen"), sql.ErrNoRows)
+		require.ErrorIs(t, s.Revoke(ctx, "user-2", first.ID), sql.ErrNoRows)
+
+		n, err := s.RevokeAll(ctx, "user-1", first.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		_, err = s.Get(ctx, second.ID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		session, err := s.Get(ctx, first.ID)
+		require.NoError(t, err)
+		require.Equal(t, "laptop", session.Name)
+
+		require.NoError(t, s.Revoke(ctx, "user-1", first.ID))
+	})
+
+	t.Run("list, rename and revoke tokens", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+		for _, jti := range []string{"jti-1", "jti-2", "jti-3"} {
+			token := &model.UserToken{Jti: jti, UserID: "user-1"}
+			token.SetExpiresAt(expiresAt)
+			_, err := tokens.Create(ctx, token)
+			require.NoError(t, err)
+		}
+		require.NoError(t, tokens.Touch(ctx, "jti-1"))
+
+		list, err := tokens.List(ctx, "user-1")
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+
+		require.NoError(t, tokens.Rename(ctx, "user-1", "jti-1", "cli"))
+		require.ErrorIs(t, tokens.Rename(ctx, "user-2", "jti-1", "stolen"), sql.ErrNoRows)
+
+		require.NoError(t, tokens.Revoke(ctx, "user-1", "jti-2"))
+		require.ErrorIs(t, tokens.Revoke(ctx, "user-1", "jti-2"), sql.ErrNoRows)
+
+		isRevoked, err := revoked.IsRevoked(ctx, "jti-2")
+		require.NoError(t, err)
+		require.True(t, isRevoked)
+
+		n, err := tokens.RevokeAll(ctx, "user-1", "jti-1")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		list, err = tokens.List(ctx, "user-1")
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, "cli", list[0].Name)
+	})
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/titpetric/oida"
+	"github.com/titpetric/platform"
+
+	"github.com/titpetric/platform-app/user/model"
+)
+
+// TokenStorage records the JWTs issued to users by their jti, so users
+// can list and revoke them. Tokens that aren't recorded, like ones
+// issued before it existed, still validate.
+type TokenStorage struct {
+	db *sqlx.DB
+}
+
+// NewTokenStorage returns a new TokenStorage.
+func NewTokenStorage(db *sqlx.DB) *TokenStorage {
+	return &TokenStorage{db: db}
+}
+
+// Create records an issued token. The caller fills in the JTI, UserID
+// and ExpiresAt of the token, and optionally its Name, UserAgent and IP.
+func (s *TokenStorage) Create(ctx context.Context, token *model.UserToken) (*model.UserToken, error) {
+	if s == nil || s.db == nil {
+		return token, nil
+	}
+	ctx, span := oida.StartAuto(ctx, s.Create)
+	defer span.End()
+
+	if token.Jti == "" {
+		return nil, errors.New("create token: empty jti")
+	}
+
+	now := time.Now()
+	token.SetCreatedAt(now)
+	token.SetLastSeenAt(now)
+
+	query := `INSERT INTO user_token (jti, user_id, name, user_agent, ip, expires_at, last_seen_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := s.db.ExecContext(ctx, query, token.Jti, token.UserID, token.Name, token.UserAgent, token.IP, token.ExpiresAt, token.LastSeenAt, token.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("create token: %w", err)
+	}
+	return token, nil
+}
+
+// Touch records the use of a token. Uses within a minute of the last
+// recorded one are not written.
+func (s *TokenStorage) Touch(ctx context.Context, jti string) error {
+	if s == nil || s.db == nil || jti == "" {
+		return nil
+	}
+	ctx, span := oida.StartAuto(ctx, s.Touch)
+	defer span.End()
+
+	now := time.Now()
+	query := `UPDATE user_token SET last_seen_at = ? WHERE jti = ? AND (last_seen_at IS NULL OR last_seen_at < ?)`
+	if _, err := s.db.ExecContext(ctx, query, now, jti, now.Add(-touchInterval)); err != nil {
+		return fmt.Errorf("touch token: %w", err)
+	}
+	return nil
+}
+
+// List returns the unexpired, unrevoked tokens of the user, most recently
+// used first.
+func (s *TokenStorage) List(ctx context.Context, userID string) ([]model.UserToken, error) {
+	result := []model.UserToken{}
+	if s == nil || s.db == nil {
+		return result, nil
+	}
+	ctx, span := oida.StartAuto(ctx, s.List)
+	defer span.End()
+
+	query := `SELECT * FROM user_token WHERE user_id=? AND expires_at > ? AND jti NOT IN (SELECT jti FROM user_token_revoked) ORDER BY last_seen_at DESC, created_at DESC`
+	if err := s.db.SelectContext(ctx, &result, query, userID, time.Now()); err != nil {
+		return nil, fmt.Errorf("list tokens: %w", err)
+	}
+	return result, nil
+}
+
+// Rename sets the name of a token of the user. Errors with sql.ErrNoRows
+// if the user has no such token.
+func (s *TokenStorage) Rename(ctx context.Context, userID, jti, name string) error {
+	if s == nil || s.db == nil {
+		return sql.ErrNoRows
+	}
+	ctx, span := oida.StartAuto(ctx, s.Rename)
+	defer span.End()
+
+	result, err := s.db.ExecContext(ctx, `UPDATE user_token SET name = ? WHERE jti = ? AND user_id = ?`, name, jti, userID)
+	if err != nil {
+		return fmt.Errorf("rename token: %w", err)
+	}
+	return requireRow(result)
+}
+
+// Revoke revokes a token of the user, see RevokedTokenStorage, and
+// removes it from the list. Errors with sql.ErrNoRows if the user has no
+// such token.
+func (s *TokenStorage) Revoke(ctx context.Context, userID, jti string) error {
+	if s == nil || s.db == nil {
+		return sql.ErrNoRows
+	}
+	ctx, span := oida.StartAuto(ctx, s.Revoke)
+	defer span.End()
+
+	return platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		n, err := revokeTokens(ctx, tx, `SELECT * FROM user_token WHERE user_id = ? AND jti = ?`, userID, jti)
+		if err == nil && n == 0 {
+			return sql.ErrNoRows
+		}
+		return err
+	})
+}
+
+// RevokeAll revokes all tokens of the user except the one with the except
+// jti, and returns how many were revoked. It also sets the revocation
+// cutoff of the user, so tokens issued before it are revoked even if
+// they aren't listed, see RevokedTokenStorage.IsUserRevoked.
+func (s *TokenStorage) RevokeAll(ctx context.Context, userID, except string) (int64, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+	ctx, span := oida.StartAuto(ctx, s.RevokeAll)
+	defer span.End()
+
+	var n int64
+	err := platform.Transaction(ctx, s.db, func(ctx context.Context, tx *sqlx.Tx) error {
+		var err error
+		n, err = revokeTokens(ctx, tx, `SELECT * FROM user_token WHERE user_id = ? AND jti <> ?`, userID, except)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE user_auth SET tokens_revoked_at = ?, tokens_revoked_except = ? WHERE user_id = ?`, time.Now().Truncate(time.Second), except, userID); err != nil {
+			return fmt.Errorf("revoke user tokens: %w", err)
+		}
+		return nil
+	})
+	return n, err
+}
+
+// revokeTokens adds the tokens selected by query to user_token_revoked,
+// deletes their user_token rows, and returns how many there were.
+func revokeTokens(ctx context.Context, tx *sqlx.Tx, query string, args ...any) (int64, error) {
+	tokens := []model.UserToken{}
+	if err := tx.SelectContext(ctx, &tokens, query, args...); err != nil {
+		return 0, fmt.Errorf("revoke token lookup: %w", err)
+	}
+
+	now := time.Now()
+	for _, token := range tokens {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO user_token_revoked (jti, user_id, expires_at, created_at) VALUES (?, ?, ?, ?)`, token.Jti, token.UserID, token.ExpiresAt, now); err != nil {
+			return 0, fmt.Errorf("revoke token: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_token WHERE jti = ?`, token.Jti); err != nil {
+			return 0, fmt.Errorf("delete token: %w", err)
+		}
+	}
+	return int64(len(tokens)), nil
+}
+
+var _ model.TokenStorage = (*TokenStorage)(nil)
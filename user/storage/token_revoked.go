@@ -82,3 +82,32 @@ func (s *RevokedTokenStorage) PurgeExpired(ctx context.Context) (int64, error) {
 	n, _ := res.RowsAffected()
 	return n, nil
 }
+
+// IsUserRevoked reports whether a JWT of the user issued at issuedAt was
+// revoked in bulk, as happens when the user resets their password or
+// revokes all their tokens. Tokens issued before the user's
+// tokens_revoked_at cutoff are revoked, except the one with the
+// tokens_revoked_except jti.
+func (s *RevokedTokenStorage) IsUserRevoked(ctx context.Context, userID, jti string, issuedAt time.Time) (bool, error) {
+	if s == nil || s.db == nil || userID == "" {
+		return false, nil
+	}
+	ctx, span := oida.StartAuto(ctx, s.IsUserRevoked)
+	defer span.End()
+
+	var row struct {
+		Cutoff sql.NullTime `db:"tokens_revoked_at"`
+		Except string       `db:"tokens_revoked_except"`
+	}
+	err := s.db.GetContext(ctx, &row, `SELECT tokens_revoked_at, tokens_revoked_except FROM user_auth WHERE user_id=? LIMIT 1`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("is user revoked: %w", err)
+	}
+	if row.Except != "" && row.Except == jti {
+		return false, nil
+	}
+	return row.Cutoff.Valid && issuedAt.Before(row.Cutoff.Time), nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/titpetric/platform/pkg/require"
 
+	"github.com/titpetric/platform-app/user/model"
 	"github.com/titpetric/platform-app/user/schema"
 	"github.com/titpetric/platform-app/user/storage"
 )
@@ -72,3 +73,59 @@ func TestRevokedTokenStorage_integration(t *testing.T) {
 		require.False(t, stillRevoked)
 	})
 }
+
+func TestRevokeAllCutoff_integration(t *testing.T) {
+	ctx := t.Context()
+
+	db := NewTestDB(t)
+	require.NoError(t, storage.Migrate(ctx, db, schema.Migrations()))
+
+	users := storage.NewUserStorage(db)
+	tokens := storage.NewTokenStorage(db)
+	revoked := storage.NewRevokedTokenStorage(db)
+
+	user, err := users.Create(ctx, &model.UserCreateRequest{
+		FullName: "Revoke Me",
+		Email:    "revoke@titpetric.com",
+		Password: "horse battery staple",
+		Username: "revokeme",
+	})
+	require.NoError(t, err)
+
+	issuedAt := time.Now().Add(-time.Minute)
+	_, err = tokens.RevokeAll(ctx, user.ID, "jti-current")
+	require.NoError(t, err)
+
+	t.Run("unlisted tokens are revoked", func(t *testing.T) {
+		isRevoked, err := revoked.IsUserRevoked(ctx, user.ID, "jti-unlisted", issuedAt)
+		require.NoError(t, err)
+		require.True(t, isRevoked)
+
+		isRevoked, err = revoked.IsUserRevoked(ctx, user.ID, "", issuedAt)
+		require.NoError(t, err)
+		require.True(t, isRevoked)
+	})
+
+	t.Run("current token is kept", func(t *testing.T) {
+		isRevoked, err := revoked.IsUserRevoked(ctx, user.ID, "jti-current", issuedAt)
+		require.NoError(t, err)
+		require.False(t, isRevoked)
+	})
+
+	t.Run("later tokens are valid", func(t *testing.T) {
+		isRevoked, err := revoked.IsUserRevoked(ctx, user.ID, "jti-later", time.Now().Add(time.Second))
+		require.NoError(t, err)
+		require.False(t, isRevoked)
+	})
+
+	t.Run("password reset revokes the current token", func(t *testing.T) {
+		token, _, err := users.CreatePasswordReset(ctx, "revoke@titpetric.com", time.Hour)
+		require.NoError(t, err)
+		_, err = users.ResetPassword(ctx, token, "correct horse battery")
+		require.NoError(t, err)
+
+		isRevoked, err := revoked.IsUserRevoked(ctx, user.ID, "jti-current", issuedAt)
+		require.NoError(t, err)
+		require.True(t, isRevoked)
+	})
+}
@@ -177,6 +177,7 @@ func (s *UserStorage) insertUserAndAuth(ctx context.Context, req *model.UserCrea
 		userAuth.Password = hashedPassword
 		userAuth.SetCreatedAt(now)
 		userAuth.SetUpdatedAt(now)
+		userAuth.SetPasswordChangedAt(now)
 
 		if _, err := tx.NamedExecContext(ctx, userAuth.Insert(), userAuth); err != nil {
 			return fmt.Errorf("create user_auth: %w", err)
@@ -206,10 +207,10 @@ func (s *UserStorage) Update(ctx context.Context, u *model.User) (*model.User, e
 
 	u.SetUpdatedAt(time.Now())
 
-	query := `UPDATE user SET full_name=?, deleted_at=?, updated_at=? WHERE id=?`
+	query := `UPDATE user SET full_name=?, timezone=?, deleted_at=?, updated_at=? WHERE id=?`
 
 	_, err := s.db.ExecContext(ctx, query,
-		u.FullName, u.DeletedAt, u.UpdatedAt, u.ID,
+		u.FullName, u.Timezone, u.DeletedAt, u.UpdatedAt, u.ID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("update user: %w", err)
@@ -257,6 +258,19 @@ func (s *UserStorage) GetByStub(ctx context.Context, slug string) (*model.User,
 	return u, nil
 }
 
+// GetEmail returns the email address of a user.
+func (s *UserStorage) GetEmail(ctx context.Context, userID string) (string, error) {
+	ctx, span := oida.StartAuto(ctx, s.GetEmail)
+	defer span.End()
+
+	var email string
+	query := `SELECT email FROM user_auth WHERE user_id=?`
+	if err := s.db.GetContext(ctx, &email, query, userID); err != nil {
+		return "", fmt.Errorf("get email user_id=%s: %w", userID, err)
+	}
+	return email, nil
+}
+
 // GetGroups returns all groups the user belongs to.
 func (s *UserStorage) GetGroups(ctx context.Context, userID string) ([]model.UserGroup, error) {
 	ctx, span := oida.StartAuto(ctx, s.GetGroups)
@@ -265,7 +279,7 @@ func (s *UserStorage) GetGroups(ctx context.Context, userID string) ([]model.Use
 	query := `
 		SELECT g.id, g.title, g.created_at, g.updated_at
 		FROM user_group g
-		JOIN user_group_member m ON m.group_id = g.id
+		JOIN user_group_member m ON m.user_group_id = g.id
 		WHERE m.user_id = ?
 	`
 	var groups []model.UserGroup
@@ -366,8 +380,10 @@ func (s *UserStorage) Activate(ctx context.Context, token string) (*model.User,
 
 	// If the user is somehow already activated (race or stale token),
 	// still clear the token so it can't be reused, and return success
-	// — the desired end state is "activated, token gone".
-	if _, err := s.db.ExecContext(ctx, `UPDATE user_auth SET activated_at = COALESCE(activated_at, ?), activation_token = '' WHERE user_id = ?`, time.Now(), row.UserID); err != nil {
+	// — the desired end state is "activated, token gone". Activating
+	// also verifies the email, see VerifyEmail.
+	now := time.Now()
+	if _, err := s.db.ExecContext(ctx, `UPDATE user_auth SET activated_at = COALESCE(activated_at, ?), email_verified_at = ?, activation_token = '' WHERE user_id = ?`, now, now, row.UserID); err != nil {
 		return nil, fmt.Errorf("activate: %w", err)
 	}
 
@@ -4,16 +4,47 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/titpetric/platform-app/user/model"
 	"github.com/titpetric/platform-app/user/service"
 )
 
-// NewModule will return the user module.
-func NewModule() *service.UserModule {
-	return service.NewUserModule(service.Options{
-		SigningKey: SigningKey(),
-	})
+// ModuleOption configures the user module.
+type ModuleOption func(*service.Options)
+
+// NewModule will return the user module. The password reset and email
+// verification links are taken from USER_PASSWORD_RESET_URL and
+// USER_EMAIL_VERIFY_URL, format strings for the token. Password and
+// email policies are enforced when USER_POLICIES_ENABLED is set, see
+// PoliciesEnabled.
+func NewModule(opts ...ModuleOption) *service.UserModule {
+	options := service.Options{
+		SigningKey:             SigningKey(),
+		PasswordResetURLFormat: os.Getenv("USER_PASSWORD_RESET_URL"),
+		EmailVerifyURLFormat:   os.Getenv("USER_EMAIL_VERIFY_URL"),
+		PoliciesEnabled:        PoliciesEnabled(),
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return service.NewUserModule(options)
+}
+
+// WithEmailSender sets the sender of transactional mail, usually the
+// email module. It enables the forgotten password flow.
+func WithEmailSender(sender service.EmailSender) ModuleOption {
+	return func(o *service.Options) {
+		o.EmailSender = sender
+	}
+}
+
+// WithSessionTTL sets how long sessions stay valid without use.
+func WithSessionTTL(ttl time.Duration) ModuleOption {
+	return func(o *service.Options) {
+		o.SessionTTL = ttl
+	}
 }
 
 // MiddlewareOption configures the user authentication middleware.
@@ -48,6 +79,14 @@ func SigningKey() string {
 	return "test-usage"
 }
 
+// PoliciesEnabled reports whether the password expiry and email
+// re-verification policies are enforced, by the user module and the
+// middleware. They are off unless USER_POLICIES_ENABLED is true.
+func PoliciesEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("USER_POLICIES_ENABLED"))
+	return enabled
+}
+
 // AuthCookie enables session-based authentication via a cookie.
 func AuthCookie() MiddlewareOption {
 	return func(mw *Middleware) {
@@ -0,0 +1,18 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/titpetric/platform/pkg/require"
+)
+
+func TestPoliciesEnabled(t *testing.T) {
+	t.Setenv("USER_POLICIES_ENABLED", "")
+	require.False(t, PoliciesEnabled())
+
+	t.Setenv("USER_POLICIES_ENABLED", "true")
+	require.True(t, PoliciesEnabled())
+
+	t.Setenv("USER_POLICIES_ENABLED", "no")
+	require.False(t, PoliciesEnabled())
+}